@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/ancla/chrysom"
+	"go.uber.org/zap"
+)
+
+const defaultRefreshDebounce = 100 * time.Millisecond
+
+// addRefresher refreshes the listener shortly after webhooks are added through
+// the service, so its watches see them before the next scheduled poll. Adds
+// made while a refresh is scheduled share it, and adds made while one is
+// running, such as by a watch it updates, are left to the next scheduled poll
+// so that they can't trigger refreshes in a loop.
+type addRefresher struct {
+	mu         sync.Mutex
+	refresh    func(context.Context) error
+	debounce   time.Duration
+	logger     *zap.Logger
+	pending    bool
+	refreshing bool
+}
+
+// start makes adds refresh the listener with the refresh func after the
+// debounce, or the default when it's 0.
+func (r *addRefresher) start(refresh func(context.Context) error, debounce time.Duration, logger *zap.Logger) {
+	if debounce <= 0 {
+		debounce = defaultRefreshDebounce
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refresh = refresh
+	r.debounce = debounce
+	r.logger = logger
+}
+
+// stop makes adds no longer refresh the listener.
+func (r *addRefresher) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refresh = nil
+}
+
+// trigger schedules a refresh unless one is already scheduled or running.
+func (r *addRefresher) trigger() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.refresh == nil || r.pending || r.refreshing {
+		return
+	}
+	r.pending = true
+	time.AfterFunc(r.debounce, r.run)
+}
+
+func (r *addRefresher) run() {
+	r.mu.Lock()
+	refresh, logger := r.refresh, r.logger
+	r.pending = false
+	if refresh == nil {
+		r.mu.Unlock()
+		return
+	}
+	r.refreshing = true
+	r.mu.Unlock()
+
+	err := refresh(context.Background())
+
+	r.mu.Lock()
+	r.refreshing = false
+	r.mu.Unlock()
+	if err != nil && !errors.Is(err, chrysom.ErrListenerNotRunning) {
+		logger.Warn("Failed to refresh the webhooks after an add", zap.Error(err))
+	}
+}
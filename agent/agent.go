@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/ancla"
+	"go.uber.org/zap"
+)
+
+// Errors that can be returned by this package.
+var (
+	ErrNoRegistrar         = errors.New("no registrar provided")
+	ErrInvalidDuration     = errors.New("webhook duration must be positive")
+	ErrInvalidRenewBefore  = errors.New("renew before must be non-negative and less than the webhook duration")
+	ErrAgentNotStopped     = errors.New("agent is already running")
+	ErrAgentNotRunning     = errors.New("agent is not running")
+	ErrNotRegistered       = errors.New("webhook has not been registered yet")
+	ErrPermanentFailure    = errors.New("giving up on webhook registration")
+	errRegistrationTimeout = errors.New("registration attempt timed out")
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+)
+
+// Registrar registers a webhook on behalf of an owner. ancla.Service satisfies
+// this interface, as does the HTTP based registrar returned by NewHTTPRegistrar.
+type Registrar interface {
+	Add(ctx context.Context, owner string, iw ancla.InternalWebhook) error
+}
+
+// RegistrarFunc allows bare functions to pass as Registrars.
+type RegistrarFunc func(context.Context, string, ancla.InternalWebhook) error
+
+func (f RegistrarFunc) Add(ctx context.Context, owner string, iw ancla.InternalWebhook) error {
+	return f(ctx, owner, iw)
+}
+
+// Config contains the information needed to keep a webhook registration alive.
+type Config struct {
+	// Owner is the owner the webhook is registered under.
+	Owner string
+
+	// Webhook is the registration to keep alive. Its Webhook.Duration is required
+	// and each renewal registers the webhook until now plus that duration.
+	Webhook ancla.InternalWebhook
+
+	// RenewBefore is how long before the registration expires it is renewed.
+	// (Optional). Defaults to 0, which is rarely what you want.
+	RenewBefore time.Duration
+
+	// Jitter is the maximum random amount of time subtracted from each renewal
+	// delay, so that many instances of a service don't renew in lockstep.
+	// (Optional). Defaults to no jitter.
+	Jitter time.Duration
+
+	// InitialBackoff is the delay before retrying a failed registration. It doubles
+	// after each consecutive failure up to MaxBackoff.
+	// (Optional). Defaults to 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries of failed registrations.
+	// (Optional). Defaults to 1 minute.
+	MaxBackoff time.Duration
+
+	// AttemptTimeout bounds each registration attempt.
+	// (Optional). Defaults to no timeout beyond the agent's lifetime.
+	AttemptTimeout time.Duration
+
+	// MaxFailures is the number of consecutive failed registrations after which the
+	// agent gives up and calls OnPermanentFailure.
+	// (Optional). Defaults to retrying forever.
+	MaxFailures int
+
+	// OnPermanentFailure is called once when the agent gives up.
+	// (Optional).
+	OnPermanentFailure func(error)
+
+	// Logger to be used by the agent.
+	// (Optional). Defaults to a no op logger.
+	Logger *zap.Logger
+
+	// Now and After provide the agent's clock.
+	// (Optional). Default to time.Now and time.After.
+	Now   func() time.Time
+	After func(time.Duration) <-chan time.Time
+}
+
+// Agent renews a webhook registration before it expires.
+type Agent struct {
+	registrar Registrar
+	config    Config
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+	lastErr  error
+	until    time.Time
+	failures int
+}
+
+// New creates an Agent that keeps the configured webhook registered through r.
+func New(r Registrar, config Config) (*Agent, error) {
+	if r == nil {
+		return nil, ErrNoRegistrar
+	}
+	if config.Webhook.Webhook.Duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+	if config.RenewBefore < 0 || config.RenewBefore >= config.Webhook.Webhook.Duration {
+		return nil, ErrInvalidRenewBefore
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	if config.After == nil {
+		config.After = time.After
+	}
+
+	return &Agent{
+		registrar: r,
+		config:    config,
+		lastErr:   ErrNotRegistered,
+	}, nil
+}
+
+// Start registers the webhook right away and then keeps renewing it in the
+// background until Stop is called or the agent gives up.
+func (a *Agent) Start(_ context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		return ErrAgentNotStopped
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.done = make(chan struct{})
+	a.failures = 0
+	go a.run(ctx, a.done)
+	return nil
+}
+
+// Stop stops renewing the registration and waits for the background goroutine
+// to exit. The registration itself is left to expire.
+func (a *Agent) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	cancel, done := a.cancel, a.done
+	a.cancel, a.done = nil, nil
+	a.mu.Unlock()
+	if cancel == nil {
+		return ErrAgentNotRunning
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Healthy returns nil when the last registration attempt succeeded and the
+// registration hasn't expired. Otherwise it returns the reason it is unhealthy.
+func (a *Agent) Healthy() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lastErr != nil {
+		return a.lastErr
+	}
+	if !a.config.Now().Before(a.until) {
+		return ErrNotRegistered
+	}
+	return nil
+}
+
+// Until returns the expiration time of the latest successful registration.
+func (a *Agent) Until() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.until
+}
+
+func (a *Agent) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for {
+		delay, ok := a.register(ctx)
+		if !ok {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.config.After(delay):
+		}
+	}
+}
+
+// register makes one registration attempt and returns how long to wait before
+// the next one, or false if the agent should stop.
+func (a *Agent) register(ctx context.Context) (time.Duration, bool) {
+	iw := a.config.Webhook
+	now := a.config.Now()
+	iw.Webhook.Until = now.Add(iw.Webhook.Duration)
+
+	attemptCtx := ctx
+	if a.config.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeoutCause(ctx, a.config.AttemptTimeout, errRegistrationTimeout)
+		defer cancel()
+	}
+	err := a.registrar.Add(attemptCtx, a.config.Owner, iw)
+	if ctx.Err() != nil {
+		return 0, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err == nil {
+		a.lastErr = nil
+		a.failures = 0
+		a.until = iw.Webhook.Until
+		a.config.Logger.Debug("Renewed webhook registration", zap.Time("until", a.until))
+		return a.renewalDelay(now), true
+	}
+
+	a.lastErr = err
+	a.failures++
+	a.config.Logger.Error("Failed to renew webhook registration", zap.Int("failures", a.failures), zap.Error(err))
+	if a.config.MaxFailures > 0 && a.failures >= a.config.MaxFailures {
+		a.lastErr = errors.Join(ErrPermanentFailure, err)
+		if a.config.OnPermanentFailure != nil {
+			a.config.OnPermanentFailure(a.lastErr)
+		}
+		return 0, false
+	}
+	return a.backoff(), true
+}
+
+func (a *Agent) renewalDelay(now time.Time) time.Duration {
+	delay := a.until.Add(-a.config.RenewBefore).Sub(now)
+	if a.config.Jitter > 0 {
+		// nolint:gosec
+		delay -= time.Duration(rand.Int63n(int64(a.config.Jitter)))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+func (a *Agent) backoff() time.Duration {
+	backoff := a.config.InitialBackoff
+	for i := 1; i < a.failures && backoff < a.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > a.config.MaxBackoff {
+		backoff = a.config.MaxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla"
+	"github.com/xmidt-org/ancla/auth"
+	"github.com/xmidt-org/ancla/client"
+)
+
+var errRegistrar = errors.New("registrar failure")
+
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits chan time.Duration
+	fire  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:   time.Date(2021, time.January, 2, 15, 4, 0, 0, time.UTC),
+		waits: make(chan time.Duration, 10),
+		fire:  make(chan time.Time),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits <- d
+	return c.fire
+}
+
+// advance waits for the agent to schedule its next attempt, moves the clock
+// forward by the scheduled delay and fires the timer.
+func (c *fakeClock) advance(t *testing.T) time.Duration {
+	select {
+	case d := <-c.waits:
+		c.mu.Lock()
+		c.now = c.now.Add(d)
+		c.mu.Unlock()
+		c.fire <- c.now
+		return d
+	case <-time.After(time.Second):
+		require.FailNow(t, "agent never scheduled its next attempt")
+	}
+	return 0
+}
+
+type registration struct {
+	owner string
+	iw    ancla.InternalWebhook
+}
+
+func fakeRegistrar(results ...error) (RegistrarFunc, chan registration) {
+	var (
+		mu    sync.Mutex
+		calls = make(chan registration, 10)
+	)
+	return func(_ context.Context, owner string, iw ancla.InternalWebhook) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls <- registration{owner: owner, iw: iw}
+		if len(results) == 0 {
+			return nil
+		}
+		err := results[0]
+		results = results[1:]
+		return err
+	}, calls
+}
+
+func receive(t *testing.T, calls chan registration) registration {
+	select {
+	case r := <-calls:
+		return r
+	case <-time.After(time.Second):
+		require.FailNow(t, "registrar wasn't called")
+	}
+	return registration{}
+}
+
+func testConfig(clock *fakeClock) Config {
+	return Config{
+		Owner: "owner",
+		Webhook: ancla.InternalWebhook{
+			Webhook: ancla.Webhook{
+				Config: ancla.DeliveryConfig{
+					URL: "https://deliver-here.example.net",
+				},
+				Events:   []string{"online"},
+				Duration: 10 * time.Minute,
+			},
+		},
+		RenewBefore: time.Minute,
+		Now:         clock.Now,
+		After:       clock.After,
+	}
+}
+
+func TestNew(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		registrar   Registrar
+		config      Config
+		expectedErr error
+	}{
+		{
+			desc:        "No registrar Failure",
+			config:      testConfig(newFakeClock()),
+			expectedErr: ErrNoRegistrar,
+		},
+		{
+			desc:        "No duration Failure",
+			registrar:   RegistrarFunc(nil),
+			config:      Config{},
+			expectedErr: ErrInvalidDuration,
+		},
+		{
+			desc:      "Renew before too large Failure",
+			registrar: RegistrarFunc(nil),
+			config: func() Config {
+				c := testConfig(newFakeClock())
+				c.RenewBefore = c.Webhook.Webhook.Duration
+				return c
+			}(),
+			expectedErr: ErrInvalidRenewBefore,
+		},
+		{
+			desc:      "Success",
+			registrar: RegistrarFunc(nil),
+			config:    testConfig(newFakeClock()),
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			a, err := New(tc.registrar, tc.config)
+			if tc.expectedErr != nil {
+				assert.True(errors.Is(err, tc.expectedErr))
+				assert.Nil(a)
+				return
+			}
+			assert.NoError(err)
+			assert.NotNil(a)
+		})
+	}
+}
+
+func TestAgentRenewal(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	clock := newFakeClock()
+	start := clock.Now()
+	r, calls := fakeRegistrar()
+	a, err := New(r, testConfig(clock))
+	require.NoError(err)
+	assert.ErrorIs(a.Healthy(), ErrNotRegistered)
+
+	require.NoError(a.Start(context.Background()))
+	assert.ErrorIs(a.Start(context.Background()), ErrAgentNotStopped)
+
+	first := receive(t, calls)
+	assert.Equal("owner", first.owner)
+	assert.Equal(start.Add(10*time.Minute), first.iw.Webhook.Until)
+
+	assert.Equal(9*time.Minute, clock.advance(t))
+	second := receive(t, calls)
+	assert.Equal(start.Add(19*time.Minute), second.iw.Webhook.Until)
+	assert.NoError(a.Healthy())
+	assert.Equal(second.iw.Webhook.Until, a.Until())
+
+	require.NoError(a.Stop(context.Background()))
+	assert.ErrorIs(a.Stop(context.Background()), ErrAgentNotRunning)
+}
+
+func TestAgentBackoff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	clock := newFakeClock()
+	r, calls := fakeRegistrar(errRegistrar, errRegistrar, errRegistrar)
+	config := testConfig(clock)
+	config.MaxBackoff = 3 * time.Second
+	a, err := New(r, config)
+	require.NoError(err)
+	require.NoError(a.Start(context.Background()))
+	defer a.Stop(context.Background())
+
+	receive(t, calls)
+	assert.Equal(time.Second, clock.advance(t))
+	assert.ErrorIs(a.Healthy(), errRegistrar)
+
+	receive(t, calls)
+	assert.Equal(2*time.Second, clock.advance(t))
+
+	receive(t, calls)
+	assert.Equal(3*time.Second, clock.advance(t))
+
+	receive(t, calls)
+	assert.Equal(9*time.Minute, clock.advance(t))
+	assert.NoError(a.Healthy())
+}
+
+func TestAgentPermanentFailure(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	clock := newFakeClock()
+	r, calls := fakeRegistrar(errRegistrar, errRegistrar)
+	permanent := make(chan error, 1)
+	config := testConfig(clock)
+	config.MaxFailures = 2
+	config.OnPermanentFailure = func(err error) {
+		permanent <- err
+	}
+	a, err := New(r, config)
+	require.NoError(err)
+	require.NoError(a.Start(context.Background()))
+
+	receive(t, calls)
+	clock.advance(t)
+	receive(t, calls)
+
+	select {
+	case err := <-permanent:
+		assert.ErrorIs(err, ErrPermanentFailure)
+		assert.ErrorIs(err, errRegistrar)
+	case <-time.After(time.Second):
+		require.FailNow("permanent failure callback wasn't called")
+	}
+	assert.ErrorIs(a.Healthy(), ErrPermanentFailure)
+	assert.NoError(a.Stop(context.Background()))
+}
+
+func TestAgentExpiredRegistrationUnhealthy(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	clock := newFakeClock()
+	r, calls := fakeRegistrar()
+	a, err := New(r, testConfig(clock))
+	require.NoError(err)
+	require.NoError(a.Start(context.Background()))
+	receive(t, calls)
+	require.NoError(a.Stop(context.Background()))
+
+	clock.now = clock.now.Add(time.Hour)
+	assert.ErrorIs(a.Healthy(), ErrNotRegistered)
+}
+
+type fakeService struct {
+	mu  sync.Mutex
+	iws []ancla.InternalWebhook
+}
+
+func (s *fakeService) Add(_ context.Context, _ string, iw ancla.InternalWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.iws = append(s.iws, iw)
+	return nil
+}
+
+func (s *fakeService) GetAll(context.Context) ([]ancla.InternalWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.iws, nil
+}
+
+func (s *fakeService) GetAllByOwner(ctx context.Context, _ string) ([]ancla.InternalWebhook, error) {
+	return s.GetAll(ctx)
+}
+
+func (s *fakeService) Get(_ context.Context, _, id string) (ancla.InternalWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, iw := range s.iws {
+		if ancla.SHA256IDHasher(iw.Webhook.Config.URL) == id {
+			return iw, nil
+		}
+	}
+	return ancla.InternalWebhook{}, ancla.ErrWebhookNotFound
+}
+
+func TestHTTPRegistrar(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	svc := new(fakeService)
+	handler := ancla.NewAddWebhookHandler(svc, ancla.HandlerConfig{DisablePartnerIDs: true})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	decorator := new(auth.MockDecorator)
+	decorator.On("Decorate").Return(nil)
+	r := NewHTTPRegistrar(server.URL, nil, decorator)
+	iw := testConfig(newFakeClock()).Webhook
+	iw.Webhook.Until = time.Date(2021, time.January, 2, 15, 14, 0, 0, time.UTC)
+	require.NoError(r.Add(context.Background(), "owner", iw))
+
+	iws, _ := svc.GetAll(context.Background())
+	require.Len(iws, 1)
+	assert.Equal(iw.Webhook.Config.URL, iws[0].Webhook.Config.URL)
+	assert.Equal(iw.Webhook.Duration, iws[0].Webhook.Duration)
+	assert.Equal(iw.Webhook.Until, iws[0].Webhook.Until)
+	decorator.AssertExpectations(t)
+
+	t.Run("Non-success response", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		err := NewHTTPRegistrar(failing.URL, nil, nil).Add(context.Background(), "owner", iw)
+		assert.ErrorIs(err, client.ErrNonSuccessStatus)
+	})
+}
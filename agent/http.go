@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/xmidt-org/ancla"
+	"github.com/xmidt-org/ancla/auth"
+	"github.com/xmidt-org/ancla/client"
+)
+
+type clientRegistrar struct {
+	client *client.Client
+}
+
+// NewClientRegistrar returns a Registrar that registers webhooks through the
+// given registration API client.
+// The remote service derives the owner and partner IDs from the request's
+// credentials, so the owner passed to Add and the webhook's PartnerIDs are not sent.
+func NewClientRegistrar(c *client.Client) Registrar {
+	return &clientRegistrar{client: c}
+}
+
+// NewHTTPRegistrar returns a Registrar that registers webhooks by posting them
+// to the given URL of a remote ancla add webhook handler, without retries since
+// the agent retries failed registrations itself. See NewClientRegistrar.
+// httpClient defaults to http.DefaultClient and decorator may be nil when no auth
+// headers are needed.
+func NewHTTPRegistrar(rawURL string, httpClient *http.Client, decorator auth.Decorator) Registrar {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return RegistrarFunc(func(context.Context, string, ancla.InternalWebhook) error {
+			return err
+		})
+	}
+	path := u.RequestURI()
+	u.Path, u.RawPath, u.RawQuery = "", "", ""
+
+	c, err := client.New(client.Config{
+		Address:          u.String(),
+		AddPath:          path,
+		HTTPClient:       httpClient,
+		Auth:             decorator,
+		RetryMaxAttempts: 1,
+	})
+	if err != nil {
+		return RegistrarFunc(func(context.Context, string, ancla.InternalWebhook) error {
+			return err
+		})
+	}
+	return NewClientRegistrar(c)
+}
+
+func (r *clientRegistrar) Add(ctx context.Context, _ string, iw ancla.InternalWebhook) error {
+	w := iw.Webhook
+	return r.client.Register(ctx, ancla.WebhookRegistration{
+		Address:    w.Address,
+		Config:     w.Config,
+		FailureURL: w.FailureURL,
+		Events:     w.Events,
+		Matcher:    w.Matcher,
+		Duration:   ancla.CustomDuration(w.Duration),
+		Until:      w.Until,
+	}, client.RegisterOptions{})
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import "context"
+
+// HealthReporter is implemented by Decorators that can tell whether they are
+// able to decorate requests, such as ones that acquire tokens from a remote
+// endpoint.
+type HealthReporter interface {
+	// Healthy returns nil when requests can be decorated. Otherwise it returns
+	// the reason they can't, such as the last token acquisition error.
+	Healthy(ctx context.Context) error
+}
+
+// Healthy returns the health of the given Decorator. Decorators that don't
+// implement HealthReporter, and nil ones, are always healthy.
+func Healthy(ctx context.Context, d Decorator) error {
+	if hr, ok := d.(HealthReporter); ok {
+		return hr.Healthy(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when Argus responds to a request with an unexpected
+// status code. It wraps one of the package's errors, e.g. ErrItemNotFound, so
+// errors.Is still works.
+type APIError struct {
+	// Code is the status code of the Argus response.
+	Code int
+
+	// ArgusError is the X-Xmidt-Error header of the Argus response.
+	ArgusError string
+
+	// Body is the raw body of the Argus response.
+	Body []byte
+
+	// Err is the error for the status code.
+	Err error
+}
+
+func newAPIError(resp response) *APIError {
+	return &APIError{
+		Code:       resp.Code,
+		ArgusError: resp.ArgusErrorHeader,
+		Body:       resp.Body,
+		Err:        translateNonSuccessStatusCode(resp.Code),
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: received status %v", e.Err, e.Code)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the status code to respond with upstream of Argus: 404 for
+// a missing item, 503 when Argus is unavailable or throttling, and 502 for any
+// other failure since it isn't the caller's doing.
+func (e *APIError) StatusCode() int {
+	switch e.Code {
+	case http.StatusNotFound:
+		return http.StatusNotFound
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/model"
+)
+
+func TestAPIError(t *testing.T) {
+	var ttl int64 = 60
+	item := model.Item{ID: "252f10c83610ebca1a059c0bae8255eba2f95be4d1d7bcfa89d7248a82d9f111", Data: map[string]interface{}{"k": "v"}, TTL: &ttl}
+	requests := []struct {
+		desc string
+		do   func(*BasicClient) error
+	}{
+		{
+			desc: "GetItems",
+			do: func(c *BasicClient) error {
+				_, err := c.GetItems(context.TODO(), "owner")
+				return err
+			},
+		},
+		{
+			desc: "GetItem",
+			do: func(c *BasicClient) error {
+				_, err := c.GetItem(context.TODO(), "owner", item.ID)
+				return err
+			},
+		},
+		{
+			desc: "PushItem",
+			do: func(c *BasicClient) error {
+				_, err := c.PushItem(context.TODO(), "owner", item)
+				return err
+			},
+		},
+		{
+			desc: "RemoveItem",
+			do: func(c *BasicClient) error {
+				_, err := c.RemoveItem(context.TODO(), item.ID, "owner")
+				return err
+			},
+		},
+	}
+	tcs := []struct {
+		desc               string
+		code               int
+		expectedErr        error
+		expectedStatusCode int
+	}{
+		{
+			desc:               "Bad request",
+			code:               http.StatusBadRequest,
+			expectedErr:        ErrBadRequest,
+			expectedStatusCode: http.StatusBadGateway,
+		},
+		{
+			desc:               "Forbidden",
+			code:               http.StatusForbidden,
+			expectedErr:        ErrFailedAuthentication,
+			expectedStatusCode: http.StatusBadGateway,
+		},
+		{
+			desc:               "Not found",
+			code:               http.StatusNotFound,
+			expectedErr:        ErrItemNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "Throttled",
+			code:               http.StatusTooManyRequests,
+			expectedErr:        errNonSuccessResponse,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "Internal error",
+			code:               http.StatusInternalServerError,
+			expectedErr:        errNonSuccessResponse,
+			expectedStatusCode: http.StatusBadGateway,
+		},
+	}
+	for _, r := range requests {
+		for _, tc := range tcs {
+			t.Run(r.desc+" "+tc.desc, func(t *testing.T) {
+				assert := assert.New(t)
+				server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+					rw.Header().Set(XmidtErrorHeaderKey, "argus says no")
+					rw.WriteHeader(tc.code)
+					rw.Write([]byte(`{"message": "no"}`))
+				}))
+				defer server.Close()
+				client, err := NewBasicClient(BasicClientConfig{
+					Address: server.URL,
+					Bucket:  "bucket-name",
+				}, nil)
+				require.NoError(t, err)
+
+				err = r.do(client)
+				assert.ErrorIs(err, tc.expectedErr)
+				var apiErr *APIError
+				require.True(t, errors.As(err, &apiErr))
+				assert.Equal(tc.code, apiErr.Code)
+				assert.Equal("argus says no", apiErr.ArgusError)
+				assert.Equal(`{"message": "no"}`, string(apiErr.Body))
+				assert.Equal(tc.expectedStatusCode, apiErr.StatusCode())
+				assert.Contains(err.Error(), tc.expectedErr.Error())
+			})
+		}
+	}
+}
@@ -10,8 +10,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
-
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/xmidt-org/ancla/auth"
 	"github.com/xmidt-org/ancla/model"
 	"go.uber.org/zap"
@@ -32,6 +40,10 @@ var (
 	ErrUndefinedIntervalTicker = errors.New("interval ticker is nil. Can't listen for updates")
 	ErrAuthDecoratorFailure    = errors.New("failed decorating auth header")
 	ErrBadRequest              = errors.New("argus rejected the request as invalid")
+	ErrItemNotFound            = errors.New("item not found")
+	ErrReadOnlyClient          = errors.New("client is read only")
+	ErrClientClosed            = errors.New("client is closed")
+	ErrInvalidURLTemplate      = errors.New("invalid URL template")
 )
 
 var (
@@ -53,83 +65,429 @@ type BasicClientConfig struct {
 	Bucket string
 
 	// HTTPClient refers to the client that will be used to send requests.
-	// (Optional) Defaults to http.DefaultClient.
+	// Close leaves its connections to the caller.
+	// (Optional) Defaults to a client with its own copy of http.DefaultTransport,
+	// whose idle connections are closed by Close.
 	HTTPClient *http.Client
 
 	// Auth provides the mechanism to add auth headers to outgoing requests.
 	// (Optional) If not provided, no auth headers are added.
 	Auth auth.Decorator
+
+	// VerifyOwnership, if true, drops the items fetched for an owner whose Owner
+	// field names someone else. Items without an Owner are kept since not all
+	// stores report it.
+	// (Optional) Defaults to false.
+	VerifyOwnership bool
+
+	// KeepDuplicateItems, if true, makes GetItems return every item of Argus'
+	// response, even when several of them share an ID.
+	// (Optional) Defaults to false, which keeps only the last item of each ID,
+	// at the position of the first, logging and counting the dropped ones.
+	KeepDuplicateItems bool
+
+	// Measures for instrumenting the client, such as the count and duration
+	// of its GetItems, GetItem, PushItem and RemoveItem operations. Any of them
+	// may be nil.
+	// (Optional) If not provided, the client isn't instrumented.
+	Measures *Measures
+
+	// Tracer traces every request attempt, e.g. to add Argus requests to
+	// OpenTelemetry traces. With Measures.OperationDuration, the trace IDs are
+	// attached to its observations as exemplars.
+	// (Optional) If not provided, requests aren't traced.
+	Tracer RequestTracer
+
+	// BulkConcurrency is the maximum number of concurrent requests made by
+	// bulk operations such as PushItems and RemoveItems.
+	// (Optional) Defaults to 8.
+	BulkConcurrency int
+
+	// ReadOnly, if true, makes PushItem and RemoveItem fail with ErrReadOnlyClient
+	// without sending any request, for read paths that must never modify the store.
+	// (Optional) Defaults to false.
+	ReadOnly bool
+
+	// IgnoreNotFoundOnRemove, if true, makes RemoveItem return an empty item and
+	// no error when the item doesn't exist, for delete-if-exists semantics.
+	// (Optional) Defaults to false, which fails with ErrItemNotFound.
+	IgnoreNotFoundOnRemove bool
+
+	// URLTemplate is the path appended to Address for the requests on a single
+	// item (GetItem, PushItem and RemoveItem). It must contain the {bucket} and
+	// {id} placeholders, i.e. /api/v2/tenants/{bucket}/items/{id}.
+	// (Optional) Defaults to /api/v1/store/{bucket}/{id}.
+	URLTemplate string
+
+	// ListURLTemplate is the path appended to Address by GetItems. It must
+	// contain the {bucket} placeholder and must not contain {id}.
+	// (Optional) Defaults to URLTemplate without its trailing /{id}, which must
+	// then be present.
+	ListURLTemplate string
+
+	// RetryMaxAttempts is the maximum number of attempts of a GET, PUT or DELETE
+	// request that fails with a network error or a 5xx response. 4xx responses are
+	// never retried. Retries are counted by Measures.RequestRetries.
+	// (Optional) Defaults to 1, which doesn't retry.
+	RetryMaxAttempts int
+
+	// ConditionalRequests, if true, makes the polls of a ListenerClient reading
+	// through this client remember the ETag and Last-Modified headers of their
+	// latest response for each owner, and send If-None-Match and
+	// If-Modified-Since with the next poll. When Argus responds with a 304, the
+	// listener reuses the items of its previous poll. Other GetItems calls,
+	// such as the service's, always fetch the items, so the client can be
+	// shared, but only by a single listener.
+	// (Optional) Defaults to false.
+	ConditionalRequests bool
+
+	// RetryInitialBackoff is the wait before the first retry. It doubles with
+	// each retry and is jittered by up to half its value. Waits end early when
+	// the request's context is done.
+	// (Optional) Defaults to 100ms.
+	RetryInitialBackoff time.Duration
+}
+
+// BasicClientSummary describes the resolved configuration of a BasicClient, for
+// debugging. It never includes credentials.
+type BasicClientSummary struct {
+	// StoreBaseURL is the URL the bucket paths are appended to.
+	StoreBaseURL string `json:"storeBaseURL"`
+
+	// Address is the configured Argus URL.
+	Address string `json:"address"`
+
+	// APIPath is the store API path appended to Address.
+	APIPath string `json:"apiPath"`
+
+	// URLTemplate is the path template of single item requests.
+	URLTemplate string `json:"urlTemplate"`
+
+	// ListURLTemplate is the path template of GetItems requests.
+	ListURLTemplate string `json:"listURLTemplate"`
+
+	// Bucket is the bucket partition used by the client.
+	Bucket string `json:"bucket"`
+
+	// AuthConfigured is true when outgoing requests are decorated with auth headers.
+	AuthConfigured bool `json:"authConfigured"`
+
+	// Timeout is the timeout of the client's HTTP client. Zero means no timeout.
+	Timeout time.Duration `json:"timeout"`
+
+	// VerifyOwnership reports whether foreign items are dropped by GetItems.
+	VerifyOwnership bool `json:"verifyOwnership"`
+
+	// ReadOnly reports whether writes are rejected.
+	ReadOnly bool `json:"readOnly"`
+
+	// RetryMaxAttempts is the maximum number of attempts of retryable requests.
+	RetryMaxAttempts int `json:"retryMaxAttempts"`
 }
 
 // BasicClient is the client used to make requests to Argus.
 type BasicClient struct {
-	client       *http.Client
-	auth         auth.Decorator
-	storeBaseURL string
-	bucket       string
-	getLogger    func(context.Context) *zap.Logger
+	client          *http.Client
+	auth            auth.Decorator
+	bucket          string
+	urlTemplate     string
+	listURLTemplate string
+	getLogger       func(context.Context) *zap.Logger
+
+	verifyOwnership bool
+	measures        *Measures
+	tracer          RequestTracer
+	address         string
+	bulkConcurrency int
+	readOnly        bool
+	closed          atomic.Bool
+	// ownsClient is true when the client was created by NewBasicClient.
+	ownsClient bool
+
+	ignoreNotFoundOnRemove bool
+	keepDuplicateItems     bool
+	// conditional holds the validators of GetItems responses, if enabled.
+	conditional *conditionalCache
+
+	retryMaxAttempts    int
+	retryInitialBackoff time.Duration
 }
 
 type response struct {
 	Body             []byte
 	ArgusErrorHeader string
 	Code             int
+	Validators       validators
+	// TraceID is the trace ID the tracer propagated with the request, if any.
+	TraceID string
 }
 
 const (
 	storeAPIPath     = "/api/v1/store"
-	errWrappedFmt    = "%w: %s"
+	bucketParam      = "{bucket}"
+	idParam          = "{id}"
+	limitParam       = "limit"
+	errWrappedFmt    = "%w: %w"
 	errStatusCodeFmt = "%w: received status %v"
 	errorHeaderKey   = "errorHeader"
-)
 
-// Items is a slice of model.Item(s) .
-type Items []model.Item
+	defaultBulkConcurrency = 8
+
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+)
 
 // NewBasicClient creates a new BasicClient that can be used to
 // make requests to Argus.
 func NewBasicClient(config BasicClientConfig,
 	getLogger func(context.Context) *zap.Logger) (*BasicClient, error) {
+	ownsClient := config.HTTPClient == nil
 	err := validateBasicConfig(&config)
 	if err != nil {
 		return nil, err
 	}
+	if getLogger == nil {
+		getLogger = func(context.Context) *zap.Logger {
+			return zap.NewNop()
+		}
+	}
+
+	c := &BasicClient{
+		client:          config.HTTPClient,
+		auth:            config.Auth,
+		bucket:          config.Bucket,
+		urlTemplate:     config.URLTemplate,
+		listURLTemplate: config.ListURLTemplate,
+		getLogger:       getLogger,
+
+		verifyOwnership: config.VerifyOwnership,
+		measures:        config.Measures,
+		tracer:          config.Tracer,
+		address:         config.Address,
+		bulkConcurrency: config.BulkConcurrency,
+		readOnly:        config.ReadOnly,
+		ownsClient:      ownsClient,
+
+		ignoreNotFoundOnRemove: config.IgnoreNotFoundOnRemove,
+		keepDuplicateItems:     config.KeepDuplicateItems,
+
+		retryMaxAttempts:    config.RetryMaxAttempts,
+		retryInitialBackoff: config.RetryInitialBackoff,
+	}
+	if config.ConditionalRequests {
+		c.conditional = newConditionalCache()
+	}
+
+	getLogger(context.Background()).Info("Created chrysom basic client", zap.Any("config", c.Config()))
+	return c, nil
+}
+
+// Config returns a summary of the client's resolved configuration.
+func (c *BasicClient) Config() BasicClientSummary {
+	apiPath := strings.TrimSuffix(strings.SplitN(c.listURLTemplate, bucketParam, 2)[0], "/")
+	s := BasicClientSummary{
+		StoreBaseURL:    c.address + apiPath,
+		Address:         c.address,
+		APIPath:         apiPath,
+		URLTemplate:     c.urlTemplate,
+		ListURLTemplate: c.listURLTemplate,
+		Bucket:          c.bucket,
+		AuthConfigured:  c.auth != nil,
+		VerifyOwnership: c.verifyOwnership,
+		ReadOnly:        c.readOnly,
+
+		RetryMaxAttempts: c.retryMaxAttempts,
+	}
+	if c.client != nil {
+		s.Timeout = c.client.Timeout
+	}
+	return s
+}
 
-	return &BasicClient{
-		client:       config.HTTPClient,
-		auth:         config.Auth,
-		bucket:       config.Bucket,
-		storeBaseURL: config.Address + storeAPIPath,
-		getLogger:    getLogger,
-	}, nil
+// AuthHealthy returns nil unless the client's auth decorator implements
+// auth.HealthReporter and reports it can't decorate requests, in which case
+// requests would fail with ErrAuthDecoratorFailure. Use it as a readiness check.
+func (c *BasicClient) AuthHealthy(ctx context.Context) error {
+	if err := auth.Healthy(ctx, c.auth); err != nil {
+		return fmt.Errorf(errWrappedFmt, ErrAuthDecoratorFailure, err)
+	}
+	return nil
 }
 
-// GetItems fetches all items that belong to a given owner.
+// GetItems fetches all items that belong to a given owner. With
+// ConditionalRequests, the polls of a ListenerClient fail with ErrNotModified
+// when the items haven't changed since its previous poll for the owner.
 func (c *BasicClient) GetItems(ctx context.Context, owner string) (Items, error) {
-	response, err := c.sendRequest(ctx, owner, http.MethodGet, fmt.Sprintf("%s/%s", c.storeBaseURL, c.bucket), nil)
+	return c.getItems(ctx, owner, c.listURL(), c.conditional)
+}
+
+// GetItemsLimited fetches at most limit of the items that belong to a given
+// owner, asking Argus for no more with the limit query parameter. Responses
+// from servers that ignore it are truncated to the limit. A limit below 1
+// fetches all items.
+func (c *BasicClient) GetItemsLimited(ctx context.Context, owner string, limit int) (Items, error) {
+	if limit < 1 {
+		return c.GetItems(ctx, owner)
+	}
+
+	u := c.listURL()
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	items, err := c.getItems(ctx, owner, u+sep+limitParam+"="+strconv.Itoa(limit), nil)
 	if err != nil {
 		return nil, err
 	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// getItems fetches the items at the url, making the request conditional on
+// the validators in the cache, if any.
+func (c *BasicClient) getItems(ctx context.Context, owner, url string, cache *conditionalCache) (Items, error) {
+	if cache != nil {
+		conditional, revalidate := conditionalFrom(ctx)
+		if !conditional {
+			cache = nil
+		} else if v, ok := cache.get(url, owner); ok && revalidate {
+			ctx = withValidators(ctx, v)
+		}
+	}
+	response, err := c.send(ctx, RequestInfo{Operation: GetItemsOperation}, owner, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Code == http.StatusNotModified && cache != nil {
+		c.getLogger(ctx).Debug("Argus responded that the items weren't modified")
+		return nil, ErrNotModified
+	}
 
 	if response.Code != http.StatusOK {
 		c.getLogger(ctx).Error("Argus responded with non-200 response for GetItems request",
 			zap.Int("code", response.Code), zap.String(errorHeaderKey, response.ArgusErrorHeader))
-		return nil, fmt.Errorf(errStatusCodeFmt, translateNonSuccessStatusCode(response.Code), response.Code)
+		return nil, newAPIError(response)
 	}
 
 	var items Items
 
 	err = json.Unmarshal(response.Body, &items)
 	if err != nil {
-		return nil, fmt.Errorf("GetItems: %w: %s", errJSONUnmarshal, err.Error())
+		return nil, fmt.Errorf("GetItems: %w: %w", errJSONUnmarshal, err)
+	}
+	fetchedAt := time.Now()
+	for i := range items {
+		items[i].FetchedAt = fetchedAt
+	}
+	if cache != nil {
+		cache.put(url, owner, response.Validators)
+	}
+
+	if c.verifyOwnership && owner != "" {
+		items = c.dropForeignItems(ctx, owner, items)
+	}
+
+	if !c.keepDuplicateItems {
+		items = c.dropDuplicateItems(ctx, items)
 	}
 
 	return items, nil
 }
 
+// GetItem returns the item with the given ID. It fails with ErrItemNotFound when
+// there is no such item, or when VerifyOwnership is set and the item belongs to
+// another owner.
+func (c *BasicClient) GetItem(ctx context.Context, owner, id string) (model.Item, error) {
+	if len(id) < 1 {
+		return model.Item{}, ErrItemIDEmpty
+	}
+
+	response, err := c.send(ctx, RequestInfo{Operation: GetItemOperation, ItemID: id}, owner, http.MethodGet, c.itemURL(id), nil)
+	if err != nil {
+		return model.Item{}, err
+	}
+
+	if response.Code != http.StatusOK {
+		if response.Code != http.StatusNotFound {
+			c.getLogger(ctx).Error("Argus responded with non-200 response for GetItem request",
+				zap.Int("code", response.Code), zap.String(errorHeaderKey, response.ArgusErrorHeader))
+		}
+		return model.Item{}, newAPIError(response)
+	}
+
+	var item model.Item
+	err = json.Unmarshal(response.Body, &item)
+	if err != nil {
+		return model.Item{}, fmt.Errorf("GetItem: %w: %w", errJSONUnmarshal, err)
+	}
+	item.FetchedAt = time.Now()
+
+	if c.verifyOwnership && owner != "" && len(c.dropForeignItems(ctx, owner, Items{item})) == 0 {
+		return model.Item{}, fmt.Errorf(errStatusCodeFmt, ErrItemNotFound, http.StatusNotFound)
+	}
+
+	return item, nil
+}
+
+// dropForeignItems removes the items owned by someone other than the given owner.
+func (c *BasicClient) dropForeignItems(ctx context.Context, owner string, items Items) Items {
+	owned := items[:0]
+	var foreign []string
+	for _, item := range items {
+		if item.Owner != "" && item.Owner != owner {
+			foreign = append(foreign, item.ID)
+			continue
+		}
+		owned = append(owned, item)
+	}
+
+	if len(foreign) > 0 {
+		c.getLogger(ctx).Error("Argus responded with items belonging to another owner for GetItems request",
+			zap.String("owner", owner), zap.Strings("ids", foreign))
+		if c.measures != nil && c.measures.OwnershipMismatches != nil {
+			c.measures.OwnershipMismatches.Add(float64(len(foreign)))
+		}
+	}
+
+	return owned
+}
+
+// dropDuplicateItems keeps only the last of the items sharing an ID, at the
+// position of the first.
+func (c *BasicClient) dropDuplicateItems(ctx context.Context, items Items) Items {
+	unique := items[:0]
+	index := make(map[string]int, len(items))
+	var duplicates []string
+	for _, item := range items {
+		if i, ok := index[item.ID]; ok {
+			unique[i] = item
+			duplicates = append(duplicates, item.ID)
+			continue
+		}
+		index[item.ID] = len(unique)
+		unique = append(unique, item)
+	}
+
+	if len(duplicates) > 0 {
+		c.getLogger(ctx).Warn("Argus responded with items sharing an ID for GetItems request, keeping the last of each",
+			zap.Strings("ids", duplicates))
+		if c.measures != nil && c.measures.DuplicateItems != nil {
+			c.measures.DuplicateItems.Add(float64(len(duplicates)))
+		}
+	}
+
+	return unique
+}
+
 // PushItem creates a new item if one doesn't already exist. If an item exists
 // and the ownership matches, the item is simply updated.
 func (c *BasicClient) PushItem(ctx context.Context, owner string, item model.Item) (PushResult, error) {
+	if c.readOnly {
+		return NilPushResult, ErrReadOnlyClient
+	}
+
 	err := validatePushItemInput(owner, item)
 	if err != nil {
 		return NilPushResult, err
@@ -137,10 +495,10 @@ func (c *BasicClient) PushItem(ctx context.Context, owner string, item model.Ite
 
 	data, err := json.Marshal(item)
 	if err != nil {
-		return NilPushResult, fmt.Errorf(errWrappedFmt, errJSONMarshal, err.Error())
+		return NilPushResult, fmt.Errorf(errWrappedFmt, errJSONMarshal, err)
 	}
 
-	response, err := c.sendRequest(ctx, owner, http.MethodPut, fmt.Sprintf("%s/%s/%s", c.storeBaseURL, c.bucket, item.ID), bytes.NewReader(data))
+	response, err := c.send(ctx, RequestInfo{Operation: PushItemOperation, ItemID: item.ID}, owner, http.MethodPut, c.itemURL(item.ID), bytes.NewReader(data))
 	if err != nil {
 		return NilPushResult, err
 	}
@@ -156,34 +514,178 @@ func (c *BasicClient) PushItem(ctx context.Context, owner string, item model.Ite
 	c.getLogger(ctx).Error("Argus responded with a non-successful status code for a PushItem request",
 		zap.Int("code", response.Code), zap.String(errorHeaderKey, response.ArgusErrorHeader))
 
-	return NilPushResult, fmt.Errorf(errStatusCodeFmt, translateNonSuccessStatusCode(response.Code), response.Code)
+	return NilPushResult, newAPIError(response)
 }
 
 // RemoveItem removes the item if it exists and returns the data associated to it.
+// It fails with ErrItemNotFound when there is no such item, unless
+// IgnoreNotFoundOnRemove is set.
 func (c *BasicClient) RemoveItem(ctx context.Context, id, owner string) (model.Item, error) {
+	if c.readOnly {
+		return model.Item{}, ErrReadOnlyClient
+	}
+
 	if len(id) < 1 {
 		return model.Item{}, ErrItemIDEmpty
 	}
 
-	resp, err := c.sendRequest(ctx, owner, http.MethodDelete, fmt.Sprintf("%s/%s/%s", c.storeBaseURL, c.bucket, id), nil)
+	resp, err := c.send(ctx, RequestInfo{Operation: RemoveItemOperation, ItemID: id}, owner, http.MethodDelete, c.itemURL(id), nil)
 	if err != nil {
 		return model.Item{}, err
 	}
 
+	if resp.Code == http.StatusNotFound && c.ignoreNotFoundOnRemove {
+		return model.Item{}, nil
+	}
+
 	if resp.Code != http.StatusOK {
 		c.getLogger(ctx).Error("Argus responded with a non-successful status code for a RemoveItem request",
 			zap.Int("code", resp.Code), zap.String(errorHeaderKey, resp.ArgusErrorHeader))
-		return model.Item{}, fmt.Errorf(errStatusCodeFmt, translateNonSuccessStatusCode(resp.Code), resp.Code)
+		return model.Item{}, newAPIError(resp)
 	}
 
 	var item model.Item
 	err = json.Unmarshal(resp.Body, &item)
 	if err != nil {
-		return item, fmt.Errorf("RemoveItem: %w: %s", errJSONUnmarshal, err.Error())
+		return item, fmt.Errorf("RemoveItem: %w: %w", errJSONUnmarshal, err)
 	}
 	return item, nil
 }
 
+// RemoveItems removes the items with the given IDs, making up to BulkConcurrency
+// requests at a time. Items that are already gone count as removed. It returns the
+// removed IDs in the order given and the error of each ID that couldn't be removed.
+// Once ctx is done no more removals are started and the remaining IDs fail with
+// the context's error.
+func (c *BasicClient) RemoveItems(ctx context.Context, owner string, ids []string) (removed []string, failed map[string]error) {
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, c.bulkConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(ids); j++ {
+				errs[j] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			_, err := c.RemoveItem(ctx, id, owner)
+			if errors.Is(err, ErrItemNotFound) {
+				err = nil
+			}
+			errs[i] = err
+		}(i, id)
+	}
+	wg.Wait()
+
+	failed = make(map[string]error)
+	for i, id := range ids {
+		if errs[i] != nil {
+			failed[id] = errs[i]
+			continue
+		}
+		removed = append(removed, id)
+	}
+	return removed, failed
+}
+
+// PushItems pushes the items, making up to BulkConcurrency requests at a time.
+// It returns the result of each item in the order given, NilPushResult for the
+// ones that failed, along with a *BatchError of their errors by index. A failed
+// item doesn't keep the others from being pushed, but once ctx is done no more
+// pushes are started and the remaining items fail with the context's error.
+func (c *BasicClient) PushItems(ctx context.Context, owner string, items []model.Item) ([]PushResult, error) {
+	results := make([]PushResult, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, c.bulkConcurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(items); j++ {
+				errs[j] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item model.Item) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], errs[i] = c.PushItem(ctx, owner, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	return results, NewBatchError(errs)
+}
+
+// BatchError is returned by bulk operations when some of their entries failed.
+type BatchError struct {
+	// Errs holds the error of each failed entry by its index in the batch.
+	Errs map[int]error
+}
+
+// NewBatchError returns a *BatchError of the non nil errors by their index in
+// errs, or nil if there are none.
+func NewBatchError(errs []error) error {
+	failed := make(map[int]error)
+	for i, err := range errs {
+		if err != nil {
+			failed[i] = err
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BatchError{Errs: failed}
+}
+
+// Indices returns the indices of the failed entries in ascending order.
+func (e *BatchError) Indices() []int {
+	indices := make([]int, 0, len(e.Errs))
+	for i := range e.Errs {
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d entries of the batch failed", len(e.Errs))
+	for _, i := range e.Indices() {
+		fmt.Fprintf(&b, "; [%d]: %v", i, e.Errs[i])
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors of the failed entries, in the order of their indices.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errs))
+	for _, i := range e.Indices() {
+		errs = append(errs, e.Errs[i])
+	}
+	return errs
+}
+
 func validatePushItemInput(_ string, item model.Item) error {
 	if len(item.ID) < 1 {
 		return ErrItemIDEmpty
@@ -196,43 +698,157 @@ func validatePushItemInput(_ string, item model.Item) error {
 	return nil
 }
 
+// Close closes the idle connections of the client's default HTTP client, leaving
+// those of a configured BasicClientConfig.HTTPClient to its owner. Requests made
+// after Close fail with ErrClientClosed. Calling Close more than once is a no op.
+func (c *BasicClient) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if c.ownsClient {
+		c.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// send sends the request of the operation with sendRequest, observing its
+// duration, retries included, by its final code class. Each attempt is traced
+// with the info and counted by its operation.
+func (c *BasicClient) send(ctx context.Context, info RequestInfo, owner, method, url string, body io.Reader) (response, error) {
+	start := time.Now()
+	resp, err := c.sendRequest(withRequestInfo(ctx, info), owner, method, url, body)
+	if c.measures == nil || c.measures.OperationDuration == nil {
+		return resp, err
+	}
+
+	class := TransportErrorCodeClass
+	if resp.Code != 0 {
+		class = codeClass(resp.Code)
+	}
+	labels := prometheus.Labels{OperationLabel: info.Operation, CodeClassLabel: class}
+	observe(c.measures.OperationDuration.With(labels), time.Since(start).Seconds(), resp.TraceID)
+	return resp, err
+}
+
+// sendRequest sends the request, retrying it as configured.
 func (c *BasicClient) sendRequest(ctx context.Context, owner, method, url string, body io.Reader) (response, error) {
+	if c.closed.Load() {
+		return response{}, ErrClientClosed
+	}
+	if c.retryMaxAttempts <= 1 || !isIdempotent(method) {
+		return c.sendAttempt(ctx, owner, method, url, body)
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return response{}, fmt.Errorf(errWrappedFmt, errNewRequestFailure, err)
+		}
+	}
+
+	backoff := c.retryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		var attemptBody io.Reader
+		if payload != nil {
+			attemptBody = bytes.NewReader(payload)
+		}
+		resp, err := c.sendAttempt(ctx, owner, method, url, attemptBody)
+		retryable := errors.Is(err, errDoRequestFailure) || (err == nil && resp.Code >= http.StatusInternalServerError)
+		if !retryable || attempt >= c.retryMaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		c.getLogger(ctx).Debug("Retrying Argus request",
+			zap.String("method", method), zap.Int("attempt", attempt), zap.Int("code", resp.Code), zap.Error(err))
+		timer := time.NewTimer(backoff/2 + rand.N(backoff/2+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response{}, fmt.Errorf(errWrappedFmt, errDoRequestFailure, context.Cause(ctx))
+		case <-timer.C:
+		}
+		backoff *= 2
+		if c.measures != nil && c.measures.RequestRetries != nil {
+			c.measures.RequestRetries.With(prometheus.Labels{MethodLabel: method}).Inc()
+		}
+	}
+}
+
+// isIdempotent reports whether requests with the method can be retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (c *BasicClient) sendAttempt(ctx context.Context, owner, method, url string, body io.Reader) (response, error) {
 	r, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return response{}, fmt.Errorf(errWrappedFmt, errNewRequestFailure, err.Error())
+		return response{}, fmt.Errorf(errWrappedFmt, errNewRequestFailure, err)
 	}
 
 	if len(owner) > 0 {
 		r.Header.Set(ItemOwnerHeaderKey, owner)
 	}
 
+	if v, ok := ctx.Value(validatorsKey{}).(validators); ok {
+		v.set(r.Header)
+	}
+
 	if c.auth != nil {
 		if err := c.auth.Decorate(ctx, r); err != nil {
 			return response{}, errors.Join(ErrAuthDecoratorFailure, err)
 		}
 	}
 
+	end := c.traceRequest(r)
+	traceID := traceIDOf(r.Header)
 	resp, err := c.client.Do(r)
 	if err != nil {
-		return response{}, fmt.Errorf(errWrappedFmt, errDoRequestFailure, err.Error())
+		c.countRequest(ctx, method, TransportErrorCodeClass)
+		err = fmt.Errorf(errWrappedFmt, errDoRequestFailure, err)
+		end(0, err)
+		return response{TraceID: traceID}, err
 	}
+	c.countRequest(ctx, method, codeClass(resp.StatusCode))
 
 	defer resp.Body.Close()
 
 	sqResp := response{
 		Code:             resp.StatusCode,
 		ArgusErrorHeader: resp.Header.Get(XmidtErrorHeaderKey),
+		Validators: validators{
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+		},
+		TraceID: traceID,
 	}
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return sqResp, fmt.Errorf(errWrappedFmt, errReadingBodyFailure, err.Error())
+		err = fmt.Errorf(errWrappedFmt, errReadingBodyFailure, err)
+		end(sqResp.Code, err)
+		return sqResp, err
 	}
+	end(sqResp.Code, nil)
 
 	sqResp.Body = bodyBytes
 
 	return sqResp, nil
 }
 
+// countRequest counts a request attempt by its method, operation and code
+// class.
+func (c *BasicClient) countRequest(ctx context.Context, method, class string) {
+	if c.measures != nil && c.measures.Requests != nil {
+		info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+		c.measures.Requests.With(prometheus.Labels{MethodLabel: method, OperationLabel: info.Operation, CodeClassLabel: class}).Inc()
+	}
+}
+
 // translateNonSuccessStatusCode returns as specific error
 // for known Argus status codes.
 func translateNonSuccessStatusCode(code int) error {
@@ -241,11 +857,54 @@ func translateNonSuccessStatusCode(code int) error {
 		return ErrBadRequest
 	case http.StatusUnauthorized, http.StatusForbidden:
 		return ErrFailedAuthentication
+	case http.StatusNotFound:
+		return ErrItemNotFound
 	default:
 		return errNonSuccessResponse
 	}
 }
 
+// itemURL returns the URL of the item with the given id.
+func (c *BasicClient) itemURL(id string) string {
+	return c.address + strings.NewReplacer(bucketParam, c.bucket, idParam, id).Replace(c.urlTemplate)
+}
+
+// listURL returns the URL of the client's bucket.
+func (c *BasicClient) listURL() string {
+	return c.address + strings.ReplaceAll(c.listURLTemplate, bucketParam, c.bucket)
+}
+
+func validateURLTemplates(config *BasicClientConfig) error {
+	if config.URLTemplate == "" {
+		config.URLTemplate = storeAPIPath + "/" + bucketParam + "/" + idParam
+	}
+	if !strings.Contains(config.URLTemplate, bucketParam) || !strings.Contains(config.URLTemplate, idParam) {
+		return fmt.Errorf("%w: %q must contain %s and %s", ErrInvalidURLTemplate, config.URLTemplate, bucketParam, idParam)
+	}
+
+	if config.ListURLTemplate == "" {
+		list, ok := strings.CutSuffix(config.URLTemplate, "/"+idParam)
+		if !ok {
+			return fmt.Errorf("%w: a list template is required when %q doesn't end with /%s", ErrInvalidURLTemplate, config.URLTemplate, idParam)
+		}
+		config.ListURLTemplate = list
+	}
+	if !strings.Contains(config.ListURLTemplate, bucketParam) || strings.Contains(config.ListURLTemplate, idParam) {
+		return fmt.Errorf("%w: list template %q must contain %s and not %s", ErrInvalidURLTemplate, config.ListURLTemplate, bucketParam, idParam)
+	}
+
+	return nil
+}
+
+// newHTTPClient returns a client with its own copy of http.DefaultTransport, so
+// that closing its idle connections leaves those of other clients alone.
+func newHTTPClient() *http.Client {
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		return &http.Client{Transport: t.Clone()}
+	}
+	return &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}}
+}
+
 func validateBasicConfig(config *BasicClientConfig) error {
 	if config.Address == "" {
 		return ErrAddressEmpty
@@ -255,8 +914,24 @@ func validateBasicConfig(config *BasicClientConfig) error {
 		return ErrBucketEmpty
 	}
 
+	if err := validateURLTemplates(config); err != nil {
+		return err
+	}
+
 	if config.HTTPClient == nil {
-		config.HTTPClient = http.DefaultClient
+		config.HTTPClient = newHTTPClient()
+	}
+
+	if config.BulkConcurrency < 1 {
+		config.BulkConcurrency = defaultBulkConcurrency
+	}
+
+	if config.RetryMaxAttempts < 1 {
+		config.RetryMaxAttempts = 1
+	}
+
+	if config.RetryInitialBackoff <= 0 {
+		config.RetryInitialBackoff = defaultRetryInitialBackoff
 	}
 
 	return nil
@@ -12,14 +12,21 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/ancla/auth"
 	"github.com/xmidt-org/ancla/model"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const failingURL = "nowhere://"
@@ -40,14 +47,24 @@ func TestValidateBasicConfig(t *testing.T) {
 	}
 
 	allDefaultsCaseConfig := &BasicClientConfig{
-		HTTPClient: http.DefaultClient,
-		Address:    "example.com",
-		Bucket:     "bucket-name",
+		HTTPClient:          http.DefaultClient,
+		Address:             "example.com",
+		Bucket:              "bucket-name",
+		BulkConcurrency:     defaultBulkConcurrency,
+		URLTemplate:         "/api/v1/store/{bucket}/{id}",
+		ListURLTemplate:     "/api/v1/store/{bucket}",
+		RetryMaxAttempts:    1,
+		RetryInitialBackoff: defaultRetryInitialBackoff,
 	}
 	allDefinedCaseConfig := &BasicClientConfig{
-		HTTPClient: http.DefaultClient,
-		Address:    "example.com",
-		Bucket:     "amazing-bucket",
+		HTTPClient:          http.DefaultClient,
+		Address:             "example.com",
+		Bucket:              "amazing-bucket",
+		BulkConcurrency:     2,
+		URLTemplate:         "/api/v1/store/{bucket}/{id}",
+		ListURLTemplate:     "/api/v1/store/{bucket}",
+		RetryMaxAttempts:    3,
+		RetryInitialBackoff: time.Second,
 	}
 
 	tcs := []testCase{
@@ -79,9 +96,12 @@ func TestValidateBasicConfig(t *testing.T) {
 		{
 			Description: "All defined",
 			Input: &BasicClientConfig{
-				HTTPClient: http.DefaultClient,
-				Address:    "example.com",
-				Bucket:     "amazing-bucket",
+				HTTPClient:          http.DefaultClient,
+				Address:             "example.com",
+				Bucket:              "amazing-bucket",
+				BulkConcurrency:     2,
+				RetryMaxAttempts:    3,
+				RetryInitialBackoff: time.Second,
 			},
 			ExpectedConfig: allDefinedCaseConfig,
 		},
@@ -99,6 +119,16 @@ func TestValidateBasicConfig(t *testing.T) {
 	}
 }
 
+// fetched checks that the items were given the time they were fetched, and
+// clears it so that they can be compared with the expected items.
+func fetched(t *testing.T, items Items) Items {
+	for i := range items {
+		assert.False(t, items[i].FetchedAt.IsZero(), items[i].ID)
+		items[i].FetchedAt = time.Time{}
+	}
+	return items
+}
+
 func TestSendRequest(t *testing.T) {
 	type testCase struct {
 		Description      string
@@ -315,14 +345,14 @@ func TestGetItems(t *testing.T) {
 			}
 
 			if tc.ShouldDoRequestFail {
-				client.storeBaseURL = failingURL
+				client.address = failingURL
 			}
 
 			output, err := client.GetItems(context.TODO(), owner)
 
 			assert.True(errors.Is(err, tc.ExpectedErr))
 			if tc.ExpectedErr == nil {
-				assert.EqualValues(tc.ExpectedOutput, output)
+				assert.EqualValues(tc.ExpectedOutput, fetched(t, output))
 			}
 		})
 	}
@@ -458,7 +488,7 @@ func TestPushItem(t *testing.T) {
 			}
 
 			if tc.ShouldDoRequestFail {
-				client.storeBaseURL = failingURL
+				client.address = failingURL
 			}
 
 			if tc.ShouldEraseBucket {
@@ -489,6 +519,7 @@ func TestRemoveItem(t *testing.T) {
 		ExpectedOutput       model.Item
 		MockError            error
 		MockAuth             string
+		IgnoreNotFound       bool
 	}
 
 	tcs := []testCase{
@@ -497,6 +528,22 @@ func TestRemoveItem(t *testing.T) {
 			ExpectedErr: ErrAuthDecoratorFailure,
 			MockError:   errFails,
 		},
+		{
+			Description:  "Not found",
+			ResponseCode: http.StatusNotFound,
+			ExpectedErr:  ErrItemNotFound,
+		},
+		{
+			Description:    "Not found ignored",
+			ResponseCode:   http.StatusNotFound,
+			IgnoreNotFound: true,
+		},
+		{
+			Description:    "Other non-success not ignored",
+			ResponseCode:   http.StatusInternalServerError,
+			IgnoreNotFound: true,
+			ExpectedErr:    errNonSuccessResponse,
+		},
 		{
 			Description:         "Do request fails",
 			ShouldDoRequestFail: true,
@@ -551,8 +598,9 @@ func TestRemoveItem(t *testing.T) {
 			}))
 
 			client, err := NewBasicClient(BasicClientConfig{
-				Address: server.URL,
-				Bucket:  bucket,
+				Address:                server.URL,
+				Bucket:                 bucket,
+				IgnoreNotFoundOnRemove: tc.IgnoreNotFound,
 			}, func(context.Context) *zap.Logger {
 				return zap.NewNop()
 			})
@@ -564,13 +612,14 @@ func TestRemoveItem(t *testing.T) {
 			}
 
 			if tc.ShouldDoRequestFail {
-				client.storeBaseURL = failingURL
+				client.address = failingURL
 			}
 
 			require.Nil(err)
 			output, err := client.RemoveItem(context.TODO(), id, tc.Owner)
 
 			if tc.ExpectedErr == nil {
+				assert.NoError(err)
 				assert.EqualValues(tc.ExpectedOutput, output)
 			} else {
 				assert.True(errors.Is(err, tc.ExpectedErr))
@@ -599,6 +648,10 @@ func TestTranslateStatusCode(t *testing.T) {
 			Code:        http.StatusBadRequest,
 			ExpectedErr: ErrBadRequest,
 		},
+		{
+			Code:        http.StatusNotFound,
+			ExpectedErr: ErrItemNotFound,
+		},
 		{
 			Code:        http.StatusInternalServerError,
 			ExpectedErr: errNonSuccessResponse,
@@ -663,3 +716,1192 @@ func getItemsHappyOutput() Items {
 		},
 	}
 }
+
+func TestGetItemsVerifyOwnership(t *testing.T) {
+	payload := []byte(`[
+		{"id": "1", "data": {"k": "v"}, "owner": "owner-name"},
+		{"id": "2", "data": {"k": "v"}, "owner": "someone-else"},
+		{"id": "3", "data": {"k": "v"}}
+	]`)
+	tcs := []struct {
+		Description        string
+		VerifyOwnership    bool
+		Owner              string
+		ExpectedIDs        []string
+		ExpectedMismatches float64
+	}{
+		{
+			Description:     "Disabled",
+			Owner:           "owner-name",
+			ExpectedIDs:     []string{"1", "2", "3"},
+			VerifyOwnership: false,
+		},
+		{
+			Description:        "Enabled",
+			Owner:              "owner-name",
+			ExpectedIDs:        []string{"1", "3"},
+			VerifyOwnership:    true,
+			ExpectedMismatches: 1,
+		},
+		{
+			Description:     "Enabled without owner",
+			ExpectedIDs:     []string{"1", "2", "3"},
+			VerifyOwnership: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write(payload)
+			}))
+			defer server.Close()
+
+			mismatches := prometheus.NewCounter(prometheus.CounterOpts{Name: "testMismatches"})
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:         server.URL,
+				Bucket:          "bucket-name",
+				VerifyOwnership: tc.VerifyOwnership,
+				Measures:        &Measures{OwnershipMismatches: mismatches},
+			}, func(context.Context) *zap.Logger {
+				return zap.NewNop()
+			})
+			require.NoError(err)
+
+			items, err := client.GetItems(context.TODO(), tc.Owner)
+			require.NoError(err)
+			ids := make([]string, 0, len(items))
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+			assert.Equal(tc.ExpectedIDs, ids)
+			assert.Equal(tc.ExpectedMismatches, counterValue(t, mismatches))
+		})
+	}
+}
+
+func TestGetItemsDuplicateIDs(t *testing.T) {
+	payload := []byte(`[
+		{"id": "1", "data": {"k": "first"}},
+		{"id": "2", "data": {"k": "v"}},
+		{"id": "1", "data": {"k": "second"}},
+		{"id": "1", "data": {"k": "last"}}
+	]`)
+	tcs := []struct {
+		Description        string
+		KeepDuplicateItems bool
+		ExpectedIDs        []string
+		ExpectedValues     []interface{}
+		ExpectedDuplicates float64
+	}{
+		{
+			Description:        "Deduplicated",
+			ExpectedIDs:        []string{"1", "2"},
+			ExpectedValues:     []interface{}{"last", "v"},
+			ExpectedDuplicates: 2,
+		},
+		{
+			Description:        "Kept",
+			KeepDuplicateItems: true,
+			ExpectedIDs:        []string{"1", "2", "1", "1"},
+			ExpectedValues:     []interface{}{"first", "v", "second", "last"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write(payload)
+			}))
+			defer server.Close()
+
+			duplicates := prometheus.NewCounter(prometheus.CounterOpts{Name: "testDuplicates"})
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:            server.URL,
+				Bucket:             "bucket-name",
+				KeepDuplicateItems: tc.KeepDuplicateItems,
+				Measures:           &Measures{DuplicateItems: duplicates},
+			}, func(context.Context) *zap.Logger {
+				return zap.NewNop()
+			})
+			require.NoError(err)
+
+			items, err := client.GetItems(context.TODO(), "owner-name")
+			require.NoError(err)
+			assert.Equal(tc.ExpectedIDs, items.IDs())
+			values := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				values = append(values, item.Data["k"])
+			}
+			assert.Equal(tc.ExpectedValues, values)
+			assert.Equal(tc.ExpectedDuplicates, counterValue(t, duplicates))
+		})
+	}
+}
+
+func TestGetItemsConditional(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	type exchange struct {
+		ifNoneMatch     string
+		ifModifiedSince string
+	}
+	var (
+		exchanges []exchange
+		version   = "v1"
+		items     = Items{{ID: "1"}}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		exchanges = append(exchanges, exchange{
+			ifNoneMatch:     r.Header.Get("If-None-Match"),
+			ifModifiedSince: r.Header.Get("If-Modified-Since"),
+		})
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("ETag", etag)
+		rw.Header().Set("Last-Modified", "Mon, 12 Oct 2026 10:00:00 GMT")
+		json.NewEncoder(rw).Encode(items)
+	}))
+	defer server.Close()
+
+	client, err := NewBasicClient(BasicClientConfig{
+		Address:             server.URL,
+		Bucket:              "bucket-name",
+		ConditionalRequests: true,
+	}, nil)
+	require.NoError(err)
+
+	// 200, then 304 while the version is unchanged, then 200 once it changes.
+	poll := withConditional(context.Background(), true)
+	got, err := client.GetItems(withConditional(context.Background(), false), "")
+	require.NoError(err)
+	assert.Equal(items, fetched(t, got))
+
+	got, err = client.GetItems(poll, "")
+	assert.ErrorIs(err, ErrNotModified)
+	assert.Nil(got)
+
+	// calls that don't opt in always fetch the items.
+	got, err = client.GetItems(context.Background(), "")
+	require.NoError(err)
+	assert.Equal(items, fetched(t, got))
+
+	version, items = "v2", Items{{ID: "1"}, {ID: "2"}}
+	got, err = client.GetItems(poll, "")
+	require.NoError(err)
+	assert.Equal(items, fetched(t, got))
+
+	got, err = client.GetItems(withConditional(context.Background(), false), "")
+	require.NoError(err)
+	assert.Equal(items, fetched(t, got))
+
+	// the validators are kept by owner, and limited requests aren't conditional.
+	_, err = client.GetItems(poll, "owner")
+	require.NoError(err)
+	_, err = client.GetItemsLimited(poll, "", 1)
+	require.NoError(err)
+
+	lastModified := "Mon, 12 Oct 2026 10:00:00 GMT"
+	assert.Equal([]exchange{
+		{},
+		{`"v1"`, lastModified},
+		{},
+		{`"v1"`, lastModified},
+		{},
+		{},
+		{},
+	}, exchanges)
+
+	// disabled by default.
+	exchanges = nil
+	client, err = NewBasicClient(BasicClientConfig{
+		Address: server.URL,
+		Bucket:  "bucket-name",
+	}, nil)
+	require.NoError(err)
+	for range 2 {
+		_, err = client.GetItems(poll, "")
+		require.NoError(err)
+	}
+	assert.Equal([]exchange{{}, {}}, exchanges)
+}
+
+func TestGetItemsLimited(t *testing.T) {
+	tcs := []struct {
+		Description   string
+		Limit         int
+		HonorLimit    bool
+		ExpectedQuery string
+		ExpectedIDs   []string
+		ExpectedCount int
+	}{
+		{
+			Description:   "Server honors limit",
+			Limit:         2,
+			HonorLimit:    true,
+			ExpectedQuery: "limit=2",
+			ExpectedIDs:   []string{"1", "2"},
+			ExpectedCount: 2,
+		},
+		{
+			Description:   "Server ignores limit",
+			Limit:         2,
+			ExpectedQuery: "limit=2",
+			ExpectedIDs:   []string{"1", "2"},
+			ExpectedCount: 2,
+		},
+		{
+			Description:   "Limit above item count",
+			Limit:         5,
+			ExpectedQuery: "limit=5",
+			ExpectedIDs:   []string{"1", "2", "3"},
+			ExpectedCount: 3,
+		},
+		{
+			Description:   "No limit",
+			ExpectedIDs:   []string{"1", "2", "3"},
+			ExpectedCount: 3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var queries []string
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				queries = append(queries, r.URL.RawQuery)
+				items := Items{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+				if tc.HonorLimit {
+					items = items[:tc.Limit]
+				}
+				json.NewEncoder(rw).Encode(items)
+			}))
+			defer server.Close()
+
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:  server.URL,
+				Bucket:   "bucket-name",
+				Measures: &Measures{},
+			}, func(context.Context) *zap.Logger {
+				return zap.NewNop()
+			})
+			require.NoError(err)
+
+			items, err := client.GetItemsLimited(context.TODO(), "owner-name", tc.Limit)
+			require.NoError(err)
+			assert.Equal(tc.ExpectedIDs, items.IDs())
+
+			count, err := CountItems(context.TODO(), client, "owner-name", tc.Limit)
+			require.NoError(err)
+			assert.Equal(tc.ExpectedCount, count)
+			assert.Equal([]string{tc.ExpectedQuery, tc.ExpectedQuery}, queries)
+		})
+	}
+}
+
+func TestURLTemplates(t *testing.T) {
+	tcs := []struct {
+		Description     string
+		URLTemplate     string
+		ListURLTemplate string
+		ExpectedItem    string
+		ExpectedList    string
+		ExpectedErr     error
+	}{
+		{
+			Description:  "Default",
+			ExpectedItem: "/api/v1/store/bucket-name/item-id",
+			ExpectedList: "/api/v1/store/bucket-name",
+		},
+		{
+			Description:  "Derived list template",
+			URLTemplate:  "/api/v2/tenants/{bucket}/items/{id}",
+			ExpectedItem: "/api/v2/tenants/bucket-name/items/item-id",
+			ExpectedList: "/api/v2/tenants/bucket-name/items",
+		},
+		{
+			Description:     "Explicit list template",
+			URLTemplate:     "/api/v2/items/{id}?bucket={bucket}",
+			ListURLTemplate: "/api/v2/items?bucket={bucket}",
+			ExpectedItem:    "/api/v2/items/item-id?bucket=bucket-name",
+			ExpectedList:    "/api/v2/items?bucket=bucket-name",
+		},
+		{
+			Description: "Missing id",
+			URLTemplate: "/api/v2/tenants/{bucket}/items",
+			ExpectedErr: ErrInvalidURLTemplate,
+		},
+		{
+			Description: "Missing bucket",
+			URLTemplate: "/api/v2/items/{id}",
+			ExpectedErr: ErrInvalidURLTemplate,
+		},
+		{
+			Description: "Underivable list template",
+			URLTemplate: "/api/v2/items/{id}/{bucket}",
+			ExpectedErr: ErrInvalidURLTemplate,
+		},
+		{
+			Description:     "List template with id",
+			ListURLTemplate: "/api/v1/store/{bucket}/{id}",
+			ExpectedErr:     ErrInvalidURLTemplate,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:         "https://example-argus.io",
+				Bucket:          "bucket-name",
+				URLTemplate:     tc.URLTemplate,
+				ListURLTemplate: tc.ListURLTemplate,
+			}, nil)
+			assert.ErrorIs(err, tc.ExpectedErr)
+			if tc.ExpectedErr != nil {
+				return
+			}
+
+			assert.Equal("https://example-argus.io"+tc.ExpectedItem, client.itemURL("item-id"))
+			assert.Equal("https://example-argus.io"+tc.ExpectedList, client.listURL())
+		})
+	}
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	require.NoError(t, c.Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+// outageDecorator simulates a token acquiring auth.Decorator whose token
+// endpoint is unreachable while err is set.
+type outageDecorator struct {
+	err error
+}
+
+func (d *outageDecorator) Decorate(context.Context, *http.Request) error {
+	return d.err
+}
+
+func (d *outageDecorator) Healthy(context.Context) error {
+	return d.err
+}
+
+func TestAuthHealthy(t *testing.T) {
+	errOutage := errors.New("token endpoint unreachable")
+	tcs := []struct {
+		Description string
+		Auth        auth.Decorator
+		ExpectedErr error
+	}{
+		{
+			Description: "No decorator",
+		},
+		{
+			Description: "Decorator without health",
+			Auth:        new(auth.MockDecorator),
+		},
+		{
+			Description: "Healthy decorator",
+			Auth:        &outageDecorator{},
+		},
+		{
+			Description: "Token endpoint outage",
+			Auth:        &outageDecorator{err: errOutage},
+			ExpectedErr: errOutage,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			client, err := NewBasicClient(BasicClientConfig{
+				Address: "https://example-argus.io",
+				Bucket:  "bucket-name",
+				Auth:    tc.Auth,
+			}, nil)
+			require.NoError(err)
+
+			err = client.AuthHealthy(context.Background())
+			if tc.ExpectedErr == nil {
+				assert.NoError(err)
+				return
+			}
+			assert.ErrorIs(err, ErrAuthDecoratorFailure)
+			assert.ErrorIs(err, tc.ExpectedErr)
+			_, err = client.GetItems(context.Background(), "owner")
+			assert.ErrorIs(err, ErrAuthDecoratorFailure)
+		})
+	}
+}
+
+func TestBasicClientConfig(t *testing.T) {
+	tcs := []struct {
+		Description     string
+		Config          BasicClientConfig
+		ExpectedSummary BasicClientSummary
+	}{
+		{
+			Description: "Defaults",
+			Config: BasicClientConfig{
+				Address: "https://example-argus.io:8090",
+				Bucket:  "bucket-name",
+			},
+			ExpectedSummary: BasicClientSummary{
+				StoreBaseURL:     "https://example-argus.io:8090/api/v1/store",
+				Address:          "https://example-argus.io:8090",
+				APIPath:          storeAPIPath,
+				URLTemplate:      "/api/v1/store/{bucket}/{id}",
+				ListURLTemplate:  "/api/v1/store/{bucket}",
+				Bucket:           "bucket-name",
+				RetryMaxAttempts: 1,
+			},
+		},
+		{
+			Description: "All defined",
+			Config: BasicClientConfig{
+				Address:          "https://example-argus.io:8090",
+				Bucket:           "bucket-name",
+				HTTPClient:       &http.Client{Timeout: 5 * time.Second},
+				Auth:             new(auth.MockDecorator),
+				VerifyOwnership:  true,
+				ReadOnly:         true,
+				RetryMaxAttempts: 3,
+			},
+			ExpectedSummary: BasicClientSummary{
+				StoreBaseURL:     "https://example-argus.io:8090/api/v1/store",
+				Address:          "https://example-argus.io:8090",
+				APIPath:          storeAPIPath,
+				URLTemplate:      "/api/v1/store/{bucket}/{id}",
+				ListURLTemplate:  "/api/v1/store/{bucket}",
+				Bucket:           "bucket-name",
+				AuthConfigured:   true,
+				Timeout:          5 * time.Second,
+				VerifyOwnership:  true,
+				ReadOnly:         true,
+				RetryMaxAttempts: 3,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			core, logs := observer.New(zap.InfoLevel)
+			client, err := NewBasicClient(tc.Config, func(context.Context) *zap.Logger {
+				return zap.New(core)
+			})
+			require.NoError(err)
+			assert.Equal(tc.ExpectedSummary, client.Config())
+
+			entries := logs.FilterMessage("Created chrysom basic client").All()
+			require.Len(entries, 1)
+			assert.Equal(tc.ExpectedSummary, entries[0].ContextMap()["config"])
+		})
+	}
+
+	t.Run("No logger", func(t *testing.T) {
+		_, err := NewBasicClient(BasicClientConfig{Address: "example.com", Bucket: "bucket-name"}, nil)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRemoveItems(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var (
+		mu        sync.Mutex
+		active    int
+		maxActive int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(http.MethodDelete, r.Method)
+		assert.Equal("owner-name", r.Header.Get(ItemOwnerHeaderKey))
+		switch path.Base(r.URL.Path) {
+		case "gone":
+			rw.WriteHeader(http.StatusNotFound)
+		case "forbidden":
+			rw.WriteHeader(http.StatusForbidden)
+		case "broken":
+			rw.WriteHeader(http.StatusInternalServerError)
+		default:
+			rw.Write([]byte(`{"id": "ok", "data": {}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBasicClient(BasicClientConfig{
+		Address:         server.URL,
+		Bucket:          "bucket-name",
+		BulkConcurrency: 2,
+	}, nil)
+	require.NoError(err)
+
+	removed, failed := client.RemoveItems(context.Background(), "owner-name",
+		[]string{"ok-0", "gone", "forbidden", "ok-1", "broken", "ok-2"})
+	assert.Equal([]string{"ok-0", "gone", "ok-1", "ok-2"}, removed)
+	require.Len(failed, 2)
+	assert.ErrorIs(failed["forbidden"], ErrFailedAuthentication)
+	assert.ErrorIs(failed["broken"], errNonSuccessResponse)
+	assert.LessOrEqual(maxActive, 2)
+
+	t.Run("Canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		removed, failed := client.RemoveItems(ctx, "owner-name", []string{"ok-0", "ok-1"})
+		assert.Empty(removed)
+		assert.Len(failed, 2)
+		for _, err := range failed {
+			assert.ErrorIs(err, context.Canceled)
+		}
+	})
+}
+
+func TestPushItems(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var (
+		mu        sync.Mutex
+		active    int
+		maxActive int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		assert.Equal(http.MethodPut, r.Method)
+		assert.Equal("owner-name", r.Header.Get(ItemOwnerHeaderKey))
+		switch path.Base(r.URL.Path) {
+		case "bad":
+			rw.WriteHeader(http.StatusBadRequest)
+		case "existing":
+			rw.WriteHeader(http.StatusOK)
+		default:
+			rw.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewBasicClient(BasicClientConfig{
+		Address:         server.URL,
+		Bucket:          "bucket-name",
+		BulkConcurrency: 2,
+	}, nil)
+	require.NoError(err)
+
+	data := map[string]interface{}{"k": "v"}
+	results, err := client.PushItems(context.Background(), "owner-name", []model.Item{
+		{ID: "new-0", Data: data},
+		{ID: "bad", Data: data},
+		{ID: "existing", Data: data},
+		{ID: "empty"},
+		{ID: "new-1", Data: data},
+	})
+	assert.Equal([]PushResult{CreatedPushResult, NilPushResult, UpdatedPushResult, NilPushResult, CreatedPushResult}, results)
+	var batchErr *BatchError
+	require.ErrorAs(err, &batchErr)
+	assert.Equal([]int{1, 3}, batchErr.Indices())
+	assert.ErrorIs(batchErr.Errs[1], ErrBadRequest)
+	assert.ErrorIs(batchErr.Errs[3], ErrItemDataEmpty)
+	assert.ErrorIs(err, ErrBadRequest)
+	assert.Contains(err.Error(), "2 entries of the batch failed")
+	assert.LessOrEqual(maxActive, 2)
+
+	results, err = client.PushItems(context.Background(), "owner-name", []model.Item{{ID: "new-0", Data: data}})
+	assert.NoError(err)
+	assert.Equal([]PushResult{CreatedPushResult}, results)
+
+	t.Run("Canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.PushItems(ctx, "owner-name", []model.Item{{ID: "new-0", Data: data}, {ID: "new-1", Data: data}})
+		var batchErr *BatchError
+		require.ErrorAs(err, &batchErr)
+		assert.Equal([]int{0, 1}, batchErr.Indices())
+		assert.ErrorIs(err, context.Canceled)
+	})
+}
+
+func TestReadOnlyClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		rw.Write(getItemsValidPayload())
+	}))
+	defer server.Close()
+
+	client, err := NewBasicClient(BasicClientConfig{
+		Address:  server.URL,
+		Bucket:   "bucket-name",
+		ReadOnly: true,
+	}, nil)
+	require.NoError(err)
+
+	result, err := client.PushItem(context.Background(), "owner", model.Item{ID: "id", Data: map[string]interface{}{"k": "v"}})
+	assert.ErrorIs(err, ErrReadOnlyClient)
+	assert.Equal(NilPushResult, result)
+
+	_, err = client.RemoveItem(context.Background(), "id", "owner")
+	assert.ErrorIs(err, ErrReadOnlyClient)
+
+	removed, failed := client.RemoveItems(context.Background(), "owner", []string{"a", "b"})
+	assert.Empty(removed)
+	assert.ErrorIs(failed["a"], ErrReadOnlyClient)
+	assert.ErrorIs(failed["b"], ErrReadOnlyClient)
+	assert.Empty(requests)
+
+	items, err := client.GetItems(context.Background(), "")
+	assert.NoError(err)
+	assert.Equal(getItemsHappyOutput(), fetched(t, items))
+	assert.Equal([]string{http.MethodGet}, requests)
+}
+
+func TestErrorChains(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Write([]byte("{{}"))
+		}))
+		defer server.Close()
+		client, err := NewBasicClient(BasicClientConfig{Address: server.URL, Bucket: "bucket-name"}, nil)
+		require.NoError(t, err)
+
+		_, err = client.GetItems(context.Background(), "")
+		assert.ErrorIs(t, err, errJSONUnmarshal)
+		var syntaxErr *json.SyntaxError
+		assert.ErrorAs(t, err, &syntaxErr)
+	})
+
+	t.Run("Do request", func(t *testing.T) {
+		client, err := NewBasicClient(BasicClientConfig{Address: failingURL, Bucket: "bucket-name"}, nil)
+		require.NoError(t, err)
+
+		_, err = client.GetItems(context.Background(), "")
+		assert.ErrorIs(t, err, errDoRequestFailure)
+		var urlErr *url.Error
+		assert.ErrorAs(t, err, &urlErr)
+	})
+}
+
+func TestBasicClientClose(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		requests++
+		rw.Write(getItemsValidPayload())
+	}))
+	defer server.Close()
+
+	client, err := NewBasicClient(BasicClientConfig{
+		Address: server.URL,
+		Bucket:  "bucket-name",
+	}, nil)
+	require.NoError(err)
+	_, err = client.GetItems(context.Background(), "")
+	require.NoError(err)
+
+	assert.NoError(client.Close())
+	assert.NoError(client.Close())
+
+	_, err = client.GetItems(context.Background(), "")
+	assert.ErrorIs(err, ErrClientClosed)
+	_, err = client.PushItem(context.Background(), "owner", model.Item{ID: "id", Data: map[string]interface{}{"k": "v"}})
+	assert.ErrorIs(err, ErrClientClosed)
+	_, err = client.RemoveItem(context.Background(), "id", "owner")
+	assert.ErrorIs(err, ErrClientClosed)
+	assert.Equal(1, requests)
+}
+
+// idleClosingTransport counts the calls closing its idle connections.
+type idleClosingTransport struct {
+	http.RoundTripper
+	closes int
+}
+
+func (t *idleClosingTransport) CloseIdleConnections() {
+	t.closes++
+}
+
+func TestBasicClientCloseIdleConnections(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	given := &idleClosingTransport{RoundTripper: http.DefaultTransport}
+	client, err := NewBasicClient(BasicClientConfig{
+		Address:    "example.com",
+		Bucket:     "bucket-name",
+		HTTPClient: &http.Client{Transport: given},
+	}, nil)
+	require.NoError(err)
+	require.NoError(client.Close())
+	assert.Zero(given.closes, "a given client's connections are left to its owner")
+
+	client, err = NewBasicClient(BasicClientConfig{
+		Address: "example.com",
+		Bucket:  "bucket-name",
+	}, nil)
+	require.NoError(err)
+	assert.NotSame(http.DefaultClient, client.client)
+	assert.NotSame(http.DefaultTransport, client.client.Transport)
+	owned := &idleClosingTransport{RoundTripper: client.client.Transport}
+	client.client.Transport = owned
+	require.NoError(client.Close())
+	assert.Equal(1, owned.closes)
+}
+
+func TestGetItem(t *testing.T) {
+	tcs := []struct {
+		Description     string
+		ID              string
+		ResponsePayload []byte
+		ResponseCode    int
+		VerifyOwnership bool
+		ExpectedErr     error
+		ExpectedOutput  model.Item
+	}{
+		{
+			Description: "Empty ID",
+			ExpectedErr: ErrItemIDEmpty,
+		},
+		{
+			Description:  "Not found",
+			ID:           "id",
+			ResponseCode: http.StatusNotFound,
+			ExpectedErr:  ErrItemNotFound,
+		},
+		{
+			Description:  "Unauthorized",
+			ID:           "id",
+			ResponseCode: http.StatusForbidden,
+			ExpectedErr:  ErrFailedAuthentication,
+		},
+		{
+			Description:  "Other non-success",
+			ID:           "id",
+			ResponseCode: http.StatusInternalServerError,
+			ExpectedErr:  errNonSuccessResponse,
+		},
+		{
+			Description:     "Payload unmarshal error",
+			ID:              "id",
+			ResponseCode:    http.StatusOK,
+			ResponsePayload: []byte("{"),
+			ExpectedErr:     errJSONUnmarshal,
+		},
+		{
+			Description:     "Foreign item",
+			ID:              "id",
+			ResponseCode:    http.StatusOK,
+			ResponsePayload: []byte(`{"id": "id", "data": {"k": "v"}, "owner": "someone-else"}`),
+			VerifyOwnership: true,
+			ExpectedErr:     ErrItemNotFound,
+		},
+		{
+			Description:     "Happy path",
+			ID:              "id",
+			ResponseCode:    http.StatusOK,
+			ResponsePayload: []byte(`{"id": "id", "data": {"k": "v"}, "owner": "owner-name"}`),
+			VerifyOwnership: true,
+			ExpectedOutput:  model.Item{ID: "id", Data: map[string]interface{}{"k": "v"}, Owner: "owner-name"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			var (
+				assert  = assert.New(t)
+				require = require.New(t)
+				bucket  = "bucket-name"
+				owner   = "owner-name"
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(http.MethodGet, r.Method)
+				assert.Equal(owner, r.Header.Get(ItemOwnerHeaderKey))
+				assert.Equal(fmt.Sprintf("%s/%s/%s", storeAPIPath, bucket, tc.ID), r.URL.Path)
+
+				rw.WriteHeader(tc.ResponseCode)
+				rw.Write(tc.ResponsePayload)
+			}))
+			defer server.Close()
+
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:         server.URL,
+				Bucket:          bucket,
+				VerifyOwnership: tc.VerifyOwnership,
+			}, nil)
+			require.NoError(err)
+
+			output, err := client.GetItem(context.TODO(), owner, tc.ID)
+			if tc.ExpectedErr != nil {
+				assert.ErrorIs(err, tc.ExpectedErr)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.ExpectedOutput, fetched(t, Items{output})[0])
+		})
+	}
+}
+
+func TestSendRequestCodeClassMetric(t *testing.T) {
+	tcs := []struct {
+		Description   string
+		Code          int
+		Unreachable   bool
+		ExpectedClass string
+	}{
+		{
+			Description:   "Success",
+			Code:          http.StatusOK,
+			ExpectedClass: "2xx",
+		},
+		{
+			Description:   "Client error",
+			Code:          http.StatusNotFound,
+			ExpectedClass: "4xx",
+		},
+		{
+			Description:   "Server error",
+			Code:          http.StatusServiceUnavailable,
+			ExpectedClass: "5xx",
+		},
+		{
+			Description:   "Transport error",
+			Unreachable:   true,
+			ExpectedClass: TransportErrorCodeClass,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tc.Code)
+			}))
+			defer server.Close()
+			url := server.URL
+			if tc.Unreachable {
+				url = failingURL
+			}
+
+			requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "testRequests"}, []string{MethodLabel, OperationLabel, CodeClassLabel})
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:  url,
+				Bucket:   "bucket-name",
+				Measures: &Measures{Requests: requests},
+			}, nil)
+			require.NoError(err)
+
+			client.sendRequest(context.TODO(), "owner", http.MethodGet, url, nil)
+			client.sendRequest(context.TODO(), "owner", http.MethodGet, url, nil)
+			assert.Equal(2.0, counterValue(t, requests.With(prometheus.Labels{MethodLabel: http.MethodGet, OperationLabel: "", CodeClassLabel: tc.ExpectedClass})))
+			assert.Equal(1, testCollectorLen(requests))
+		})
+	}
+
+	t.Run("Nil measures", func(t *testing.T) {
+		client, err := NewBasicClient(BasicClientConfig{
+			Address:  failingURL,
+			Bucket:   "bucket-name",
+			Measures: &Measures{},
+		}, nil)
+		require.NoError(t, err)
+		_, err = client.sendRequest(context.TODO(), "owner", http.MethodGet, failingURL, nil)
+		assert.ErrorIs(t, err, errDoRequestFailure)
+	})
+}
+
+func TestOperationMetrics(t *testing.T) {
+	tcs := []struct {
+		Description    string
+		Code           int
+		Unreachable    bool
+		Call           func(*BasicClient) error
+		ExpectedMethod string
+		ExpectedOp     string
+		ExpectedClass  string
+	}{
+		{
+			Description: "Success",
+			Code:        http.StatusOK,
+			Call: func(c *BasicClient) error {
+				_, err := c.GetItems(context.TODO(), "owner")
+				return err
+			},
+			ExpectedMethod: http.MethodGet,
+			ExpectedOp:     GetItemsOperation,
+			ExpectedClass:  "2xx",
+		},
+		{
+			Description: "4xx",
+			Code:        http.StatusNotFound,
+			Call: func(c *BasicClient) error {
+				_, err := c.GetItem(context.TODO(), "owner", "id")
+				return err
+			},
+			ExpectedMethod: http.MethodGet,
+			ExpectedOp:     GetItemOperation,
+			ExpectedClass:  "4xx",
+		},
+		{
+			Description: "5xx",
+			Code:        http.StatusInternalServerError,
+			Call: func(c *BasicClient) error {
+				_, err := c.RemoveItem(context.TODO(), "id", "owner")
+				return err
+			},
+			ExpectedMethod: http.MethodDelete,
+			ExpectedOp:     RemoveItemOperation,
+			ExpectedClass:  "5xx",
+		},
+		{
+			Description: "Transport error",
+			Unreachable: true,
+			Call: func(c *BasicClient) error {
+				_, err := c.PushItem(context.TODO(), "owner", model.Item{ID: "id", Data: map[string]interface{}{"k": "v"}})
+				return err
+			},
+			ExpectedMethod: http.MethodPut,
+			ExpectedOp:     PushItemOperation,
+			ExpectedClass:  TransportErrorCodeClass,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tc.Code)
+				if tc.Code == http.StatusOK {
+					rw.Write([]byte("[]"))
+				}
+			}))
+			defer server.Close()
+			address := server.URL
+			if tc.Unreachable {
+				address = failingURL
+			}
+
+			registry := prometheus.NewRegistry()
+			requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "testRequests"}, []string{MethodLabel, OperationLabel, CodeClassLabel})
+			durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "testOperationDuration"}, []string{OperationLabel, CodeClassLabel})
+			registry.MustRegister(requests, durations)
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:  address,
+				Bucket:   "bucket-name",
+				Measures: &Measures{Requests: requests, OperationDuration: durations},
+			}, nil)
+			require.NoError(err)
+
+			tc.Call(client)
+			families, err := registry.Gather()
+			require.NoError(err)
+			require.Len(families, 2)
+			for _, family := range families {
+				require.Len(family.GetMetric(), 1)
+				m := family.GetMetric()[0]
+				labels := make(map[string]string)
+				for _, l := range m.GetLabel() {
+					labels[l.GetName()] = l.GetValue()
+				}
+				if family.GetName() == "testRequests" {
+					assert.Equal(map[string]string{MethodLabel: tc.ExpectedMethod, OperationLabel: tc.ExpectedOp, CodeClassLabel: tc.ExpectedClass}, labels)
+					assert.Equal(1.0, m.GetCounter().GetValue())
+				} else {
+					assert.Equal(map[string]string{OperationLabel: tc.ExpectedOp, CodeClassLabel: tc.ExpectedClass}, labels)
+					assert.Equal(uint64(1), m.GetHistogram().GetSampleCount())
+				}
+			}
+		})
+	}
+
+	t.Run("No measures", func(t *testing.T) {
+		client, err := NewBasicClient(BasicClientConfig{
+			Address:  failingURL,
+			Bucket:   "bucket-name",
+			Measures: &Measures{},
+		}, nil)
+		require.NoError(t, err)
+		_, err = client.GetItems(context.TODO(), "owner")
+		assert.ErrorIs(t, err, errDoRequestFailure)
+	})
+}
+
+// testCollectorLen returns the number of metrics collected from c.
+func testCollectorLen(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric, 16)
+	c.Collect(ch)
+	close(ch)
+	return len(ch)
+}
+
+func TestSendRequestRetry(t *testing.T) {
+	tcs := []struct {
+		Description      string
+		Method           string
+		Codes            []int
+		MaxAttempts      int
+		ExpectedRequests int
+		ExpectedCode     int
+		ExpectedRetries  float64
+	}{
+		{
+			Description:      "Success after retry",
+			Method:           http.MethodGet,
+			Codes:            []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			MaxAttempts:      3,
+			ExpectedRequests: 3,
+			ExpectedCode:     http.StatusOK,
+			ExpectedRetries:  2,
+		},
+		{
+			Description:      "Retries exhausted",
+			Method:           http.MethodDelete,
+			Codes:            []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			MaxAttempts:      3,
+			ExpectedRequests: 3,
+			ExpectedCode:     http.StatusServiceUnavailable,
+			ExpectedRetries:  2,
+		},
+		{
+			Description:      "Body resent",
+			Method:           http.MethodPut,
+			Codes:            []int{http.StatusInternalServerError, http.StatusCreated},
+			MaxAttempts:      3,
+			ExpectedRequests: 2,
+			ExpectedCode:     http.StatusCreated,
+			ExpectedRetries:  1,
+		},
+		{
+			Description:      "4xx not retried",
+			Method:           http.MethodGet,
+			Codes:            []int{http.StatusNotFound, http.StatusOK},
+			MaxAttempts:      3,
+			ExpectedRequests: 1,
+			ExpectedCode:     http.StatusNotFound,
+		},
+		{
+			Description:      "Non idempotent method not retried",
+			Method:           http.MethodPost,
+			Codes:            []int{http.StatusServiceUnavailable, http.StatusOK},
+			MaxAttempts:      3,
+			ExpectedRequests: 1,
+			ExpectedCode:     http.StatusServiceUnavailable,
+		},
+		{
+			Description:      "Retries disabled",
+			Method:           http.MethodGet,
+			Codes:            []int{http.StatusServiceUnavailable, http.StatusOK},
+			ExpectedRequests: 1,
+			ExpectedCode:     http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(err)
+				assert.Equal("payload", string(body))
+				rw.WriteHeader(tc.Codes[requests])
+				requests++
+			}))
+			defer server.Close()
+
+			retries := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "testRetries"}, []string{MethodLabel})
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:             server.URL,
+				Bucket:              "bucket-name",
+				Measures:            &Measures{RequestRetries: retries},
+				RetryMaxAttempts:    tc.MaxAttempts,
+				RetryInitialBackoff: time.Millisecond,
+			}, nil)
+			require.NoError(err)
+
+			resp, err := client.sendRequest(context.TODO(), "owner", tc.Method, server.URL, bytes.NewBufferString("payload"))
+			require.NoError(err)
+			assert.Equal(tc.ExpectedCode, resp.Code)
+			assert.Equal(tc.ExpectedRequests, requests)
+			assert.Equal(tc.ExpectedRetries, counterValue(t, retries.With(prometheus.Labels{MethodLabel: tc.Method})))
+		})
+	}
+
+	t.Run("Network error", func(t *testing.T) {
+		client, err := NewBasicClient(BasicClientConfig{
+			Address:             failingURL,
+			Bucket:              "bucket-name",
+			RetryMaxAttempts:    2,
+			RetryInitialBackoff: time.Millisecond,
+		}, nil)
+		require.NoError(t, err)
+		_, err = client.sendRequest(context.TODO(), "owner", http.MethodGet, failingURL, nil)
+		assert.ErrorIs(t, err, errDoRequestFailure)
+	})
+
+	t.Run("Canceled during backoff", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requests++
+			cancel()
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client, err := NewBasicClient(BasicClientConfig{
+			Address:             server.URL,
+			Bucket:              "bucket-name",
+			RetryMaxAttempts:    3,
+			RetryInitialBackoff: time.Hour,
+		}, nil)
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = client.sendRequest(ctx, "owner", http.MethodGet, server.URL, nil)
+		assert.ErrorIs(err, context.Canceled)
+		assert.Less(time.Since(start), time.Minute)
+		assert.Equal(1, requests)
+	})
+}
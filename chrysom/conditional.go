@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNotModified is returned by the conditional GetItems calls of a
+// ListenerClient when Argus reports the items haven't changed since its
+// previous poll.
+var ErrNotModified = errors.New("items not modified")
+
+// validators are the headers of a response identifying its version.
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+// set makes the request conditional on the version having changed.
+func (v validators) set(h http.Header) {
+	if v.etag != "" {
+		h.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		h.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+// conditionalCache holds the validators of the latest GetItems response of
+// each URL and owner.
+type conditionalCache struct {
+	mu sync.Mutex
+	m  map[string]validators
+}
+
+func newConditionalCache() *conditionalCache {
+	return &conditionalCache{m: make(map[string]validators)}
+}
+
+func conditionalKey(url, owner string) string {
+	return owner + " " + url
+}
+
+func (c *conditionalCache) get(url, owner string) (validators, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.m[conditionalKey(url, owner)]
+	return v, ok
+}
+
+// put remembers the validators of a response, forgetting the previous ones
+// when it has none.
+func (c *conditionalCache) put(url, owner string, v validators) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v == (validators{}) {
+		delete(c.m, conditionalKey(url, owner))
+		return
+	}
+	c.m[conditionalKey(url, owner)] = v
+}
+
+type validatorsKey struct{}
+
+// withValidators returns a context making sendAttempt send a request
+// conditional on the validators.
+func withValidators(ctx context.Context, v validators) context.Context {
+	return context.WithValue(ctx, validatorsKey{}, v)
+}
+
+type conditionalCallKey struct{}
+
+// withConditional returns a context opting GetItems into conditional requests
+// when they're enabled, for callers keeping the items of their previous call
+// such as a ListenerClient's polls. GetItems then remembers the validators of
+// its response and, if revalidate is true, sends the ones it remembered
+// before. Other calls always fetch the items and leave the validators alone.
+func withConditional(ctx context.Context, revalidate bool) context.Context {
+	return context.WithValue(ctx, conditionalCallKey{}, revalidate)
+}
+
+// conditionalFrom returns whether the context opts into conditional requests,
+// and whether they should revalidate the remembered validators.
+func conditionalFrom(ctx context.Context) (conditional, revalidate bool) {
+	revalidate, conditional = ctx.Value(conditionalCallKey{}).(bool)
+	return conditional, revalidate
+}
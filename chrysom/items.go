@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/xmidt-org/ancla/model"
+)
+
+// Items is a slice of model.Item(s) .
+// Its methods never modify the items they are called on.
+type Items []model.Item
+
+// Len returns the number of items.
+func (items Items) Len() int {
+	return len(items)
+}
+
+// IDs returns the IDs of the items, in order.
+func (items Items) IDs() []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// ByID indexes the items by ID. Of the items sharing an ID, the last one wins.
+func (items Items) ByID() map[string]model.Item {
+	byID := make(map[string]model.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID
+}
+
+// Sorted returns a copy of the items sorted by ID. Items sharing an ID keep
+// their relative order.
+func (items Items) Sorted() Items {
+	sorted := append(Items{}, items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// Hash returns a SHA-256 digest of the items' IDs, owners and data that doesn't
+// depend on their order. TTLs are left out since they count down between
+// fetches of the same items. It fails if the data can't be encoded as JSON.
+func (items Items) Hash() ([sha256.Size]byte, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, item := range items.Sorted() {
+		err := enc.Encode(struct {
+			ID    string                 `json:"id"`
+			Owner string                 `json:"owner"`
+			Data  map[string]interface{} `json:"data"`
+		}{item.ID, item.Owner, item.Data})
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum, nil
+}
+
+// DeepCopy returns a copy of the items that shares no memory with them.
+func (items Items) DeepCopy() Items {
+	if items == nil {
+		return nil
+	}
+	c := make(Items, len(items))
+	for i, item := range items {
+		c[i] = item.DeepCopy()
+	}
+	return c
+}
+
+// FilterExpired returns the items that haven't expired at now(), i.e. the ones
+// without a TTL or whose TTL, counted down from their FetchedAt, ends after it.
+// Items without a FetchedAt are taken to have just been fetched.
+func (items Items) FilterExpired(now func() time.Time) Items {
+	at := now()
+	live := make(Items, 0, len(items))
+	for _, item := range items {
+		if item.TTL == nil {
+			live = append(live, item)
+			continue
+		}
+		fetchedAt := item.FetchedAt
+		if fetchedAt.IsZero() {
+			fetchedAt = at
+		}
+		if fetchedAt.Add(time.Duration(*item.TTL) * time.Second).After(at) {
+			live = append(live, item)
+		}
+	}
+	return live
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/model"
+)
+
+func TestItems(t *testing.T) {
+	var (
+		live    int64 = 10
+		expired int64 = 0
+	)
+	tcs := []struct {
+		desc           string
+		items          Items
+		expectedIDs    []string
+		expectedByID   map[string]model.Item
+		expectedSorted Items
+		expectedLive   Items
+	}{
+		{
+			desc:           "Empty",
+			expectedIDs:    []string{},
+			expectedByID:   map[string]model.Item{},
+			expectedSorted: Items{},
+			expectedLive:   Items{},
+		},
+		{
+			desc:           "Nil TTL",
+			items:          Items{{ID: "b"}, {ID: "a", TTL: &live}},
+			expectedIDs:    []string{"b", "a"},
+			expectedByID:   map[string]model.Item{"a": {ID: "a", TTL: &live}, "b": {ID: "b"}},
+			expectedSorted: Items{{ID: "a", TTL: &live}, {ID: "b"}},
+			expectedLive:   Items{{ID: "b"}, {ID: "a", TTL: &live}},
+		},
+		{
+			desc:           "Expired",
+			items:          Items{{ID: "b", TTL: &expired}, {ID: "a", TTL: &live}},
+			expectedIDs:    []string{"b", "a"},
+			expectedByID:   map[string]model.Item{"a": {ID: "a", TTL: &live}, "b": {ID: "b", TTL: &expired}},
+			expectedSorted: Items{{ID: "a", TTL: &live}, {ID: "b", TTL: &expired}},
+			expectedLive:   Items{{ID: "a", TTL: &live}},
+		},
+		{
+			desc:           "Duplicate IDs",
+			items:          Items{{ID: "b", Owner: "first"}, {ID: "a"}, {ID: "b", Owner: "second"}},
+			expectedIDs:    []string{"b", "a", "b"},
+			expectedByID:   map[string]model.Item{"a": {ID: "a"}, "b": {ID: "b", Owner: "second"}},
+			expectedSorted: Items{{ID: "a"}, {ID: "b", Owner: "first"}, {ID: "b", Owner: "second"}},
+			expectedLive:   Items{{ID: "b", Owner: "first"}, {ID: "a"}, {ID: "b", Owner: "second"}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			original := append(Items(nil), tc.items...)
+
+			assert.Equal(len(tc.items), tc.items.Len())
+			assert.Equal(tc.expectedIDs, tc.items.IDs())
+			assert.Equal(tc.expectedByID, tc.items.ByID())
+			assert.Equal(tc.expectedSorted, tc.items.Sorted())
+			assert.Equal(tc.expectedLive, tc.items.FilterExpired(time.Now))
+			assert.Equal(original, tc.items)
+		})
+	}
+}
+
+func TestItemsFilterExpired(t *testing.T) {
+	var (
+		fetchedAt       = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		ttl       int64 = 60
+	)
+	items := Items{
+		{ID: "a", TTL: &ttl, FetchedAt: fetchedAt},
+		{ID: "b", FetchedAt: fetchedAt},
+		{ID: "c", TTL: &ttl},
+	}
+	tcs := []struct {
+		desc        string
+		now         time.Time
+		expectedIDs []string
+	}{
+		{
+			desc:        "Just fetched",
+			now:         fetchedAt,
+			expectedIDs: []string{"a", "b", "c"},
+		},
+		{
+			desc:        "Before the TTL ends",
+			now:         fetchedAt.Add(59 * time.Second),
+			expectedIDs: []string{"a", "b", "c"},
+		},
+		{
+			desc:        "Once the TTL ends",
+			now:         fetchedAt.Add(time.Minute),
+			expectedIDs: []string{"b", "c"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			live := items.FilterExpired(func() time.Time { return tc.now })
+			assert.Equal(t, tc.expectedIDs, live.IDs())
+		})
+	}
+}
+
+func TestItemsHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ttl, otherTTL := int64(10), int64(5)
+	items := Items{
+		{ID: "a", Owner: "owner", TTL: &ttl, Data: map[string]interface{}{"v": 1}},
+		{ID: "b", Owner: "owner", Data: map[string]interface{}{"v": 2}},
+	}
+	hash, err := items.Hash()
+	require.NoError(err)
+
+	same := Items{
+		{ID: "b", Owner: "owner", Data: map[string]interface{}{"v": 2}},
+		{ID: "a", Owner: "owner", TTL: &otherTTL, Data: map[string]interface{}{"v": 1}},
+	}
+	sameHash, err := same.Hash()
+	require.NoError(err)
+	assert.Equal(hash, sameHash)
+
+	for _, changed := range []Items{
+		{items[0]},
+		{items[0], {ID: "b", Owner: "other", Data: map[string]interface{}{"v": 2}}},
+		{items[0], {ID: "b", Owner: "owner", Data: map[string]interface{}{"v": 3}}},
+		{items[0], {ID: "c", Owner: "owner", Data: map[string]interface{}{"v": 2}}},
+	} {
+		changedHash, err := changed.Hash()
+		require.NoError(err)
+		assert.NotEqual(hash, changedHash)
+	}
+
+	_, err = Items{{ID: "a", Data: map[string]interface{}{"ch": make(chan int)}}}.Hash()
+	assert.Error(err)
+}
+
+func deepCopyFixture() Items {
+	ttl := int64(10)
+	return Items{{
+		ID:    "a",
+		Owner: "owner",
+		TTL:   &ttl,
+		Data: map[string]interface{}{
+			"config": map[string]interface{}{"url": "https://example.com", "alt_urls": []interface{}{"https://alt.example.com"}},
+			"events": []interface{}{"online", "offline"},
+			"until":  "2021-01-02T15:04:00Z",
+		},
+	}}
+}
+
+func TestItemsDeepCopy(t *testing.T) {
+	assert := assert.New(t)
+	assert.Nil(Items(nil).DeepCopy())
+
+	items := deepCopyFixture()
+	c := items.DeepCopy()
+	assert.Equal(items, c)
+
+	*c[0].TTL = 0
+	c[0].Data["until"] = "now"
+	c[0].Data["config"].(map[string]interface{})["url"] = "https://changed.example.com"
+	c[0].Data["config"].(map[string]interface{})["alt_urls"].([]interface{})[0] = "https://changed.example.com"
+	c[0].Data["events"].([]interface{})[0] = "changed"
+	assert.Equal(deepCopyFixture(), items)
+}
+
+func BenchmarkItemsDeepCopy(b *testing.B) {
+	items := make(Items, 0, 100)
+	for range 100 {
+		items = append(items, deepCopyFixture()...)
+	}
+
+	b.Run("DeepCopy", func(b *testing.B) {
+		for range b.N {
+			_ = items.DeepCopy()
+		}
+	})
+	b.Run("JSON", func(b *testing.B) {
+		for range b.N {
+			data, err := json.Marshal(items)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var c Items
+			if err := json.Unmarshal(data, &c); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
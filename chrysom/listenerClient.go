@@ -5,7 +5,12 @@ package chrysom
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +22,9 @@ import (
 // is safest to use errors.Is() to check for them.
 // Some internal errors might be unwrapped from output errors but unless these errors become exported,
 // they are not part of the library API and may change in future versions.
+// The exported errors are part of the stable API. Wrapped errors keep their cause in the
+// chain, so errors.As() can also extract the underlying error, such as a *url.Error from
+// a failed request or a *json.SyntaxError from a malformed response.
 var (
 	ErrFailedAuthentication = errors.New("failed to authentication with argus")
 
@@ -24,13 +32,51 @@ var (
 	ErrListenerNotRunning = errors.New("listener is either stopped or stopping")
 	ErrNoListenerProvided = errors.New("no listener provided")
 	ErrNoReaderProvided   = errors.New("no reader provided")
+	ErrInvalidPullJitter  = errors.New("pull jitter must be at least 0 and less than 1")
+
+	// ErrPollFailed wraps the errors given to ListenerClientConfig.OnPollError.
+	ErrPollFailed = errors.New("poll failed")
+	// ErrOversizedPoll is given to OnPollError for polls rejected for returning
+	// more than ListenerClientConfig.MaxItemsPerPoll items.
+	ErrOversizedPoll = errors.New("poll returned more items than the maximum per poll")
 )
 
+// callbackQueueSize is the number of poll callbacks that may wait for the
+// previous ones to return before being dropped.
+const callbackQueueSize = 16
+
+// ListenerState is the state of a ListenerClient, as returned by State.
+type ListenerState int32
+
+// Listener states.
+const (
+	// ListenerStopped is the state of a client that isn't polling.
+	ListenerStopped ListenerState = iota
+	// ListenerRunning is the state of a client polling on its interval.
+	ListenerRunning
+	// ListenerTransitioning is the state of a client being started or stopped.
+	ListenerTransitioning
+)
+
+// String returns the name of the state.
+func (s ListenerState) String() string {
+	switch s {
+	case ListenerStopped:
+		return "stopped"
+	case ListenerRunning:
+		return "running"
+	case ListenerTransitioning:
+		return "transitioning"
+	default:
+		return "unknown"
+	}
+}
+
 // listening states
 const (
-	stopped int32 = iota
-	running
-	transitioning
+	stopped       = int32(ListenerStopped)
+	running       = int32(ListenerRunning)
+	transitioning = int32(ListenerTransitioning)
 )
 
 const (
@@ -44,13 +90,90 @@ type ListenerClientConfig struct {
 	// (Optional). If not provided, listening won't be enabled for this client.
 	Listener Listener
 
+	// Listeners are more listeners to fetch the items for, each called on every
+	// poll after Listener. At least one of Listener or Listeners is required.
+	// (Optional).
+	Listeners []Listener
+
 	// PullInterval is how often listeners should get updates.
 	// (Optional). Defaults to 5 seconds.
 	PullInterval time.Duration
 
+	// PullJitter randomizes every interval until the next poll by up to this
+	// fraction of it either way, e.g. 0.1 for ±10%, so that instances started
+	// together don't poll Argus in lockstep. It must be in [0, 1).
+	// (Optional). Defaults to 0, which polls at a fixed cadence.
+	PullJitter float64
+
 	// Logger to be used by the client.
 	// (Optional). By default a no op logger will be used.
 	Logger *zap.Logger
+
+	// EmptyListConfirmations protects listeners against a store that transiently
+	// returns no items. When the last list delivered to the listener wasn't empty,
+	// an empty list is only delivered once this many consecutive polls have returned
+	// no items. The empty polls before that are logged, counted with the withheld
+	// outcome and not delivered. Note this means genuinely emptying the bucket takes
+	// this many polls to reach the listener.
+	// (Optional). Defaults to 0, which delivers empty lists right away.
+	EmptyListConfirmations int
+
+	// InfoLogEvery raises the debug log of every Nth successful poll to info, so
+	// low traffic deployments can confirm the listener is alive without debug logs.
+	// (Optional). Defaults to 0, which always logs successful polls at debug.
+	InfoLogEvery int
+
+	// DeliverFailedPolls, if true, tells a MetaListener about failed polls by
+	// calling UpdateWithMeta with the FailureOutcome and no items. Plain Listeners
+	// are never told about failed polls.
+	// (Optional). Defaults to false.
+	DeliverFailedPolls bool
+
+	// MaxFailureBackoff enables backing off while polls fail: the interval until
+	// the next poll doubles after each consecutive failed poll, up to this cap.
+	// It's reset to PullInterval by the next successful poll and by Start.
+	// (Optional). Defaults to 0, which polls every PullInterval regardless.
+	MaxFailureBackoff time.Duration
+
+	// MaxItemsPerPoll protects the service against a store returning far more
+	// items than it should. A poll returning more items is treated as a failure
+	// counted with the oversized outcome, and listeners keep the previous list.
+	// (Optional). Defaults to 0, which doesn't limit the items.
+	MaxItemsPerPoll int
+
+	// TruncateOversizedPolls, if true, delivers the first MaxItemsPerPoll items
+	// sorted by ID instead of failing oversized polls.
+	// (Optional). Defaults to false.
+	TruncateOversizedPolls bool
+
+	// SharedItems, if true, delivers the same items to every listener instead of
+	// a deep copy each, saving the copies in performance sensitive setups. The
+	// listeners must then not modify the items.
+	// (Optional). Defaults to false.
+	SharedItems bool
+
+	// AlwaysNotify, if true, updates the listeners on every successful poll.
+	// Otherwise plain Listeners are only updated when the items differ from the
+	// ones last delivered, as told by Items.Hash, while MetaListeners are updated
+	// with UpdateMeta.Unchanged set. The first poll after Start is always
+	// delivered.
+	// (Optional). Defaults to false.
+	AlwaysNotify bool
+
+	// OnPollError, if set, is called with an ErrPollFailed wrapped error for
+	// every failed or oversized poll, e.g. for the service to mark itself
+	// unhealthy. While the client is started, the poll callbacks are called in
+	// poll order from a goroutine of their own, so a slow callback delays
+	// neither the polls nor Stop. Callbacks that fall more than 16 polls behind
+	// are dropped with a warning.
+	// (Optional). Defaults to nil.
+	OnPollError func(error)
+
+	// OnPollSuccess, if set, is called like OnPollError with the items of every
+	// successful poll, unchanged ones included. The items must not be modified
+	// when SharedItems is set.
+	// (Optional). Defaults to nil.
+	OnPollSuccess func(Items)
 }
 
 // ListenerClient is the client used to poll Argus for updates.
@@ -62,12 +185,68 @@ type ListenerClient struct {
 }
 
 type observerConfig struct {
-	listener     Listener
+	listeners    []Listener
 	ticker       *time.Ticker
 	pullInterval time.Duration
+	pullJitter   float64
 	measures     *Measures
-	shutdown     chan struct{}
 	state        int32
+	closed       atomic.Bool
+
+	// runLock serializes Start and Stop, and guards shutdown and done, the
+	// channels of the current run. shutdown is closed to stop the polling
+	// goroutine, which closes done when it exits.
+	runLock  sync.Mutex
+	shutdown chan struct{}
+	done     chan struct{}
+
+	// pollLock serializes the polls of the ticker and of Refresh, and guards
+	// the poll state below.
+	pollLock sync.Mutex
+	// listenersLock guards listeners, which AddListener appends to.
+	listenersLock sync.RWMutex
+	// statusLock guards status, the status of the latest poll read by Status.
+	statusLock sync.RWMutex
+	status     PollStatus
+
+	emptyListConfirmations int
+	// emptyPolls is the number of consecutive polls with no items since the
+	// last non-empty list was delivered.
+	emptyPolls int
+	// delivered is true when the last list delivered to the listener wasn't empty.
+	delivered bool
+	// pollSeq is the number of the latest poll, used to correlate its logs.
+	pollSeq uint64
+	// infoLogEvery is how often a successful poll is logged at info.
+	infoLogEvery int
+	// successes is the number of successful polls.
+	successes int
+	// deliverFailedPolls is whether MetaListeners are told about failed polls.
+	deliverFailedPolls bool
+	// maxFailureBackoff caps the poll interval while polls fail.
+	maxFailureBackoff time.Duration
+	// failures is the number of consecutive failed polls.
+	failures int
+	// maxItemsPerPoll is the most items a poll may deliver.
+	maxItemsPerPoll int
+	// truncateOversized is whether oversized polls are truncated instead of failed.
+	truncateOversized bool
+	// sharedItems is whether every listener is given the same items.
+	sharedItems bool
+	// alwaysNotify is whether plain listeners are given unchanged items.
+	alwaysNotify bool
+	// lastHash is the hash of the items last delivered, if hashed is true.
+	lastHash [sha256.Size]byte
+	hashed   bool
+	// onPollError and onPollSuccess are the poll callbacks, either may be nil.
+	onPollError   func(error)
+	onPollSuccess func(Items)
+	// lastFetched is the items of the latest poll that fetched them, reused
+	// when the reader reports they weren't modified, if fetched is true.
+	lastFetched Items
+	fetched     bool
+	// callbacks queues the poll callbacks while the client is started.
+	callbacks chan func()
 }
 
 // NewListenerClient creates a new ListenerClient to be used to poll Argus
@@ -91,13 +270,30 @@ func NewListenerClient(config ListenerClientConfig,
 	if r == nil {
 		return nil, ErrNoReaderProvided
 	}
+	var listeners []Listener
+	for _, l := range append([]Listener{config.Listener}, config.Listeners...) {
+		if l != nil {
+			listeners = append(listeners, l)
+		}
+	}
 	return &ListenerClient{
 		observer: &observerConfig{
-			listener:     config.Listener,
+			listeners:    listeners,
 			ticker:       time.NewTicker(config.PullInterval),
 			pullInterval: config.PullInterval,
+			pullJitter:   config.PullJitter,
 			measures:     measures,
-			shutdown:     make(chan struct{}),
+
+			emptyListConfirmations: config.EmptyListConfirmations,
+			infoLogEvery:           config.InfoLogEvery,
+			deliverFailedPolls:     config.DeliverFailedPolls,
+			maxFailureBackoff:      config.MaxFailureBackoff,
+			maxItemsPerPoll:        config.MaxItemsPerPoll,
+			truncateOversized:      config.TruncateOversizedPolls,
+			sharedItems:            config.SharedItems,
+			alwaysNotify:           config.AlwaysNotify,
+			onPollError:            config.OnPollError,
+			onPollSuccess:          config.OnPollSuccess,
 		},
 		logger:    config.Logger,
 		setLogger: setLogger,
@@ -109,7 +305,7 @@ func NewListenerClient(config ListenerClientConfig,
 // is setup correctly. If a listener process is already in progress, calling Start()
 // is a NoOp. If you want to restart the current listener process, call Stop() first.
 func (c *ListenerClient) Start(ctx context.Context) error {
-	if c.observer == nil || c.observer.listener == nil {
+	if c.observer == nil || len(c.getListeners()) == 0 {
 		c.logger.Warn("No listener was setup to receive updates.")
 		return nil
 	}
@@ -117,59 +313,433 @@ func (c *ListenerClient) Start(ctx context.Context) error {
 		c.logger.Error("Observer ticker is nil", zap.Error(ErrUndefinedIntervalTicker))
 		return ErrUndefinedIntervalTicker
 	}
+	if c.observer.closed.Load() {
+		return ErrClientClosed
+	}
 
+	c.observer.runLock.Lock()
+	defer c.observer.runLock.Unlock()
 	if !atomic.CompareAndSwapInt32(&c.observer.state, stopped, transitioning) {
 		c.logger.Error("Start called when a listener was not in stopped state", zap.Error(ErrListenerNotStopped))
 		return ErrListenerNotStopped
 	}
 
-	c.observer.ticker.Reset(c.observer.pullInterval)
-	go func() {
-		for {
-			select {
-			case <-c.observer.shutdown:
-				return
-			case <-c.observer.ticker.C:
-				outcome := SuccessOutcome
-				ctx := c.setLogger(context.Background(), c.logger)
-				items, err := c.reader.GetItems(ctx, "")
-				if err == nil {
-					c.observer.listener.Update(items)
-				} else {
-					outcome = FailureOutcome
-					c.logger.Error("Failed to get items for listeners", zap.Error(err))
+	c.observer.failures = 0
+	if c.observer.onPollError != nil || c.observer.onPollSuccess != nil {
+		c.observer.pollLock.Lock()
+		c.observer.callbacks = make(chan func(), callbackQueueSize)
+		go runCallbacks(c.logger, c.observer.callbacks)
+		c.observer.pollLock.Unlock()
+	}
+	c.observer.ticker.Reset(c.jitter(c.observer.pullInterval))
+	c.observer.shutdown = make(chan struct{})
+	c.observer.done = make(chan struct{})
+	go c.run(c.observer.shutdown, c.observer.done)
+
+	atomic.SwapInt32(&c.observer.state, running)
+	return nil
+}
+
+// run polls on every tick until shutdown is closed, closing done when it
+// exits. A panic ends the run early, and is logged rather than crashing the
+// process. The client is then left running without polling until Stop.
+func (c *ListenerClient) run(shutdown <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.Error("Listener goroutine panicked, polling has stopped",
+				zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+
+	interval := c.observer.pullInterval
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-c.observer.ticker.C:
+			next := c.nextInterval(c.poll())
+			// a jittered ticker is re-armed after every poll.
+			if next != interval || c.observer.pullJitter > 0 {
+				interval = next
+				c.observer.ticker.Reset(c.jitter(interval))
+			}
+		}
+	}
+}
+
+// State returns the current state of the client.
+func (c *ListenerClient) State() ListenerState {
+	if c.observer == nil {
+		return ListenerStopped
+	}
+	return ListenerState(atomic.LoadInt32(&c.observer.state))
+}
+
+// Refresh polls for the items right away, outside of the interval, e.g. after
+// this process changed an item, and delivers them to the listeners like any
+// other poll. It returns the ErrPollFailed wrapped error given to OnPollError
+// when the poll fails. Refresh fails with ErrListenerNotRunning unless the
+// client is running.
+func (c *ListenerClient) Refresh(ctx context.Context) error {
+	if c.State() != ListenerRunning {
+		return ErrListenerNotRunning
+	}
+	_, err := c.pollWithContext(ctx)
+	return err
+}
+
+// poll fetches the current items and delivers them to the listener.
+func (c *ListenerClient) poll() string {
+	outcome, _ := c.pollWithContext(context.Background())
+	return outcome
+}
+
+// pollWithContext is poll, fetching the items with the given context and
+// returning the poll's error too. Polls are serialized, so the items of
+// concurrent polls are delivered in the order they were fetched.
+// Its logs, including the ones made through the context given to the reader,
+// carry the poll's sequence number and start time.
+func (c *ListenerClient) pollWithContext(ctx context.Context) (string, error) {
+	c.observer.pollLock.Lock()
+	defer c.observer.pollLock.Unlock()
+
+	outcome := SuccessOutcome
+	var pollErr error
+	c.observer.pollSeq++
+	start := time.Now()
+	logger := c.logger.With(
+		zap.Uint64("poll_seq", c.observer.pollSeq),
+		zap.Time("started_at", start))
+	ctx = c.setLogger(ctx, logger)
+	ctx = withConditional(ctx, c.observer.fetched)
+	items, err := c.reader.GetItems(ctx, "")
+	switch {
+	case errors.Is(err, ErrNotModified) && c.observer.fetched:
+		logger.Debug("Items weren't modified since the previous poll")
+		items, err = c.observer.lastFetched, nil
+	case err == nil:
+		c.observer.lastFetched, c.observer.fetched = items, true
+	}
+	fetchedAt := time.Now()
+	duration := fetchedAt.Sub(start)
+	fetched := len(items)
+	meta := UpdateMeta{
+		FetchedAt: fetchedAt,
+		PollSeq:   c.observer.pollSeq,
+	}
+	switch {
+	case err != nil:
+		outcome = FailureOutcome
+		logger.Error("Failed to get items for listeners", zap.Error(err))
+		if c.observer.deliverFailedPolls {
+			meta.Outcome = outcome
+			c.deliver(logger, meta, nil, true)
+		}
+		pollErr = c.pollFailed(logger, err)
+	case c.oversized(items) && !c.observer.truncateOversized:
+		outcome = OversizedOutcome
+		logger.Error("Rejecting poll with more items than the maximum per poll",
+			zap.Int("item_count", len(items)),
+			zap.Int("max_items", c.observer.maxItemsPerPoll))
+		if c.observer.deliverFailedPolls {
+			meta.Outcome = outcome
+			c.deliver(logger, meta, nil, true)
+		}
+		pollErr = c.pollFailed(logger, fmt.Errorf("%w: %d items exceed the maximum of %d",
+			ErrOversizedPoll, len(items), c.observer.maxItemsPerPoll))
+	case c.withholdEmpty(items):
+		outcome = WithheldOutcome
+		logger.Warn("Withholding empty list of items from listeners until it is confirmed",
+			zap.Int("emptyPolls", c.observer.emptyPolls),
+			zap.Int("confirmations", c.observer.emptyListConfirmations))
+	default:
+		if c.oversized(items) {
+			logger.Warn("Truncating poll with more items than the maximum per poll",
+				zap.Int("item_count", len(items)),
+				zap.Int("max_items", c.observer.maxItemsPerPoll))
+			items = truncateItems(items, c.observer.maxItemsPerPoll)
+		}
+		level := zap.DebugLevel
+		c.observer.successes++
+		if n := c.observer.infoLogEvery; n > 0 && c.observer.successes%n == 0 {
+			level = zap.InfoLevel
+		}
+		meta.Unchanged = c.unchanged(logger, items)
+		logger.Log(level, "Updating listeners",
+			zap.Int("item_count", len(items)),
+			zap.Duration("duration", duration),
+			zap.Bool("unchanged", meta.Unchanged),
+			zap.String(OutcomeLabel, outcome))
+		meta.Outcome = outcome
+		c.deliver(logger, meta, items, false)
+		if f := c.observer.onPollSuccess; f != nil {
+			if !c.observer.sharedItems {
+				items = items.DeepCopy()
+			}
+			c.callback(logger, func() { f(items) })
+		}
+	}
+	c.observer.measures.Polls.With(prometheus.Labels{
+		OutcomeLabel: outcome}).Add(1)
+	c.observePoll(outcome, time.Since(start), fetchedAt, fetched)
+	kind, code := classifyPollError(err)
+	c.setStatus(PollStatus{
+		PollSeq:    meta.PollSeq,
+		At:         fetchedAt,
+		Outcome:    outcome,
+		Kind:       kind,
+		StatusCode: code,
+		Err:        err,
+	})
+	return outcome, pollErr
+}
+
+// observePoll updates the optional poll health metrics: the duration of every
+// poll, and the time and item count of successful ones.
+func (c *ListenerClient) observePoll(outcome string, duration time.Duration, fetchedAt time.Time, fetched int) {
+	m := c.observer.measures
+	if m.PollDuration != nil {
+		m.PollDuration.With(prometheus.Labels{OutcomeLabel: outcome}).Observe(duration.Seconds())
+	}
+	if outcome != SuccessOutcome {
+		return
+	}
+	if m.LastSuccessfulPoll != nil {
+		m.LastSuccessfulPoll.Set(float64(fetchedAt.UnixNano()) / float64(time.Second))
+	}
+	if m.ItemsFetched != nil {
+		m.ItemsFetched.Set(float64(fetched))
+	}
+}
+
+// AddListener adds a listener to be called on every poll along with the
+// current ones. A nil listener is ignored.
+func (c *ListenerClient) AddListener(l Listener) {
+	if c.observer == nil || l == nil {
+		return
+	}
+	c.observer.listenersLock.Lock()
+	defer c.observer.listenersLock.Unlock()
+	c.observer.listeners = append(c.observer.listeners, l)
+}
+
+func (c *ListenerClient) getListeners() []Listener {
+	c.observer.listenersLock.RLock()
+	defer c.observer.listenersLock.RUnlock()
+	return c.observer.listeners
+}
+
+// deliver gives the poll's items to each listener, or for a failed poll or
+// unchanged items only to the MetaListeners. A panicking listener is logged and
+// doesn't keep the others from their update.
+func (c *ListenerClient) deliver(logger *zap.Logger, meta UpdateMeta, items Items, failed bool) {
+	for _, l := range c.getListeners() {
+		ml, isMeta := l.(MetaListener)
+		if (failed || meta.Unchanged) && !isMeta {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("Listener panicked", zap.Any("panic", r), zap.Stack("stack"))
 				}
-				c.observer.measures.Polls.With(prometheus.Labels{
-					OutcomeLabel: outcome}).Add(1)
+			}()
+			delivered := items
+			if !c.observer.sharedItems {
+				delivered = items.DeepCopy()
 			}
+			if isMeta {
+				ml.UpdateWithMeta(meta, delivered)
+			} else {
+				l.Update(delivered)
+			}
+		}()
+	}
+}
+
+// pollFailed wraps the poll's error with ErrPollFailed, giving it to
+// OnPollError if set.
+func (c *ListenerClient) pollFailed(logger *zap.Logger, err error) error {
+	err = fmt.Errorf("%w: %w", ErrPollFailed, err)
+	if f := c.observer.onPollError; f != nil {
+		c.callback(logger, func() { f(err) })
+	}
+	return err
+}
+
+// callback queues the poll callback while the client is started, dropping it
+// when the queue is full, and otherwise calls it right away.
+func (c *ListenerClient) callback(logger *zap.Logger, f func()) {
+	if c.observer.callbacks == nil {
+		safeCallback(logger, f)
+		return
+	}
+	select {
+	case c.observer.callbacks <- f:
+	default:
+		logger.Warn("Dropping poll callback, the previous ones haven't returned")
+	}
+}
+
+// runCallbacks calls the queued poll callbacks in order until the queue is closed.
+func runCallbacks(logger *zap.Logger, callbacks <-chan func()) {
+	for f := range callbacks {
+		safeCallback(logger, f)
+	}
+}
+
+// safeCallback calls the poll callback, logging it if it panics.
+func safeCallback(logger *zap.Logger, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Poll callback panicked", zap.Any("panic", r), zap.Stack("stack"))
 		}
 	}()
+	f()
+}
 
-	atomic.SwapInt32(&c.observer.state, running)
-	return nil
+// unchanged reports whether the items to deliver are the same as the ones last
+// delivered, remembering their hash for the next poll. It's always false when
+// AlwaysNotify is set, and when the items can't be hashed.
+func (c *ListenerClient) unchanged(logger *zap.Logger, items Items) bool {
+	o := c.observer
+	if o.alwaysNotify {
+		return false
+	}
+	hash, err := items.Hash()
+	if err != nil {
+		logger.Warn("Failed to hash items, delivering them as changed", zap.Error(err))
+		o.hashed = false
+		return false
+	}
+	unchanged := o.hashed && hash == o.lastHash
+	o.lastHash, o.hashed = hash, true
+	return unchanged
+}
+
+// nextInterval returns the interval until the next poll given the outcome of
+// the latest one, backing off after failures when MaxFailureBackoff is set.
+func (c *ListenerClient) nextInterval(outcome string) time.Duration {
+	o := c.observer
+	if (outcome != FailureOutcome && outcome != OversizedOutcome) || o.maxFailureBackoff <= 0 {
+		o.failures = 0
+		return o.pullInterval
+	}
+
+	o.failures++
+	interval := o.pullInterval
+	for i := 0; i < o.failures && interval < o.maxFailureBackoff; i++ {
+		interval *= 2
+	}
+	return min(interval, max(o.maxFailureBackoff, o.pullInterval))
+}
+
+// jitter returns the interval randomized by up to PullJitter of it either way.
+func (c *ListenerClient) jitter(interval time.Duration) time.Duration {
+	f := c.observer.pullJitter
+	if f <= 0 {
+		return interval
+	}
+	return interval + time.Duration(float64(interval)*f*(2*rand.Float64()-1))
+}
+
+// oversized reports whether the given items exceed MaxItemsPerPoll.
+func (c *ListenerClient) oversized(items Items) bool {
+	return c.observer.maxItemsPerPoll > 0 && len(items) > c.observer.maxItemsPerPoll
+}
+
+// truncateItems returns the first n of the given items sorted by ID, leaving
+// the given items untouched.
+func truncateItems(items Items, n int) Items {
+	return items.Sorted()[:n]
+}
+
+// withholdEmpty reports whether the given items are an unconfirmed empty list
+// that shouldn't be delivered to the listener yet.
+func (c *ListenerClient) withholdEmpty(items Items) bool {
+	o := c.observer
+	if len(items) > 0 {
+		o.emptyPolls = 0
+		o.delivered = true
+		return false
+	}
+
+	if o.delivered && o.emptyListConfirmations > 1 {
+		o.emptyPolls++
+		if o.emptyPolls < o.emptyListConfirmations {
+			return true
+		}
+	}
+
+	o.emptyPolls = 0
+	o.delivered = false
+	return false
 }
 
-// Stop requests the current listener process to stop and waits for its goroutine to complete.
-// Calling Stop() when a listener is not running (or while one is getting stopped) returns an
-// error.
+// Stop requests the current listener process to stop and waits for its goroutine to complete,
+// which returns right away if the goroutine has already exited. Stop is idempotent and safe to
+// call concurrently: calling it when the listener is stopped is a no op. If ctx is done before
+// the goroutine completes, Stop returns ctx.Err() and the listener stays stopping until a later
+// Stop call sees the goroutine complete.
 func (c *ListenerClient) Stop(ctx context.Context) error {
 	if c.observer == nil || c.observer.ticker == nil {
 		return nil
 	}
 
-	if !atomic.CompareAndSwapInt32(&c.observer.state, running, transitioning) {
-		c.logger.Error("Stop called when a listener was not in running state", zap.Error(ErrListenerNotStopped))
-		return ErrListenerNotRunning
+	o := c.observer
+	o.runLock.Lock()
+	defer o.runLock.Unlock()
+	if atomic.LoadInt32(&o.state) == stopped {
+		return nil
+	}
+
+	atomic.StoreInt32(&o.state, transitioning)
+	if o.shutdown != nil {
+		o.ticker.Stop()
+		close(o.shutdown)
+		o.shutdown = nil
+	}
+	select {
+	case <-o.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// the polling goroutine is done and Refresh waits for pollLock, so a
+	// restart delivers the current items and no more callbacks are queued.
+	// The queued ones still run.
+	o.done = nil
+	o.pollLock.Lock()
+	o.hashed = false
+	if o.callbacks != nil {
+		close(o.callbacks)
+		o.callbacks = nil
 	}
+	o.pollLock.Unlock()
+	atomic.StoreInt32(&o.state, stopped)
+	return nil
+}
 
-	c.observer.ticker.Stop()
-	c.observer.shutdown <- struct{}{}
-	atomic.SwapInt32(&c.observer.state, stopped)
+// Close stops the listener if it is running and releases its ticker. Start
+// fails with ErrClientClosed after Close. Calling Close more than once is a no op.
+func (c *ListenerClient) Close() error {
+	if c.observer == nil || c.observer.closed.Swap(true) {
+		return nil
+	}
+	if atomic.LoadInt32(&c.observer.state) != stopped {
+		if err := c.Stop(context.Background()); err != nil {
+			return err
+		}
+	}
+	if c.observer.ticker != nil {
+		c.observer.ticker.Stop()
+	}
 	return nil
 }
 
 func validateListenerConfig(config *ListenerClientConfig) error {
-	if config.Listener == nil {
+	if config.Listener == nil && !slices.ContainsFunc(config.Listeners, func(l Listener) bool { return l != nil }) {
 		return ErrNoListenerProvided
 	}
 	if config.Logger == nil {
@@ -178,5 +748,8 @@ func validateListenerConfig(config *ListenerClientConfig) error {
 	if config.PullInterval == 0 {
 		config.PullInterval = defaultPullInterval
 	}
+	if config.PullJitter < 0 || config.PullJitter >= 1 {
+		return fmt.Errorf("%w: %v", ErrInvalidPullJitter, config.PullJitter)
+	}
 	return nil
 }
@@ -10,13 +10,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/model"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 var (
@@ -145,6 +150,29 @@ func TestValidateListenerConfig(t *testing.T) {
 				Listener: mockListener,
 			},
 		},
+		{
+			desc: "Pull jitter Success",
+			config: ListenerClientConfig{
+				Listener:   mockListener,
+				PullJitter: 0.1,
+			},
+		},
+		{
+			desc: "Negative pull jitter Failure",
+			config: ListenerClientConfig{
+				Listener:   mockListener,
+				PullJitter: -0.1,
+			},
+			expectedErr: ErrInvalidPullJitter,
+		},
+		{
+			desc: "Pull jitter of 1 Failure",
+			config: ListenerClientConfig{
+				Listener:   mockListener,
+				PullJitter: 1,
+			},
+			expectedErr: ErrInvalidPullJitter,
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -201,3 +229,966 @@ func TestNewListenerClient(t *testing.T) {
 		})
 	}
 }
+
+type sequenceReader struct {
+	results []Items
+	errs    []error
+}
+
+func (s *sequenceReader) GetItems(context.Context, string) (Items, error) {
+	items, err := s.results[0], s.errs[0]
+	s.results, s.errs = s.results[1:], s.errs[1:]
+	return items, err
+}
+
+func TestListenerEmptyListConfirmations(t *testing.T) {
+	nonEmpty := Items{{ID: "1"}}
+	tcs := []struct {
+		desc          string
+		confirmations int
+		polls         []Items
+		expected      []Items
+	}{
+		{
+			desc:          "Transient empty list withheld",
+			confirmations: 3,
+			polls:         []Items{nonEmpty, {}, {}, nonEmpty},
+			expected:      []Items{nonEmpty, nonEmpty},
+		},
+		{
+			desc:          "Genuinely empty bucket delivered after confirmations",
+			confirmations: 3,
+			polls:         []Items{nonEmpty, {}, {}, {}, {}},
+			expected:      []Items{nonEmpty, {}, {}},
+		},
+		{
+			desc:          "Empty list delivered right away when nothing was delivered before",
+			confirmations: 3,
+			polls:         []Items{{}, nonEmpty},
+			expected:      []Items{{}, nonEmpty},
+		},
+		{
+			desc:          "Confirmation count restarts after a non-empty list",
+			confirmations: 2,
+			polls:         []Items{nonEmpty, {}, nonEmpty, {}, {}},
+			expected:      []Items{nonEmpty, nonEmpty, {}},
+		},
+		{
+			desc:     "Disabled",
+			polls:    []Items{nonEmpty, {}, nonEmpty},
+			expected: []Items{nonEmpty, {}, nonEmpty},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var delivered []Items
+			config := ListenerClientConfig{
+				Listener: ListenerFunc(func(items Items) {
+					delivered = append(delivered, items)
+				}),
+				EmptyListConfirmations: tc.confirmations,
+				AlwaysNotify:           true,
+			}
+			reader := &sequenceReader{results: tc.polls, errs: make([]error, len(tc.polls))}
+			client, err := NewListenerClient(config, nil, mockMeasures, reader)
+			require.NoError(err)
+			for range tc.polls {
+				client.poll()
+			}
+			assert.Equal(tc.expected, delivered)
+		})
+	}
+}
+
+func TestListenerMaxItemsPerPoll(t *testing.T) {
+	small := Items{{ID: "b"}, {ID: "a"}}
+	oversized := make(Items, 1000)
+	for i := range oversized {
+		oversized[i] = model.Item{ID: fmt.Sprintf("%04d", len(oversized)-i)}
+	}
+	tcs := []struct {
+		desc             string
+		truncate         bool
+		expectedOutcomes []string
+		expected         []Items
+		expectedLog      string
+	}{
+		{
+			// listeners keep the small items through the rejected poll, so the
+			// next poll's are unchanged and not delivered again.
+			desc:             "Oversized poll fails",
+			expectedOutcomes: []string{SuccessOutcome, OversizedOutcome, SuccessOutcome},
+			expected:         []Items{small},
+			expectedLog:      "Rejecting poll with more items than the maximum per poll",
+		},
+		{
+			desc:             "Oversized poll truncated",
+			truncate:         true,
+			expectedOutcomes: []string{SuccessOutcome, SuccessOutcome, SuccessOutcome},
+			expected:         []Items{small, {{ID: "0001"}, {ID: "0002"}, {ID: "0003"}}, small},
+			expectedLog:      "Truncating poll with more items than the maximum per poll",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			core, logs := observer.New(zapcore.WarnLevel)
+			var delivered []Items
+			client, err := NewListenerClient(ListenerClientConfig{
+				Listener: ListenerFunc(func(items Items) {
+					delivered = append(delivered, items)
+				}),
+				Logger:                 zap.New(core),
+				MaxItemsPerPoll:        3,
+				TruncateOversizedPolls: tc.truncate,
+			}, nil, mockMeasures, &sequenceReader{
+				results: []Items{small, oversized, small},
+				errs:    make([]error, 3),
+			})
+			require.NoError(err)
+
+			var outcomes []string
+			for range 3 {
+				outcomes = append(outcomes, client.poll())
+			}
+			assert.Equal(tc.expectedOutcomes, outcomes)
+			assert.Equal(tc.expected, delivered)
+			assert.Equal("1000", oversized[0].ID, "the reader's items must not be reordered")
+			entries := logs.FilterMessage(tc.expectedLog).AllUntimed()
+			if assert.Len(entries, 1) {
+				assert.Equal(int64(1000), entries[0].ContextMap()["item_count"])
+			}
+		})
+	}
+}
+
+func TestListenerPollLogFields(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	core, logs := observer.New(zapcore.DebugLevel)
+	config := ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {}),
+		Logger:   zap.New(core),
+	}
+	setLogger := func(ctx context.Context, l *zap.Logger) context.Context {
+		l.Debug("Getting items")
+		return ctx
+	}
+	reader := &sequenceReader{
+		results: []Items{nil, {{ID: "1"}, {ID: "2"}}},
+		errs:    []error{errors.New("failed"), nil},
+	}
+	client, err := NewListenerClient(config, setLogger, mockMeasures, reader)
+	require.NoError(err)
+	client.poll()
+	client.poll()
+
+	entries := logs.AllUntimed()
+	require.Len(entries, 4)
+	for i, seq := range []int64{1, 1, 2, 2} {
+		fields := entries[i].ContextMap()
+		assert.EqualValues(seq, fields["poll_seq"], entries[i].Message)
+		assert.Contains(fields, "started_at")
+	}
+	assert.Equal("Failed to get items for listeners", entries[1].Message)
+	assert.Equal("Updating listeners", entries[3].Message)
+	assert.EqualValues(2, entries[3].ContextMap()["item_count"])
+	assert.Contains(entries[3].ContextMap(), "duration")
+	assert.Equal(SuccessOutcome, entries[3].ContextMap()[OutcomeLabel])
+}
+
+func TestListenerPollInfoLogEvery(t *testing.T) {
+	tcs := []struct {
+		desc           string
+		infoLogEvery   int
+		expectedLevels []zapcore.Level
+	}{
+		{
+			desc:           "Always debug",
+			expectedLevels: []zapcore.Level{zap.DebugLevel, zap.DebugLevel, zap.DebugLevel, zap.DebugLevel},
+		},
+		{
+			desc:           "Every second poll at info",
+			infoLogEvery:   2,
+			expectedLevels: []zapcore.Level{zap.DebugLevel, zap.InfoLevel, zap.DebugLevel, zap.InfoLevel},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			core, logs := observer.New(zapcore.DebugLevel)
+			config := ListenerClientConfig{
+				Listener:     ListenerFunc(func(Items) {}),
+				Logger:       zap.New(core),
+				InfoLogEvery: tc.infoLogEvery,
+			}
+			reader := &sequenceReader{
+				results: []Items{{{ID: "1"}}, {{ID: "1"}}, {{ID: "1"}}, {{ID: "1"}}},
+				errs:    []error{nil, nil, nil, nil},
+			}
+			client, err := NewListenerClient(config, nil, mockMeasures, reader)
+			require.NoError(err)
+			for range tc.expectedLevels {
+				client.poll()
+			}
+
+			entries := logs.FilterMessage("Updating listeners").AllUntimed()
+			require.Len(entries, len(tc.expectedLevels))
+			for i, level := range tc.expectedLevels {
+				assert.Equal(level, entries[i].Level)
+			}
+		})
+	}
+}
+
+type metaListener struct {
+	metas []UpdateMeta
+	items []Items
+}
+
+func (l *metaListener) Update(Items) {
+	panic("Update called on a MetaListener")
+}
+
+func (l *metaListener) UpdateWithMeta(meta UpdateMeta, items Items) {
+	l.metas = append(l.metas, meta)
+	l.items = append(l.items, items)
+}
+
+func TestListenerUpdateMeta(t *testing.T) {
+	tcs := []struct {
+		desc               string
+		deliverFailedPolls bool
+		expectedOutcomes   []string
+		expectedSeqs       []uint64
+		expectedItems      []Items
+	}{
+		{
+			desc:             "Successes only",
+			expectedOutcomes: []string{SuccessOutcome, SuccessOutcome},
+			expectedSeqs:     []uint64{1, 3},
+			expectedItems:    []Items{{{ID: "1"}}, {{ID: "2"}}},
+		},
+		{
+			desc:               "Failed polls delivered",
+			deliverFailedPolls: true,
+			expectedOutcomes:   []string{SuccessOutcome, FailureOutcome, SuccessOutcome},
+			expectedSeqs:       []uint64{1, 2, 3},
+			expectedItems:      []Items{{{ID: "1"}}, nil, {{ID: "2"}}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			listener := &metaListener{}
+			config := ListenerClientConfig{
+				Listener:           listener,
+				DeliverFailedPolls: tc.deliverFailedPolls,
+			}
+			reader := &sequenceReader{
+				results: []Items{{{ID: "1"}}, nil, {{ID: "2"}}},
+				errs:    []error{nil, errors.New("failed"), nil},
+			}
+			client, err := NewListenerClient(config, nil, mockMeasures, reader)
+			require.NoError(err)
+			before := time.Now()
+			for range reader.results {
+				client.poll()
+			}
+
+			require.Len(listener.metas, len(tc.expectedOutcomes))
+			assert.Equal(tc.expectedItems, listener.items)
+			for i, meta := range listener.metas {
+				assert.Equal(tc.expectedOutcomes[i], meta.Outcome)
+				assert.Equal(tc.expectedSeqs[i], meta.PollSeq)
+				assert.False(meta.FetchedAt.Before(before))
+				assert.False(meta.FetchedAt.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestListenerChangeDetection(t *testing.T) {
+	a := model.Item{ID: "a", Data: map[string]interface{}{"v": 1}}
+	b := model.Item{ID: "b", Data: map[string]interface{}{"v": 2}}
+	changed := model.Item{ID: "b", Data: map[string]interface{}{"v": 3}}
+	polls := []Items{{a, b}, {b, a}, {a, changed}, {a, changed}}
+	tcs := []struct {
+		desc              string
+		alwaysNotify      bool
+		expectedDelivered []Items
+		expectedUnchanged []bool
+	}{
+		{
+			desc:              "Unchanged polls skipped",
+			expectedDelivered: []Items{{a, b}, {a, changed}},
+			expectedUnchanged: []bool{false, true, false, true},
+		},
+		{
+			desc:              "Always notify",
+			alwaysNotify:      true,
+			expectedDelivered: polls,
+			expectedUnchanged: []bool{false, false, false, false},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var delivered []Items
+			meta := &metaListener{}
+			client, err := NewListenerClient(ListenerClientConfig{
+				Listeners: []Listener{
+					ListenerFunc(func(items Items) { delivered = append(delivered, items) }),
+					meta,
+				},
+				AlwaysNotify: tc.alwaysNotify,
+			}, nil, mockMeasures, &sequenceReader{
+				results: polls,
+				errs:    make([]error, len(polls)),
+			})
+			require.NoError(err)
+			for range polls {
+				assert.Equal(SuccessOutcome, client.poll())
+			}
+
+			assert.Equal(tc.expectedDelivered, delivered)
+			assert.Equal(polls, meta.items)
+			require.Len(meta.metas, len(tc.expectedUnchanged))
+			for i, m := range meta.metas {
+				assert.Equal(tc.expectedUnchanged[i], m.Unchanged)
+			}
+		})
+	}
+}
+
+func TestListenerChangeDetectionRestart(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	items := Items{{ID: "a"}}
+	var delivered []Items
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     ListenerFunc(func(items Items) { delivered = append(delivered, items) }),
+		PullInterval: time.Hour,
+	}, nil, mockMeasures, &sequenceReader{
+		results: []Items{items, items, items},
+		errs:    make([]error, 3),
+	})
+	require.NoError(err)
+
+	client.poll()
+	client.poll()
+	assert.Len(delivered, 1)
+
+	// the first poll after a restart is delivered even if nothing changed.
+	require.NoError(client.Start(context.Background()))
+	require.NoError(client.Stop(context.Background()))
+	client.poll()
+	assert.Equal([]Items{items, items}, delivered)
+}
+
+func TestListenerPollCallbacks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	failed := errors.New("failed")
+	var (
+		errs      []error
+		successes []Items
+	)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:        ListenerFunc(func(Items) {}),
+		MaxItemsPerPoll: 1,
+		OnPollError:     func(err error) { errs = append(errs, err) },
+		OnPollSuccess:   func(items Items) { successes = append(successes, items) },
+	}, nil, mockMeasures, &sequenceReader{
+		results: []Items{{{ID: "1"}}, nil, {{ID: "2"}}, {{ID: "3"}, {ID: "4"}}},
+		errs:    []error{nil, failed, nil, nil},
+	})
+	require.NoError(err)
+	for range 4 {
+		client.poll()
+	}
+
+	assert.Equal([]Items{{{ID: "1"}}, {{ID: "2"}}}, successes)
+	require.Len(errs, 2)
+	assert.ErrorIs(errs[0], ErrPollFailed)
+	assert.ErrorIs(errs[0], failed)
+	assert.ErrorIs(errs[1], ErrPollFailed)
+	assert.ErrorIs(errs[1], ErrOversizedPoll)
+
+	// nil callbacks are skipped.
+	client, err = NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {}),
+	}, nil, mockMeasures, &sequenceReader{
+		results: []Items{nil, {{ID: "1"}}},
+		errs:    []error{failed, nil},
+	})
+	require.NoError(err)
+	assert.Equal(FailureOutcome, client.poll())
+	assert.Equal(SuccessOutcome, client.poll())
+}
+
+// alternatingReader fails every other poll.
+type alternatingReader struct {
+	polls atomic.Int32
+}
+
+func (r *alternatingReader) GetItems(context.Context, string) (Items, error) {
+	if r.polls.Add(1)%2 == 0 {
+		return nil, errors.New("failed")
+	}
+	return Items{{ID: "1"}}, nil
+}
+
+func TestListenerPollCallbacksStarted(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var (
+		mu     sync.Mutex
+		calls  []string
+		called = make(chan struct{}, 100)
+		block  = make(chan struct{})
+	)
+	record := func(call string) {
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+		called <- struct{}{}
+		<-block
+	}
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:      ListenerFunc(func(Items) {}),
+		PullInterval:  time.Millisecond,
+		OnPollError:   func(error) { record("error") },
+		OnPollSuccess: func(Items) { record("success") },
+	}, nil, mockMeasures, &alternatingReader{})
+	require.NoError(err)
+
+	require.NoError(client.Start(context.Background()))
+	<-called
+	// the first callback is blocked, which must not keep Stop from returning.
+	stopped := make(chan error)
+	go func() { stopped <- client.Stop(context.Background()) }()
+	select {
+	case err := <-stopped:
+		assert.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop was blocked by a poll callback")
+	}
+
+	// the callbacks queued before Stop still run, in poll order.
+	close(block)
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(calls)
+	for i, call := range calls {
+		expected := "success"
+		if i%2 == 1 {
+			expected = "error"
+		}
+		assert.Equal(expected, call)
+	}
+}
+
+func TestListenerPullJitter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     mockListener,
+		PullInterval: time.Second,
+	}, nil, mockMeasures, &sequenceReader{})
+	require.NoError(err)
+	for range 100 {
+		assert.Equal(time.Second, client.jitter(time.Second))
+	}
+
+	client, err = NewListenerClient(ListenerClientConfig{
+		Listener:     mockListener,
+		PullInterval: time.Second,
+		PullJitter:   0.1,
+	}, nil, mockMeasures, &sequenceReader{})
+	require.NoError(err)
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		d := client.jitter(time.Second)
+		assert.GreaterOrEqual(d, 900*time.Millisecond)
+		assert.LessOrEqual(d, 1100*time.Millisecond)
+		seen[d] = true
+	}
+	assert.Greater(len(seen), 1)
+
+	// Stop cancels the jittered ticker promptly.
+	require.NoError(client.Start(context.Background()))
+	start := time.Now()
+	require.NoError(client.Stop(context.Background()))
+	assert.Less(time.Since(start), 500*time.Millisecond)
+}
+
+func TestListenerStopAfterGoroutineExit(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	core, logs := observer.New(zapcore.ErrorLevel)
+	// the sequenceReader has no results, so the first poll panics.
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     ListenerFunc(func(Items) {}),
+		PullInterval: time.Millisecond,
+		Logger:       zap.New(core),
+	}, nil, mockMeasures, &sequenceReader{})
+	require.NoError(err)
+
+	require.NoError(client.Start(context.Background()))
+	select {
+	case <-client.observer.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the polling goroutine didn't exit")
+	}
+	assert.Equal(1, logs.FilterMessage("Listener goroutine panicked, polling has stopped").Len())
+
+	result := make(chan error)
+	go func() { result <- client.Stop(context.Background()) }()
+	select {
+	case err := <-result:
+		assert.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop hung after the polling goroutine exited")
+	}
+	assert.Equal(stopped, client.observer.state)
+
+	// the client can be restarted.
+	client.reader = &sequenceReader{results: []Items{{}}, errs: []error{nil}}
+	client.observer.pullInterval = time.Hour
+	require.NoError(client.Start(context.Background()))
+	require.NoError(client.Stop(context.Background()))
+}
+
+func TestListenerStopIdempotent(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     ListenerFunc(func(Items) {}),
+		PullInterval: time.Hour,
+	}, nil, mockMeasures, &sequenceReader{})
+	require.NoError(err)
+
+	assert.NoError(client.Stop(context.Background()))
+	require.NoError(client.Start(context.Background()))
+	assert.NoError(client.Stop(context.Background()))
+	assert.NoError(client.Stop(context.Background()))
+	assert.Equal(stopped, client.observer.state)
+
+	require.NoError(client.Start(context.Background()))
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- client.Stop(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(err)
+	}
+	assert.Equal(stopped, client.observer.state)
+}
+
+func TestListenerStopContextDone(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	block, polled := make(chan struct{}), make(chan struct{})
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {
+			close(polled)
+			<-block
+		}),
+		PullInterval: time.Millisecond,
+	}, nil, mockMeasures, &sequenceReader{results: []Items{{}, {}}, errs: []error{nil, nil}})
+	require.NoError(err)
+
+	require.NoError(client.Start(context.Background()))
+	<-polled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(client.Stop(ctx), context.DeadlineExceeded)
+	assert.Equal(transitioning, client.observer.state)
+	assert.ErrorIs(client.Start(context.Background()), ErrListenerNotStopped)
+
+	close(block)
+	assert.NoError(client.Stop(context.Background()))
+	assert.Equal(stopped, client.observer.state)
+}
+
+func TestListenerState(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     ListenerFunc(func(Items) {}),
+		PullInterval: time.Hour,
+	}, nil, mockMeasures, &sequenceReader{})
+	require.NoError(err)
+
+	assert.Equal(ListenerStopped, client.State())
+	require.NoError(client.Start(context.Background()))
+	assert.Equal(ListenerRunning, client.State())
+	require.NoError(client.Stop(context.Background()))
+	assert.Equal(ListenerStopped, client.State())
+
+	assert.Equal("stopped", ListenerStopped.String())
+	assert.Equal("running", ListenerRunning.String())
+	assert.Equal("transitioning", ListenerTransitioning.String())
+	assert.Equal("unknown", ListenerState(-1).String())
+}
+
+func TestListenerRefresh(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	errFetch := errors.New("failed")
+	polls := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel})
+	var updates []Items
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener:     ListenerFunc(func(items Items) { updates = append(updates, items) }),
+		PullInterval: time.Hour,
+	}, nil, &Measures{Polls: polls}, &sequenceReader{
+		results: []Items{{{ID: "1"}}, nil},
+		errs:    []error{nil, errFetch},
+	})
+	require.NoError(err)
+
+	assert.ErrorIs(client.Refresh(context.Background()), ErrListenerNotRunning)
+
+	require.NoError(client.Start(context.Background()))
+	defer client.Stop(context.Background())
+	assert.NoError(client.Refresh(context.Background()))
+	assert.Equal([]Items{{{ID: "1"}}}, updates)
+	assert.Equal(uint64(1), client.Status().PollSeq)
+
+	err = client.Refresh(context.Background())
+	assert.ErrorIs(err, ErrPollFailed)
+	assert.ErrorIs(err, errFetch)
+	assert.Equal([]Items{{{ID: "1"}}}, updates)
+	assert.Equal(FailureOutcome, client.Status().Outcome)
+
+	assert.Equal(1.0, counterValue(t, polls.With(prometheus.Labels{OutcomeLabel: SuccessOutcome})))
+	assert.Equal(1.0, counterValue(t, polls.With(prometheus.Labels{OutcomeLabel: FailureOutcome})))
+}
+
+// versionReader returns a single item whose version grows with every call.
+type versionReader struct {
+	version atomic.Int64
+}
+
+func (r *versionReader) GetItems(context.Context, string) (Items, error) {
+	v := r.version.Add(1)
+	return Items{{ID: "1", Data: map[string]interface{}{"version": v}}}, nil
+}
+
+func TestListenerRefreshConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var (
+		mu       sync.Mutex
+		versions []int64
+	)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(items Items) {
+			mu.Lock()
+			defer mu.Unlock()
+			versions = append(versions, items[0].Data["version"].(int64))
+		}),
+		PullInterval: time.Millisecond,
+	}, nil, mockMeasures, &versionReader{})
+	require.NoError(err)
+
+	require.NoError(client.Start(context.Background()))
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				assert.NoError(client.Refresh(context.Background()))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(client.Stop(context.Background()))
+
+	// every poll fetched a newer version, which reached the listener in order.
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(len(versions), 200)
+	for i := 1; i < len(versions); i++ {
+		assert.Greater(versions[i], versions[i-1])
+	}
+}
+
+func TestListenerPollMetrics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	registry := prometheus.NewRegistry()
+	measures := &Measures{
+		Polls:              prometheus.NewCounterVec(prometheus.CounterOpts{Name: PollCounter}, []string{OutcomeLabel}),
+		LastSuccessfulPoll: prometheus.NewGauge(prometheus.GaugeOpts{Name: LastSuccessfulPollGauge}),
+		PollDuration:       prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: PollDurationHistogram}, []string{OutcomeLabel}),
+		ItemsFetched:       prometheus.NewGauge(prometheus.GaugeOpts{Name: ItemsFetchedGauge}),
+	}
+	registry.MustRegister(measures.Polls, measures.LastSuccessfulPoll,
+		measures.PollDuration, measures.ItemsFetched)
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {}),
+	}, nil, measures, &sequenceReader{
+		results: []Items{{{ID: "1"}, {ID: "2"}}, nil},
+		errs:    []error{nil, errors.New("failed")},
+	})
+	require.NoError(err)
+
+	before := time.Now()
+	client.poll()
+	client.poll()
+
+	families, err := registry.Gather()
+	require.NoError(err)
+	gathered := make(map[string][]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			switch {
+			case m.GetGauge() != nil:
+				gathered[f.GetName()] = append(gathered[f.GetName()], m.GetGauge().GetValue())
+			case m.GetHistogram() != nil:
+				gathered[f.GetName()] = append(gathered[f.GetName()], float64(m.GetHistogram().GetSampleCount()))
+			}
+		}
+	}
+	// the failed poll keeps the gauges of the successful one.
+	assert.Equal([]float64{2}, gathered[ItemsFetchedGauge])
+	require.Len(gathered[LastSuccessfulPollGauge], 1)
+	assert.InDelta(float64(before.Unix()), gathered[LastSuccessfulPollGauge][0], 5)
+	// one sample per outcome.
+	assert.Equal([]float64{1, 1}, gathered[PollDurationHistogram])
+
+	// the health metrics are optional.
+	client, err = NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {}),
+	}, nil, mockMeasures, &sequenceReader{results: []Items{{}}, errs: []error{nil}})
+	require.NoError(err)
+	assert.Equal(SuccessOutcome, client.poll())
+}
+
+// conditionalReader records whether each request revalidated the previous
+// response.
+type conditionalReader struct {
+	sequenceReader
+	revalidated []bool
+}
+
+func (r *conditionalReader) GetItems(ctx context.Context, owner string) (Items, error) {
+	conditional, revalidate := conditionalFrom(ctx)
+	r.revalidated = append(r.revalidated, conditional && revalidate)
+	return r.sequenceReader.GetItems(ctx, owner)
+}
+
+func TestListenerNotModified(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	first, second := Items{{ID: "1"}}, Items{{ID: "1"}, {ID: "2"}}
+	reader := &conditionalReader{sequenceReader: sequenceReader{
+		results: []Items{first, nil, second},
+		errs:    []error{nil, ErrNotModified, nil},
+	}}
+	polls := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel})
+	var updates []Items
+	meta := &metaListener{}
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listeners: []Listener{
+			ListenerFunc(func(items Items) { updates = append(updates, items) }),
+			meta,
+		},
+	}, nil, &Measures{Polls: polls}, reader)
+	require.NoError(err)
+
+	for range 3 {
+		assert.Equal(SuccessOutcome, client.poll())
+	}
+	assert.Equal([]bool{false, true, true}, reader.revalidated)
+	assert.Equal([]Items{first, second}, updates)
+	require.Len(meta.metas, 3)
+	assert.True(meta.metas[1].Unchanged)
+	assert.Equal(first, meta.items[1])
+	assert.Equal(3.0, counterValue(t, polls.With(prometheus.Labels{OutcomeLabel: SuccessOutcome})))
+
+	// a not modified response without previous items is a failure.
+	client, err = NewListenerClient(ListenerClientConfig{
+		Listener: ListenerFunc(func(Items) {}),
+	}, nil, mockMeasures, &sequenceReader{results: []Items{nil}, errs: []error{ErrNotModified}})
+	require.NoError(err)
+	assert.Equal(FailureOutcome, client.poll())
+}
+
+func TestListenerClientClose(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	client, stopServer, err := newStartStopClient(true)
+	require.NoError(err)
+	defer stopServer()
+
+	require.NoError(client.Start(context.Background()))
+	assert.NoError(client.Close())
+	assert.Equal(stopped, atomic.LoadInt32(&client.observer.state))
+	assert.NoError(client.Close())
+	assert.ErrorIs(client.Start(context.Background()), ErrClientClosed)
+
+	// closing a listener that isn't running only releases the ticker.
+	client, stopServer2, err := newStartStopClient(true)
+	require.NoError(err)
+	defer stopServer2()
+	assert.NoError(client.Close())
+	assert.ErrorIs(client.Start(context.Background()), ErrClientClosed)
+}
+
+func TestListenerFailureBackoff(t *testing.T) {
+	failed := errors.New("failed")
+	tcs := []struct {
+		desc              string
+		maxFailureBackoff time.Duration
+		expected          []time.Duration
+	}{
+		{
+			desc:     "Disabled",
+			expected: []time.Duration{time.Second, time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			desc:              "Capped and reset",
+			maxFailureBackoff: 5 * time.Second,
+			expected:          []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, time.Second, 2 * time.Second},
+		},
+		{
+			desc:              "Cap below the pull interval",
+			maxFailureBackoff: time.Millisecond,
+			expected:          []time.Duration{time.Second, time.Second, time.Second, time.Second, time.Second},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			reader := &sequenceReader{
+				results: []Items{nil, nil, nil, {{ID: "1"}}, nil},
+				errs:    []error{failed, failed, failed, nil, failed},
+			}
+			client, err := NewListenerClient(ListenerClientConfig{
+				Listener:          ListenerFunc(func(Items) {}),
+				PullInterval:      time.Second,
+				MaxFailureBackoff: tc.maxFailureBackoff,
+			}, nil, mockMeasures, reader)
+			require.NoError(err)
+
+			intervals := make([]time.Duration, 0, len(tc.expected))
+			for range tc.expected {
+				intervals = append(intervals, client.nextInterval(client.poll()))
+			}
+			assert.Equal(tc.expected, intervals)
+		})
+	}
+
+	t.Run("Reset by Start", func(t *testing.T) {
+		require := require.New(t)
+		client, err := NewListenerClient(ListenerClientConfig{
+			Listener:          ListenerFunc(func(Items) {}),
+			PullInterval:      time.Hour,
+			MaxFailureBackoff: 4 * time.Hour,
+		}, nil, mockMeasures, &sequenceReader{})
+		require.NoError(err)
+		client.observer.failures = 3
+		require.NoError(client.Start(context.Background()))
+		assert.Zero(t, client.observer.failures)
+		require.NoError(client.Stop(context.Background()))
+	})
+}
+
+func TestListenerFanOut(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	reader := &sequenceReader{
+		results: []Items{{{ID: "1"}}, nil},
+		errs:    []error{nil, errors.New("failed")},
+	}
+	var first, added []Items
+	meta := &metaListener{}
+	client, err := NewListenerClient(ListenerClientConfig{
+		Listeners: []Listener{
+			nil,
+			ListenerFunc(func(items Items) { first = append(first, items) }),
+			ListenerFunc(func(Items) { panic("listener bug") }),
+			meta,
+		},
+		DeliverFailedPolls: true,
+	}, nil, mockMeasures, reader)
+	require.NoError(err)
+	client.AddListener(ListenerFunc(func(items Items) { added = append(added, items) }))
+	client.AddListener(nil)
+	assert.Len(client.getListeners(), 4)
+
+	assert.Equal(SuccessOutcome, client.poll())
+	assert.Equal(FailureOutcome, client.poll())
+
+	// only MetaListeners are told about the failed poll.
+	assert.Equal([]Items{{{ID: "1"}}}, first)
+	assert.Equal([]Items{{{ID: "1"}}}, added)
+	assert.Equal([]Items{{{ID: "1"}}, nil}, meta.items)
+}
+
+func TestListenerItemIsolation(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		shared      bool
+		expectedURL string
+	}{
+		{
+			desc:        "Copies",
+			expectedURL: "https://example.com",
+		},
+		{
+			desc:        "Shared items",
+			shared:      true,
+			expectedURL: "https://normalized.example.com",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			items := deepCopyFixture()
+			var seen string
+			client, err := NewListenerClient(ListenerClientConfig{
+				Listeners: []Listener{
+					ListenerFunc(func(items Items) {
+						items[0].Data["config"].(map[string]interface{})["url"] = "https://normalized.example.com"
+					}),
+					ListenerFunc(func(items Items) {
+						seen = items[0].Data["config"].(map[string]interface{})["url"].(string)
+					}),
+				},
+				SharedItems: tc.shared,
+			}, nil, mockMeasures, &sequenceReader{results: []Items{items}, errs: []error{nil}})
+			require.NoError(err)
+
+			client.poll()
+			assert.Equal(tc.expectedURL, seen)
+			assert.Equal(tc.expectedURL, items[0].Data["config"].(map[string]interface{})["url"])
+		})
+	}
+}
+
+func TestValidateListenerConfigListeners(t *testing.T) {
+	c := ListenerClientConfig{Listeners: []Listener{nil}}
+	assert.ErrorIs(t, validateListenerConfig(&c), ErrNoListenerProvided)
+	c = ListenerClientConfig{Listeners: []Listener{mockListener}}
+	assert.NoError(t, validateListenerConfig(&c))
+}
@@ -4,6 +4,8 @@
 package chrysom
 
 import (
+	"fmt"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/xmidt-org/touchstone"
 	"go.uber.org/fx"
@@ -11,18 +13,45 @@ import (
 
 // Names
 const (
-	PollCounter = "chrysom_polls_total"
+	PollCounter                = "chrysom_polls_total"
+	OwnershipMismatchesCounter = "chrysom_ownership_mismatches_total"
+	RequestRetriesCounter      = "chrysom_request_retries_total"
+	RequestsCounter            = "ancla_chrysom_requests_total"
+	DuplicateItemsCounter      = "ancla_chrysom_duplicate_items_total"
+	OperationDurationHistogram = "ancla_chrysom_operation_duration_seconds"
+	LastSuccessfulPollGauge    = "chrysom_last_successful_poll_timestamp"
+	PollDurationHistogram      = "chrysom_poll_duration_seconds"
+	ItemsFetchedGauge          = "chrysom_items_fetched"
+	LastPollStatusGauge        = "ancla_chrysom_last_poll_status"
 )
 
 // Labels
 const (
-	OutcomeLabel = "outcome"
+	OutcomeLabel   = "outcome"
+	MethodLabel    = "method"
+	CodeClassLabel = "code_class"
+	OperationLabel = "operation"
+	KindLabel      = "kind"
 )
 
 // Label Values
 const (
 	SuccessOutcome = "success"
 	FailureOutcome = "failure"
+	// WithheldOutcome is used for polls whose result wasn't delivered to the listener.
+	WithheldOutcome = "withheld"
+	// OversizedOutcome is used for polls rejected for returning more than
+	// ListenerClientConfig.MaxItemsPerPoll items.
+	OversizedOutcome = "oversized"
+
+	// TransportErrorCodeClass is used for requests that got no response.
+	TransportErrorCodeClass = "transport_error"
+
+	// OperationLabel values, one per BasicClient method that sends requests.
+	GetItemsOperation   = "get_items"
+	GetItemOperation    = "get_item"
+	PushItemOperation   = "push_item"
+	RemoveItemOperation = "remove_item"
 )
 
 // Metrics returns the Metrics relevant to this package
@@ -35,10 +64,88 @@ func ProvideMetrics() fx.Option {
 			},
 			OutcomeLabel,
 		),
+		touchstone.Counter(
+			prometheus.CounterOpts{
+				Name: OwnershipMismatchesCounter,
+				Help: "Counter for the number of fetched items dropped because their owner didn't match the requested owner.",
+			},
+		),
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: RequestRetriesCounter,
+				Help: "Counter for the number of retried requests to Argus, by HTTP method.",
+			},
+			MethodLabel,
+		),
+		touchstone.CounterVec(
+			prometheus.CounterOpts{
+				Name: RequestsCounter,
+				Help: "Counter for the number of requests to Argus, by HTTP method, BasicClient operation and response status code class.",
+			},
+			MethodLabel, OperationLabel, CodeClassLabel,
+		),
+		touchstone.Counter(
+			prometheus.CounterOpts{
+				Name: DuplicateItemsCounter,
+				Help: "Counter for the number of fetched items dropped because a later item of the same response had the same ID.",
+			},
+		),
+		touchstone.HistogramVec(
+			prometheus.HistogramOpts{
+				Name:    OperationDurationHistogram,
+				Help:    "Histogram of the durations of BasicClient operations, retries included, by operation and the status code class of their final response.",
+				Buckets: prometheus.DefBuckets,
+			},
+			OperationLabel, CodeClassLabel,
+		),
+		touchstone.Gauge(
+			prometheus.GaugeOpts{
+				Name: LastSuccessfulPollGauge,
+				Help: "Unix time in seconds of the latest successful poll.",
+			},
+		),
+		touchstone.HistogramVec(
+			prometheus.HistogramOpts{
+				Name:    PollDurationHistogram,
+				Help:    "Histogram of the durations of polls, delivery to the listeners included, by outcome.",
+				Buckets: prometheus.DefBuckets,
+			},
+			OutcomeLabel,
+		),
+		touchstone.Gauge(
+			prometheus.GaugeOpts{
+				Name: ItemsFetchedGauge,
+				Help: "Number of items fetched by the latest successful poll.",
+			},
+		),
+		touchstone.GaugeVec(
+			prometheus.GaugeOpts{
+				Name: LastPollStatusGauge,
+				Help: "Kind of the result of the latest poll, set to 1 for its kind and 0 for the others.",
+			},
+			KindLabel,
+		),
 	)
 }
 
 type Measures struct {
 	fx.In
-	Polls *prometheus.CounterVec `name:"chrysom_polls_total"`
+	Polls               *prometheus.CounterVec `name:"chrysom_polls_total"`
+	OwnershipMismatches prometheus.Counter     `name:"chrysom_ownership_mismatches_total" optional:"true"`
+	RequestRetries      *prometheus.CounterVec `name:"chrysom_request_retries_total" optional:"true"`
+	Requests            *prometheus.CounterVec `name:"ancla_chrysom_requests_total" optional:"true"`
+	DuplicateItems      prometheus.Counter     `name:"ancla_chrysom_duplicate_items_total" optional:"true"`
+	OperationDuration   prometheus.ObserverVec `name:"ancla_chrysom_operation_duration_seconds" optional:"true"`
+	LastSuccessfulPoll  prometheus.Gauge       `name:"chrysom_last_successful_poll_timestamp" optional:"true"`
+	PollDuration        prometheus.ObserverVec `name:"chrysom_poll_duration_seconds" optional:"true"`
+	ItemsFetched        prometheus.Gauge       `name:"chrysom_items_fetched" optional:"true"`
+	LastPollStatus      *prometheus.GaugeVec   `name:"ancla_chrysom_last_poll_status" optional:"true"`
+}
+
+// codeClass returns the status code class label value of a response, e.g. "4xx".
+func codeClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%dxx", code/100)
 }
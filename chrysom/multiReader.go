@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/xmidt-org/ancla/model"
+	"go.uber.org/zap"
+)
+
+var ErrNoReadersProvided = errors.New("no readers provided")
+
+// PartialFailurePolicy decides whether a MultiReader fails when only some of its
+// readers fail.
+type PartialFailurePolicy int
+
+const (
+	// FailIfAllFail returns the items of the readers that succeeded and only fails
+	// when every reader fails.
+	FailIfAllFail PartialFailurePolicy = iota
+
+	// FailIfAnyFails fails as soon as a single reader fails.
+	FailIfAnyFails
+)
+
+// MultiReader is a Reader that merges the items of several readers into one view,
+// e.g. to read from both the old and the new store during a migration.
+type MultiReader struct {
+	// Readers are the sources of items. When several of them return an item with
+	// the same ID, the one expiring last wins; items without a TTL never expire.
+	// On ties the reader listed first wins.
+	Readers []Reader
+
+	// Policy decides whether partial failures fail GetItems.
+	// (Optional). Defaults to FailIfAllFail.
+	Policy PartialFailurePolicy
+
+	// Logger is used to report partial failures that are tolerated.
+	// (Optional). Defaults to a no op logger.
+	Logger *zap.Logger
+
+	mu sync.Mutex
+	// last holds the latest items of each reader and owner, reused when a
+	// reader reports with ErrNotModified that they haven't changed.
+	last map[lastItemsKey]Items
+}
+
+type lastItemsKey struct {
+	reader int
+	owner  string
+}
+
+// NewMultiReader creates a MultiReader for the given readers with the default policy.
+func NewMultiReader(readers ...Reader) *MultiReader {
+	return &MultiReader{
+		Readers: readers,
+	}
+}
+
+// GetItems fetches the owner's items from all readers concurrently and merges them.
+// Items are ordered by the reader they first appeared in and then by their order
+// within that reader's response. A reader failing with ErrNotModified, such as
+// a BasicClient with ConditionalRequests, contributes the items it returned last
+// for the owner.
+func (m *MultiReader) GetItems(ctx context.Context, owner string) (Items, error) {
+	if len(m.Readers) == 0 {
+		return nil, ErrNoReadersProvided
+	}
+
+	results := make([]Items, len(m.Readers))
+	errs := make([]error, len(m.Readers))
+	var wg sync.WaitGroup
+	for i, r := range m.Readers {
+		wg.Add(1)
+		go func(i int, r Reader) {
+			defer wg.Done()
+			results[i], errs[i] = r.GetItems(ctx, owner)
+		}(i, r)
+	}
+	wg.Wait()
+	m.reuseUnmodified(owner, results, errs)
+
+	var failures int
+	for _, err := range errs {
+		if err != nil {
+			failures++
+		}
+	}
+	err := errors.Join(errs...)
+	switch {
+	case failures == len(m.Readers):
+		return nil, err
+	case failures > 0 && m.Policy == FailIfAnyFails:
+		return nil, err
+	case failures > 0:
+		logger := m.Logger
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+		logger.Warn("Some readers failed to get items", zap.Int("failures", failures), zap.Error(err))
+	}
+
+	return mergeItems(results), nil
+}
+
+// reuseUnmodified replaces the ErrNotModified failures of the readers with the
+// items they returned last for the owner, and keeps the items of the readers
+// that succeeded for later polls.
+func (m *MultiReader) reuseUnmodified(owner string, results []Items, errs []error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.last == nil {
+		m.last = make(map[lastItemsKey]Items)
+	}
+	for i := range results {
+		key := lastItemsKey{reader: i, owner: owner}
+		switch last, ok := m.last[key]; {
+		case errs[i] == nil:
+			m.last[key] = results[i]
+		case errors.Is(errs[i], ErrNotModified) && ok:
+			results[i], errs[i] = last, nil
+		}
+	}
+}
+
+func mergeItems(results []Items) Items {
+	var merged Items
+	index := make(map[string]int)
+	for _, items := range results {
+		for _, item := range items {
+			i, ok := index[item.ID]
+			if !ok {
+				index[item.ID] = len(merged)
+				merged = append(merged, item)
+				continue
+			}
+			if expiresAfter(item, merged[i]) {
+				merged[i] = item
+			}
+		}
+	}
+	return merged
+}
+
+// expiresAfter reports whether a expires strictly after b.
+func expiresAfter(a, b model.Item) bool {
+	if b.TTL == nil {
+		return false
+	}
+	return a.TTL == nil || *a.TTL > *b.TTL
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/model"
+)
+
+func TestMultiReader(t *testing.T) {
+	var (
+		errOld = errors.New("old store failure")
+		errNew = errors.New("new store failure")
+		a      = model.Item{ID: "a", TTL: aws.Int64(10)}
+		aLater = model.Item{ID: "a", TTL: aws.Int64(20)}
+		aNoTTL = model.Item{ID: "a"}
+		b      = model.Item{ID: "b", TTL: aws.Int64(10)}
+		bSame  = model.Item{ID: "b", TTL: aws.Int64(10), Owner: "other"}
+		c      = model.Item{ID: "c"}
+	)
+	tcs := []struct {
+		desc          string
+		policy        PartialFailurePolicy
+		results       []Items
+		errs          []error
+		expectedItems Items
+		expectedErrs  []error
+	}{
+		{
+			desc:          "Dedup keeps the item expiring last",
+			results:       []Items{{a, b}, {aLater, c}},
+			errs:          []error{nil, nil},
+			expectedItems: Items{aLater, b, c},
+		},
+		{
+			desc:          "Dedup prefers items without TTL",
+			results:       []Items{{aNoTTL}, {aLater}},
+			errs:          []error{nil, nil},
+			expectedItems: Items{aNoTTL},
+		},
+		{
+			desc:          "Ties keep the first reader's item",
+			results:       []Items{{b}, {bSame}},
+			errs:          []error{nil, nil},
+			expectedItems: Items{b},
+		},
+		{
+			desc:          "Ordering follows reader order",
+			results:       []Items{{c, b}, {a}},
+			errs:          []error{nil, nil},
+			expectedItems: Items{c, b, a},
+		},
+		{
+			desc:          "Partial failure tolerated",
+			results:       []Items{nil, {a, c}},
+			errs:          []error{errOld, nil},
+			expectedItems: Items{a, c},
+		},
+		{
+			desc:         "Partial failure with FailIfAnyFails",
+			policy:       FailIfAnyFails,
+			results:      []Items{nil, {a, c}},
+			errs:         []error{errOld, nil},
+			expectedErrs: []error{errOld},
+		},
+		{
+			desc:         "All fail",
+			results:      []Items{nil, nil},
+			errs:         []error{errOld, errNew},
+			expectedErrs: []error{errOld, errNew},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			var readers []Reader
+			for i := range tc.results {
+				readers = append(readers, &sequenceReader{
+					results: []Items{tc.results[i]},
+					errs:    []error{tc.errs[i]},
+				})
+			}
+			m := NewMultiReader(readers...)
+			m.Policy = tc.policy
+
+			items, err := m.GetItems(context.Background(), "")
+			if len(tc.expectedErrs) > 0 {
+				assert.Nil(items)
+				for _, e := range tc.expectedErrs {
+					assert.ErrorIs(err, e)
+				}
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedItems, items)
+		})
+	}
+
+	t.Run("No readers", func(t *testing.T) {
+		_, err := NewMultiReader().GetItems(context.Background(), "")
+		assert.ErrorIs(t, err, ErrNoReadersProvided)
+	})
+}
+
+func TestMultiReaderNotModified(t *testing.T) {
+	var (
+		a = model.Item{ID: "a"}
+		b = model.Item{ID: "b"}
+		c = model.Item{ID: "c"}
+	)
+	tcs := []struct {
+		desc   string
+		policy PartialFailurePolicy
+	}{
+		{desc: "FailIfAllFail", policy: FailIfAllFail},
+		{desc: "FailIfAnyFails", policy: FailIfAnyFails},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+				rw.Header().Set("ETag", `"v1"`)
+				json.NewEncoder(rw).Encode(Items{a})
+			}))
+			defer server.Close()
+			conditional, err := NewBasicClient(BasicClientConfig{
+				Address:             server.URL,
+				Bucket:              "bucket-name",
+				ConditionalRequests: true,
+			}, nil)
+			require.NoError(err)
+
+			m := NewMultiReader(conditional, &sequenceReader{
+				results: []Items{{b}, {b, c}},
+				errs:    []error{nil, nil},
+			})
+			m.Policy = tc.policy
+			ctx := withConditional(context.Background(), true)
+
+			items, err := m.GetItems(ctx, "")
+			require.NoError(err)
+			assert.Equal(Items{a, b}.IDs(), items.IDs())
+
+			// the basic client's 304 reuses its items of the previous poll.
+			items, err = m.GetItems(ctx, "")
+			require.NoError(err)
+			assert.Equal(Items{a, b, c}.IDs(), items.IDs())
+		})
+	}
+
+	t.Run("Nothing to reuse", func(t *testing.T) {
+		m := NewMultiReader(&sequenceReader{
+			results: []Items{nil},
+			errs:    []error{ErrNotModified},
+		})
+		_, err := m.GetItems(context.Background(), "")
+		assert.ErrorIs(t, err, ErrNotModified)
+	})
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of poll results, reported by PollStatus.Kind and the KindLabel of the
+// last poll status gauge.
+const (
+	// OKPollKind is used for polls that fetched the items.
+	OKPollKind = "ok"
+	// AuthPollKind is used for polls rejected with a 401 or 403, or whose
+	// requests couldn't be decorated with auth headers.
+	AuthPollKind = "auth"
+	// UnavailablePollKind is used for polls that got a 5xx or 429 response.
+	UnavailablePollKind = "unavailable"
+	// RejectedPollKind is used for polls that got any other non-success response.
+	RejectedPollKind = "rejected"
+	// TransportPollKind is used for polls that got no response.
+	TransportPollKind = "transport"
+	// OtherPollKind is used for polls that failed for any other reason.
+	OtherPollKind = "other"
+)
+
+var pollKinds = []string{OKPollKind, AuthPollKind, UnavailablePollKind, RejectedPollKind, TransportPollKind, OtherPollKind}
+
+// PollStatus describes the latest poll of a ListenerClient.
+type PollStatus struct {
+	// PollSeq is the number of the poll, zero before the first one.
+	PollSeq uint64
+
+	// At is when the poll's fetch completed.
+	At time.Time
+
+	// Outcome is the poll's outcome label, e.g. SuccessOutcome.
+	Outcome string
+
+	// Kind classifies the poll's result, e.g. AuthPollKind.
+	Kind string
+
+	// StatusCode is the status code of Argus' non-success response, zero when
+	// the poll didn't fail with one.
+	StatusCode int
+
+	// Err is the error of a failed poll.
+	Err error
+}
+
+// classifyPollError returns the kind of the given poll error and the status code
+// of the Argus response it carries, if any.
+func classifyPollError(err error) (string, int) {
+	if err == nil {
+		return OKPollKind, 0
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch code := apiErr.Code; {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden:
+			return AuthPollKind, code
+		case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+			return UnavailablePollKind, code
+		default:
+			return RejectedPollKind, code
+		}
+	}
+
+	switch {
+	case errors.Is(err, ErrAuthDecoratorFailure), errors.Is(err, ErrFailedAuthentication):
+		return AuthPollKind, 0
+	case errors.Is(err, errDoRequestFailure):
+		return TransportPollKind, 0
+	default:
+		return OtherPollKind, 0
+	}
+}
+
+// Status returns the status of the latest poll.
+func (c *ListenerClient) Status() PollStatus {
+	c.observer.statusLock.RLock()
+	defer c.observer.statusLock.RUnlock()
+	return c.observer.status
+}
+
+// setStatus records the status of the latest poll, setting the one-hot last
+// poll status gauge when it is configured.
+func (c *ListenerClient) setStatus(status PollStatus) {
+	c.observer.statusLock.Lock()
+	c.observer.status = status
+	c.observer.statusLock.Unlock()
+
+	g := c.observer.measures.LastPollStatus
+	if g == nil {
+		return
+	}
+	for _, kind := range pollKinds {
+		value := 0.0
+		if kind == status.Kind {
+			value = 1
+		}
+		g.With(prometheus.Labels{KindLabel: kind}).Set(value)
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerClientStatus(t *testing.T) {
+	tcs := []struct {
+		Description  string
+		Code         int
+		Unreachable  bool
+		ExpectedKind string
+		ExpectedCode int
+	}{
+		{
+			Description:  "Success",
+			Code:         http.StatusOK,
+			ExpectedKind: OKPollKind,
+		},
+		{
+			Description:  "Unauthorized",
+			Code:         http.StatusUnauthorized,
+			ExpectedKind: AuthPollKind,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Description:  "Unavailable",
+			Code:         http.StatusServiceUnavailable,
+			ExpectedKind: UnavailablePollKind,
+			ExpectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			Description:  "Bad request",
+			Code:         http.StatusBadRequest,
+			ExpectedKind: RejectedPollKind,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Description:  "Transport failure",
+			Unreachable:  true,
+			ExpectedKind: TransportPollKind,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(tc.Code)
+				rw.Write([]byte("[]"))
+			}))
+			defer server.Close()
+			address := server.URL
+			if tc.Unreachable {
+				address = failingURL
+			}
+			basic, err := NewBasicClient(BasicClientConfig{Address: address, Bucket: "bucket-name"}, nil)
+			require.NoError(err)
+
+			gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "testLastPollStatus"}, []string{KindLabel})
+			measures := &Measures{Polls: mockMeasures.Polls, LastPollStatus: gauge}
+			client, err := NewListenerClient(happyListenerClientConfig, nil, measures, basic)
+			require.NoError(err)
+			assert.Zero(client.Status())
+
+			outcome := client.poll()
+			status := client.Status()
+			assert.Equal(uint64(1), status.PollSeq)
+			assert.Equal(outcome, status.Outcome)
+			assert.Equal(tc.ExpectedKind, status.Kind)
+			assert.Equal(tc.ExpectedCode, status.StatusCode)
+			assert.Equal(tc.ExpectedKind != OKPollKind, status.Err != nil)
+			for _, kind := range pollKinds {
+				var m dto.Metric
+				require.NoError(gauge.WithLabelValues(kind).Write(&m))
+				expected := 0.0
+				if kind == tc.ExpectedKind {
+					expected = 1
+				}
+				assert.Equal(expected, m.GetGauge().GetValue(), kind)
+			}
+		})
+	}
+}
+
+func TestClassifyPollError(t *testing.T) {
+	assert := assert.New(t)
+	kind, code := classifyPollError(ErrAuthDecoratorFailure)
+	assert.Equal(AuthPollKind, kind)
+	assert.Zero(code)
+	kind, _ = classifyPollError(errors.New("unexpected"))
+	assert.Equal(OtherPollKind, kind)
+	kind, code = classifyPollError(&APIError{Code: http.StatusTooManyRequests, Err: errNonSuccessResponse})
+	assert.Equal(UnavailablePollKind, kind)
+	assert.Equal(http.StatusTooManyRequests, code)
+}
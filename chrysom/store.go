@@ -5,6 +5,7 @@ package chrysom
 
 import (
 	"context"
+	"time"
 
 	"github.com/xmidt-org/ancla/model"
 )
@@ -30,6 +31,34 @@ type Listener interface {
 	Update(items Items)
 }
 
+// UpdateMeta describes the poll that produced an update.
+type UpdateMeta struct {
+	// FetchedAt is when the poll's fetch completed.
+	FetchedAt time.Time
+
+	// Outcome is the poll's outcome label, e.g. SuccessOutcome.
+	Outcome string
+
+	// PollSeq is the poll's sequence number, as found in its logs.
+	PollSeq uint64
+
+	// Unchanged is true for successful polls whose items are the same as the
+	// ones last delivered, which only MetaListeners are told about unless
+	// ListenerClientConfig.AlwaysNotify is set.
+	Unchanged bool
+}
+
+// MetaListener is a Listener that is also told about the poll behind each update.
+// The ListenerClient calls UpdateWithMeta instead of Update for these listeners.
+type MetaListener interface {
+	Listener
+
+	// UpdateWithMeta is Update along with the poll's metadata. When failed polls
+	// are delivered, they come with the FailureOutcome and no items. Polls with
+	// unchanged items are delivered too, with UpdateMeta.Unchanged set.
+	UpdateWithMeta(meta UpdateMeta, items Items)
+}
+
 type ListenerFunc func(items Items)
 
 func (l ListenerFunc) Update(items Items) {
@@ -41,6 +70,50 @@ type Reader interface {
 	GetItems(ctx context.Context, owner string) (Items, error)
 }
 
+// ItemGetter is implemented by stores that can fetch a single item.
+type ItemGetter interface {
+	// GetItem returns the item with the given ID, failing with ErrItemNotFound
+	// if there is none.
+	GetItem(ctx context.Context, owner, id string) (model.Item, error)
+}
+
+// BulkPusher is implemented by stores that can push many items at once.
+type BulkPusher interface {
+	// PushItems pushes the items for the owner, returning the result of each
+	// of them and a *BatchError of the ones that failed.
+	PushItems(ctx context.Context, owner string, items []model.Item) ([]PushResult, error)
+}
+
+// LimitedReader is implemented by stores that can fetch a limited number of
+// items, which is cheaper when only a few are needed.
+type LimitedReader interface {
+	// GetItemsLimited returns at most limit of the items that belong to this
+	// owner, or all of them when limit is below 1.
+	GetItemsLimited(ctx context.Context, owner string, limit int) (Items, error)
+}
+
+// CountItems returns the number of items that belong to the owner, counting no
+// further than limit when it's above 0. Readers that are LimitedReaders are
+// only asked for as many items as are counted.
+func CountItems(ctx context.Context, r Reader, owner string, limit int) (int, error) {
+	var (
+		items Items
+		err   error
+	)
+	if lr, ok := r.(LimitedReader); ok {
+		items, err = lr.GetItemsLimited(ctx, owner, limit)
+	} else {
+		items, err = r.GetItems(ctx, owner)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if limit > 0 {
+		return min(len(items), limit), nil
+	}
+	return len(items), nil
+}
+
 type ConfigureListener interface {
 	// SetListener will attempt to set the lister.
 	SetListener(listener Listener) error
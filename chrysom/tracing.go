@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TraceIDExemplarLabel is the label of the trace ID exemplars attached to the
+// OperationDuration observations of traced requests.
+const TraceIDExemplarLabel = "trace_id"
+
+// RequestInfo describes a request sent by a BasicClient.
+type RequestInfo struct {
+	// Operation is the BasicClient method sending the request, e.g. GetItemsOperation.
+	Operation string
+
+	// Bucket is the Argus bucket of the request.
+	Bucket string
+
+	// ItemID is the ID of the item requested, empty for GetItems.
+	ItemID string
+}
+
+// RequestTracer traces the requests of a BasicClient without tying this
+// package to a tracing library. An OpenTelemetry implementation starts a client
+// span from the request's context with the info and method as attributes,
+// injects the span's context into the request's headers with its propagator,
+// and ends the span with the response code.
+//
+// When the tracer sets a W3C traceparent header, its trace ID is attached as an
+// exemplar to the request's Measures.OperationDuration observation, if the
+// observer supports exemplars.
+type RequestTracer interface {
+	// StartRequest is called with every request attempt before it is sent, and
+	// may add headers to it. The returned func, if any, is called once the
+	// attempt is done with the response code, 0 when there was no response,
+	// and the attempt's error.
+	StartRequest(r *http.Request, info RequestInfo) (end func(code int, err error))
+}
+
+type requestInfoKey struct{}
+
+// withRequestInfo returns a context carrying the info for traceRequest.
+func withRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// traceRequest starts tracing the request attempt with the client's tracer,
+// returning the func ending it, which does nothing without a tracer.
+func (c *BasicClient) traceRequest(r *http.Request) func(code int, err error) {
+	if c.tracer == nil {
+		return func(int, error) {}
+	}
+	info, _ := r.Context().Value(requestInfoKey{}).(RequestInfo)
+	info.Bucket = c.bucket
+	end := c.tracer.StartRequest(r, info)
+	if end == nil {
+		return func(int, error) {}
+	}
+	return end
+}
+
+// traceIDOf returns the trace ID of the W3C traceparent header, if any.
+func traceIDOf(h http.Header) string {
+	parts := strings.Split(h.Get("traceparent"), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	return parts[1]
+}
+
+// observe observes the value, with the trace ID as an exemplar when there is one
+// and the observer supports exemplars.
+func observe(o prometheus.Observer, v float64, traceID string) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && traceID != "" {
+		eo.ObserveWithExemplar(v, prometheus.Labels{TraceIDExemplarLabel: traceID})
+		return
+	}
+	o.Observe(v)
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package chrysom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type span struct {
+	info RequestInfo
+	code int
+	err  error
+}
+
+type recordingTracer struct {
+	spans []*span
+}
+
+func (tr *recordingTracer) StartRequest(r *http.Request, info RequestInfo) func(int, error) {
+	r.Header.Set("traceparent", testTraceparent)
+	s := &span{info: info}
+	tr.spans = append(tr.spans, s)
+	return func(code int, err error) {
+		s.code, s.err = code, err
+	}
+}
+
+func TestRequestTracer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("traceparent"))
+		if r.Method == http.MethodDelete {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client, err := NewBasicClient(BasicClientConfig{
+		Address: server.URL,
+		Bucket:  "bucket-name",
+		Tracer:  tracer,
+	}, nil)
+	require.NoError(err)
+
+	_, err = client.GetItems(context.TODO(), "owner")
+	require.NoError(err)
+	_, err = client.RemoveItem(context.TODO(), "id", "owner")
+	require.Error(err)
+
+	assert.Equal([]string{testTraceparent, testTraceparent}, headers)
+	require.Len(tracer.spans, 2)
+	assert.Equal(RequestInfo{Operation: GetItemsOperation, Bucket: "bucket-name"}, tracer.spans[0].info)
+	assert.Equal(http.StatusOK, tracer.spans[0].code)
+	assert.NoError(tracer.spans[0].err)
+	assert.Equal(RequestInfo{Operation: RemoveItemOperation, Bucket: "bucket-name", ItemID: "id"}, tracer.spans[1].info)
+	assert.Equal(http.StatusNotFound, tracer.spans[1].code)
+
+	client, err = NewBasicClient(BasicClientConfig{
+		Address: failingURL,
+		Bucket:  "bucket-name",
+		Tracer:  tracer,
+	}, nil)
+	require.NoError(err)
+	_, err = client.GetItem(context.TODO(), "owner", "id")
+	require.Error(err)
+	require.Len(tracer.spans, 3)
+	assert.Equal(RequestInfo{Operation: GetItemOperation, Bucket: "bucket-name", ItemID: "id"}, tracer.spans[2].info)
+	assert.Zero(tracer.spans[2].code)
+	assert.ErrorIs(tracer.spans[2].err, errDoRequestFailure)
+}
+
+func TestRequestTracerExemplars(t *testing.T) {
+	tcs := []struct {
+		desc            string
+		tracer          RequestTracer
+		expectedTraceID string
+	}{
+		{
+			desc:            "Traced",
+			tracer:          &recordingTracer{},
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			desc: "Not traced",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte("[]"))
+			}))
+			defer server.Close()
+
+			durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "durations"}, []string{OperationLabel, CodeClassLabel})
+			client, err := NewBasicClient(BasicClientConfig{
+				Address:  server.URL,
+				Bucket:   "bucket-name",
+				Tracer:   tc.tracer,
+				Measures: &Measures{OperationDuration: durations},
+			}, nil)
+			require.NoError(err)
+			_, err = client.GetItems(context.TODO(), "owner")
+			require.NoError(err)
+
+			var m dto.Metric
+			h := durations.With(prometheus.Labels{OperationLabel: GetItemsOperation, CodeClassLabel: "2xx"})
+			require.NoError(h.(prometheus.Metric).Write(&m))
+			assert.Equal(uint64(1), m.GetHistogram().GetSampleCount())
+			var traceIDs []string
+			for _, b := range m.GetHistogram().GetBucket() {
+				for _, l := range b.GetExemplar().GetLabel() {
+					if l.GetName() == TraceIDExemplarLabel {
+						traceIDs = append(traceIDs, l.GetValue())
+					}
+				}
+			}
+			if tc.expectedTraceID == "" {
+				assert.Empty(traceIDs)
+				return
+			}
+			assert.Equal([]string{tc.expectedTraceID}, traceIDs)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client provides a client for the webhook registration API served by
+// another service's ancla handlers.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xmidt-org/ancla"
+	"github.com/xmidt-org/ancla/auth"
+)
+
+// Errors that can be returned by this package. Errors for non-success responses
+// are *Error values wrapping one of the status errors.
+var (
+	ErrAddressEmpty = errors.New("registration API address is required")
+	ErrIDEmpty      = errors.New("webhook ID is required")
+
+	ErrBadRequest       = errors.New("registration was rejected as invalid")
+	ErrForbidden        = errors.New("registration access was forbidden")
+	ErrNotFound         = errors.New("webhook not found")
+	ErrConflict         = errors.New("webhook already exists")
+	ErrNonSuccessStatus = errors.New("registration API responded with a non-success status code")
+)
+
+const (
+	contentTypeHeader = "Content-Type"
+	jsonContentType   = "application/json"
+
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+)
+
+// Config contains the information needed to talk to a registration API.
+type Config struct {
+	// Address is the URL the ancla handlers are mounted under (i.e. https://example-caduceus.io:6000/api/v4).
+	Address string
+
+	// AddPath is the path of the add webhook handler, appended to Address.
+	// (Optional). Defaults to ancla.AddWebhookPath.
+	AddPath string
+
+	// GetAllPath is the path of the get all webhooks handler, appended to Address.
+	// Single webhooks are fetched from GetAllPath/{id}, like ancla.GetWebhookPath.
+	// (Optional). Defaults to ancla.GetAllWebhooksPath.
+	GetAllPath string
+
+	// HTTPClient refers to the client that will be used to send requests.
+	// (Optional). Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Auth provides the mechanism to add auth headers to outgoing requests.
+	// (Optional). If not provided, no auth headers are added.
+	Auth auth.Decorator
+
+	// RetryMaxAttempts is the maximum number of attempts of a request that fails
+	// with a network error or a 5xx response. 4xx responses are never retried.
+	// (Optional). Defaults to 3.
+	RetryMaxAttempts int
+
+	// RetryInitialBackoff is the wait before the first retry. It doubles with
+	// each retry and is jittered by up to half its value.
+	// (Optional). Defaults to 100ms.
+	RetryInitialBackoff time.Duration
+}
+
+// RegisterOptions changes how a webhook is registered.
+type RegisterOptions struct {
+	// CreateOnly, if true, fails with ErrConflict instead of replacing an
+	// existing registration for the same URL.
+	CreateOnly bool
+}
+
+// Error describes a non-success response of the registration API, decoded from
+// the JSON error body written by the ancla handlers.
+type Error struct {
+	// Code is the response's status code.
+	Code int
+
+	// Message is the "message" of the error body.
+	Message string
+
+	// Details lists every validation failure of a rejected registration.
+	Details []string
+
+	// Err is the status error matching Code.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%v: received status %v", e.Err, e.Code)
+	}
+	return fmt.Sprintf("%v: received status %v: %s", e.Err, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Client registers and fetches webhooks through a registration API.
+type Client struct {
+	addURL              string
+	getAllURL           string
+	client              *http.Client
+	auth                auth.Decorator
+	retryMaxAttempts    int
+	retryInitialBackoff time.Duration
+}
+
+// New creates a Client for the registration API at the configured address.
+func New(config Config) (*Client, error) {
+	if config.Address == "" {
+		return nil, ErrAddressEmpty
+	}
+	if config.AddPath == "" {
+		config.AddPath = ancla.AddWebhookPath
+	}
+	if config.GetAllPath == "" {
+		config.GetAllPath = ancla.GetAllWebhooksPath
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.RetryMaxAttempts < 1 {
+		config.RetryMaxAttempts = defaultRetryMaxAttempts
+	}
+	if config.RetryInitialBackoff <= 0 {
+		config.RetryInitialBackoff = defaultRetryInitialBackoff
+	}
+
+	address := strings.TrimSuffix(config.Address, "/")
+	return &Client{
+		addURL:              address + config.AddPath,
+		getAllURL:           address + config.GetAllPath,
+		client:              config.HTTPClient,
+		auth:                config.Auth,
+		retryMaxAttempts:    config.RetryMaxAttempts,
+		retryInitialBackoff: config.RetryInitialBackoff,
+	}, nil
+}
+
+// Register adds or renews the given webhook registration. The remote service
+// derives the owner and partner IDs from the request's credentials.
+func (c *Client) Register(ctx context.Context, wr ancla.WebhookRegistration, opts RegisterOptions) error {
+	body, err := json.Marshal(wr)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{contentTypeHeader: []string{jsonContentType}}
+	if opts.CreateOnly {
+		header.Set("If-None-Match", "*")
+	}
+	_, err = c.do(ctx, http.MethodPost, c.addURL, header, body)
+	return err
+}
+
+// List returns the registered webhooks, with obfuscated secrets.
+func (c *Client) List(ctx context.Context) ([]ancla.Webhook, error) {
+	body, err := c.do(ctx, http.MethodGet, c.getAllURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []ancla.Webhook
+	if err := json.Unmarshal(body, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Get returns the webhook with the given ID, failing with ErrNotFound if there
+// is none.
+func (c *Client) Get(ctx context.Context, id string) (ancla.Webhook, error) {
+	if id == "" {
+		return ancla.Webhook{}, ErrIDEmpty
+	}
+	body, err := c.do(ctx, http.MethodGet, c.getAllURL+"/"+url.PathEscape(id), nil, nil)
+	if err != nil {
+		return ancla.Webhook{}, err
+	}
+
+	var webhook ancla.Webhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return ancla.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// do sends the request, retrying network errors and 5xx responses, and returns
+// the body of a successful response.
+func (c *Client) do(ctx context.Context, method, u string, header http.Header, body []byte) ([]byte, error) {
+	backoff := c.retryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		respBody, retryable, err := c.attempt(ctx, method, u, header, body)
+		if err == nil || !retryable || attempt >= c.retryMaxAttempts {
+			return respBody, err
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, u string, header http.Header, body []byte) ([]byte, bool, error) {
+	r, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	if c.auth != nil {
+		if err := c.auth.Decorate(ctx, r); err != nil {
+			return nil, false, err
+		}
+	}
+
+	resp, err := c.client.Do(r)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return respBody, false, nil
+	}
+	return nil, resp.StatusCode >= 500, newError(resp.StatusCode, respBody)
+}
+
+func newError(code int, body []byte) *Error {
+	e := Error{Code: code}
+	var eb struct {
+		Message string   `json:"message"`
+		Details []string `json:"details"`
+	}
+	if json.Unmarshal(body, &eb) == nil {
+		e.Message, e.Details = eb.Message, eb.Details
+	}
+
+	switch code {
+	case http.StatusBadRequest:
+		e.Err = ErrBadRequest
+	case http.StatusForbidden:
+		e.Err = ErrForbidden
+	case http.StatusNotFound:
+		e.Err = ErrNotFound
+	case http.StatusConflict:
+		e.Err = ErrConflict
+	default:
+		e.Err = ErrNonSuccessStatus
+	}
+	return &e
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla"
+	"github.com/xmidt-org/ancla/auth"
+)
+
+// memoryService is an in-memory ancla.Service and ancla.Creator.
+type memoryService struct {
+	mu  sync.Mutex
+	iws map[string]ancla.InternalWebhook
+}
+
+func (s *memoryService) Add(_ context.Context, _ string, iw ancla.InternalWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.iws == nil {
+		s.iws = make(map[string]ancla.InternalWebhook)
+	}
+	s.iws[ancla.SHA256IDHasher(iw.Webhook.Config.URL)] = iw
+	return nil
+}
+
+func (s *memoryService) Create(ctx context.Context, owner string, iw ancla.InternalWebhook) error {
+	if _, err := s.Get(ctx, owner, ancla.SHA256IDHasher(iw.Webhook.Config.URL)); err == nil {
+		return ancla.ErrWebhookExists
+	}
+	return s.Add(ctx, owner, iw)
+}
+
+func (s *memoryService) GetAll(context.Context) ([]ancla.InternalWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var iws []ancla.InternalWebhook
+	for _, iw := range s.iws {
+		iws = append(iws, iw)
+	}
+	return iws, nil
+}
+
+func (s *memoryService) GetAllByOwner(ctx context.Context, _ string) ([]ancla.InternalWebhook, error) {
+	return s.GetAll(ctx)
+}
+
+func (s *memoryService) Get(_ context.Context, _, id string) (ancla.InternalWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	iw, ok := s.iws[id]
+	if !ok {
+		return ancla.InternalWebhook{}, ancla.ErrWebhookNotFound
+	}
+	return iw, nil
+}
+
+func newTestClient(t *testing.T, config ancla.HandlerConfig) *Client {
+	config.DisablePartnerIDs = true
+	server := httptest.NewServer(ancla.NewRouter(new(memoryService), config))
+	t.Cleanup(server.Close)
+
+	c, err := New(Config{Address: server.URL})
+	require.NoError(t, err)
+	return c
+}
+
+func testRegistration(url string) ancla.WebhookRegistration {
+	var wr ancla.WebhookRegistration
+	wr.Config.URL = url
+	wr.Config.Secret = "superSecretXYZ"
+	wr.Events = []string{"online"}
+	wr.Duration = ancla.CustomDuration(5 * time.Minute)
+	return wr
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	_, err := New(Config{})
+	assert.ErrorIs(err, ErrAddressEmpty)
+
+	c, err := New(Config{Address: "https://example.com/api/"})
+	assert.NoError(err)
+	assert.Equal("https://example.com/api"+ancla.AddWebhookPath, c.addURL)
+	assert.Equal("https://example.com/api"+ancla.GetAllWebhooksPath, c.getAllURL)
+	assert.Equal(defaultRetryMaxAttempts, c.retryMaxAttempts)
+}
+
+func TestRegisterListGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	c := newTestClient(t, ancla.HandlerConfig{})
+
+	require.NoError(c.Register(ctx, testRegistration("https://receiver.example.com/events"), RegisterOptions{}))
+	require.NoError(c.Register(ctx, testRegistration("https://receiver.example.com/events"), RegisterOptions{}))
+
+	webhooks, err := c.List(ctx)
+	require.NoError(err)
+	require.Len(webhooks, 1)
+	assert.Equal("https://receiver.example.com/events", webhooks[0].Config.URL)
+	assert.NotEqual("superSecretXYZ", webhooks[0].Config.Secret)
+
+	webhook, err := c.Get(ctx, ancla.SHA256IDHasher("https://receiver.example.com/events"))
+	require.NoError(err)
+	assert.Equal(webhooks[0], webhook)
+
+	_, err = c.Get(ctx, "unknown")
+	assert.ErrorIs(err, ErrNotFound)
+	_, err = c.Get(ctx, "")
+	assert.ErrorIs(err, ErrIDEmpty)
+}
+
+func TestRegisterErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Conflict", func(t *testing.T) {
+		assert := assert.New(t)
+		c := newTestClient(t, ancla.HandlerConfig{})
+		wr := testRegistration("https://receiver.example.com/events")
+		assert.NoError(c.Register(ctx, wr, RegisterOptions{CreateOnly: true}))
+
+		err := c.Register(ctx, wr, RegisterOptions{CreateOnly: true})
+		assert.ErrorIs(err, ErrConflict)
+		var e *Error
+		if assert.ErrorAs(err, &e) {
+			assert.Equal(http.StatusConflict, e.Code)
+		}
+	})
+
+	t.Run("Validation details", func(t *testing.T) {
+		assert := assert.New(t)
+		c := newTestClient(t, ancla.HandlerConfig{
+			V: ancla.AllValidators{ancla.CheckEvents(), ancla.CheckMaxEvents(0)},
+		})
+		wr := testRegistration("https://receiver.example.com/events")
+		wr.Events = nil
+
+		err := c.Register(ctx, wr, RegisterOptions{})
+		assert.ErrorIs(err, ErrBadRequest)
+		var e *Error
+		if assert.ErrorAs(err, &e) {
+			assert.Equal(http.StatusBadRequest, e.Code)
+			assert.NotEmpty(e.Message)
+			assert.Len(e.Details, 1)
+		}
+	})
+}
+
+func TestRetries(t *testing.T) {
+	tcs := []struct {
+		desc          string
+		codes         []int
+		expectedErr   error
+		expectedCalls int32
+	}{
+		{
+			desc:          "Recovered 5xx",
+			codes:         []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			expectedCalls: 3,
+		},
+		{
+			desc:          "Exhausted 5xx",
+			codes:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedErr:   ErrNonSuccessStatus,
+			expectedCalls: 3,
+		},
+		{
+			desc:          "4xx isn't retried",
+			codes:         []int{http.StatusForbidden, http.StatusOK},
+			expectedErr:   ErrForbidden,
+			expectedCalls: 1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(auth.MockAuthHeaderValue, r.Header.Get(auth.MockAuthHeaderName))
+				assert.Equal(jsonContentType, r.Header.Get(contentTypeHeader))
+				rw.WriteHeader(tc.codes[calls.Add(1)-1])
+			}))
+			defer server.Close()
+
+			decorator := new(auth.MockDecorator)
+			// nolint:typecheck
+			decorator.On("Decorate").Return(nil)
+			c, err := New(Config{
+				Address:             server.URL,
+				Auth:                decorator,
+				RetryInitialBackoff: time.Millisecond,
+			})
+			require.NoError(err)
+
+			err = c.Register(context.Background(), testRegistration("https://receiver.example.com/events"), RegisterOptions{})
+			assert.ErrorIs(err, tc.expectedErr)
+			assert.Equal(tc.expectedCalls, calls.Load())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import "net/http"
+
+// committer is implemented by ResponseWriters that know whether the response's
+// status has been written.
+type committer interface {
+	Committed() bool
+}
+
+// committedWriter records whether a response has been committed, so that the
+// errorEncoder doesn't write a second status and body.
+type committedWriter struct {
+	http.ResponseWriter
+	committed bool
+}
+
+func (w *committedWriter) WriteHeader(code int) {
+	w.committed = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *committedWriter) Write(b []byte) (int, error) {
+	w.committed = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Committed reports whether the response's status has been written.
+func (w *committedWriter) Committed() bool {
+	if c, ok := w.ResponseWriter.(committer); ok && c.Committed() {
+		return true
+	}
+	return w.committed
+}
+
+func (w *committedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// trackCommitted wraps the ResponseWriters passed to next in committedWriters.
+func trackCommitted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if _, ok := rw.(committer); !ok {
+			rw = &committedWriter{ResponseWriter: rw}
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// isCommitted reports whether the response's status has already been written.
+func isCommitted(w http.ResponseWriter) bool {
+	c, ok := w.(committer)
+	return ok && c.Committed()
+}
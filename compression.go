@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	acceptEncodingHeader  = "Accept-Encoding"
+	contentEncodingHeader = "Content-Encoding"
+	varyHeader            = "Vary"
+	gzipEncoding          = "gzip"
+)
+
+var (
+	errCorruptGzipBody     = errors.New("request body is not valid gzip")
+	errRequestBodyTooLarge = errors.New("request body too large")
+)
+
+type acceptGzipKey struct{}
+
+// captureAcceptEncoding is a kithttp.RequestFunc that records whether the
+// request accepts gzip encoded responses.
+func captureAcceptEncoding(ctx context.Context, r *http.Request) context.Context {
+	if acceptsGzip(r.Header.Get(acceptEncodingHeader)) {
+		return context.WithValue(ctx, acceptGzipKey{}, true)
+	}
+	return ctx
+}
+
+// acceptsGzip reports whether the given Accept-Encoding header value lists gzip
+// without a zero quality.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		if !strings.EqualFold(strings.TrimSpace(name), gzipEncoding) {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				q, err := strconv.ParseFloat(v, 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWanted reports whether the response should be gzip encoded.
+func gzipWanted(ctx context.Context) bool {
+	wanted, _ := ctx.Value(acceptGzipKey{}).(bool)
+	return wanted
+}
+
+// gzipBytes returns the gzip encoding of the given bytes.
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readBody reads the request's body, decompressing it when its Content-Encoding
+// is gzip and decompress is true. Corrupt gzip bodies fail with errCorruptGzipBody.
+// Unless limit is 0, bodies of more than limit bytes, before or after
+// decompression, fail with errRequestBodyTooLarge.
+func readBody(r *http.Request, decompress bool, limit int64) ([]byte, error) {
+	body := r.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(nil, body, limit)
+	}
+	if !decompress || !strings.EqualFold(r.Header.Get(contentEncodingHeader), gzipEncoding) {
+		return readLimited(body, limit)
+	}
+
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		if tooLarge(err) {
+			return nil, fmt.Errorf("%w: more than %d bytes", errRequestBodyTooLarge, limit)
+		}
+		return nil, fmt.Errorf("%w: %w", errCorruptGzipBody, err)
+	}
+	defer zr.Close()
+	b, err := readLimited(zr, limit)
+	if err != nil && !errors.Is(err, errRequestBodyTooLarge) {
+		return nil, fmt.Errorf("%w: %w", errCorruptGzipBody, err)
+	}
+	return b, err
+}
+
+// readLimited reads all of r, failing with errRequestBodyTooLarge when it has
+// more than limit bytes, unless limit is 0.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	switch {
+	case err != nil && !tooLarge(err):
+		return nil, err
+	case err != nil || int64(len(b)) > limit:
+		return nil, fmt.Errorf("%w: more than %d bytes", errRequestBodyTooLarge, limit)
+	}
+	return b, nil
+}
+
+// tooLarge reports whether the error is from an http.MaxBytesReader whose
+// limit was exceeded.
+func tooLarge(err error) bool {
+	var e *http.MaxBytesError
+	return errors.As(err, &e)
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func mustGzip(t testing.TB, s string) []byte {
+	b, err := gzipBytes([]byte(s))
+	require.NoError(t, err)
+	return b
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tcs := []struct {
+		acceptEncoding string
+		expected       bool
+	}{
+		{acceptEncoding: "", expected: false},
+		{acceptEncoding: "gzip", expected: true},
+		{acceptEncoding: "deflate, GZIP;q=0.5", expected: true},
+		{acceptEncoding: "br, gzip; q=0", expected: false},
+		{acceptEncoding: "gzip;q=0.000", expected: false},
+		{acceptEncoding: "identity", expected: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tc.expected, acceptsGzip(tc.acceptEncoding))
+		})
+	}
+}
+
+func TestAddWebhookRequestDecoderGzip(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		body         []byte
+		encoding     string
+		disable      bool
+		expectedCode int
+	}{
+		{
+			desc:     "Gzip body",
+			body:     mustGzip(t, addWebhookDecoderDurationInput()),
+			encoding: "gzip",
+		},
+		{
+			desc: "Plain body",
+			body: []byte(addWebhookDecoderDurationInput()),
+		},
+		{
+			desc:         "Corrupt gzip header",
+			body:         []byte(addWebhookDecoderDurationInput()),
+			encoding:     "gzip",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Truncated gzip stream",
+			body:         mustGzip(t, addWebhookDecoderDurationInput())[:20],
+			encoding:     "gzip",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Compression disabled",
+			body:         mustGzip(t, addWebhookDecoderDurationInput()),
+			encoding:     "gzip",
+			disable:      true,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			decode := addWebhookRequestDecoder(transportConfig{
+				now:                getRefTime,
+				disablePartnerIDs:  true,
+				disableCompression: tc.disable,
+			})
+			r := httptest.NewRequest(http.MethodPost, AddWebhookPath, bytes.NewReader(tc.body))
+			if tc.encoding != "" {
+				r.Header.Set(contentEncodingHeader, tc.encoding)
+			}
+
+			req, err := decode(context.Background(), r)
+			if tc.expectedCode == 0 {
+				assert.NoError(err)
+				assert.Equal("example.com:443", req.(*addWebhookRequest).internalWebook.Webhook.Config.URL)
+				return
+			}
+			var sc kithttp.StatusCoder
+			if assert.ErrorAs(err, &sc) {
+				assert.Equal(tc.expectedCode, sc.StatusCode())
+			}
+		})
+	}
+}
+
+func TestGetAllWebhooksHandlerGzip(t *testing.T) {
+	tcs := []struct {
+		desc           string
+		acceptEncoding string
+		disable        bool
+		expectGzip     bool
+	}{
+		{
+			desc:           "Gzip accepted",
+			acceptEncoding: "gzip, deflate",
+			expectGzip:     true,
+		},
+		{
+			desc: "Gzip not accepted",
+		},
+		{
+			desc:           "Compression disabled",
+			acceptEncoding: "gzip",
+			disable:        true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			m := new(mockService)
+			// nolint:typecheck
+			m.On("GetAll", mock.Anything).Return(encodeGetAllInput(), nil)
+			handler := NewGetAllWebhooksHandler(m, HandlerConfig{DisableCompression: tc.disable})
+
+			r := httptest.NewRequest(http.MethodGet, GetAllWebhooksPath, nil)
+			r.Header.Set(acceptEncodingHeader, tc.acceptEncoding)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+			require.Equal(http.StatusOK, recorder.Code)
+
+			body := recorder.Body.Bytes()
+			if tc.expectGzip {
+				assert.Equal(gzipEncoding, recorder.Header().Get(contentEncodingHeader))
+				assert.Equal(acceptEncodingHeader, recorder.Header().Get(varyHeader))
+				zr, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(err)
+				body, err = io.ReadAll(zr)
+				require.NoError(err)
+			} else {
+				assert.Empty(recorder.Header().Get(contentEncodingHeader))
+			}
+			assert.JSONEq(encodeGetAllOutput(), string(body))
+
+			// the representation's own ETag is honored.
+			r.Header.Set(ifNoneMatchHeader, recorder.Header().Get(etagHeader))
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+			assert.Equal(http.StatusNotModified, recorder.Code)
+		})
+	}
+}
+
+func BenchmarkGzip(b *testing.B) {
+	var body bytes.Buffer
+	for range 500 {
+		body.WriteString(addWebhookDecoderDurationInput())
+	}
+	compressed := mustGzip(b, body.String())
+
+	b.Run("Compress", func(b *testing.B) {
+		b.SetBytes(int64(body.Len()))
+		for range b.N {
+			if _, err := gzipBytes(body.Bytes()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Decompress", func(b *testing.B) {
+		b.SetBytes(int64(body.Len()))
+		for range b.N {
+			r := httptest.NewRequest(http.MethodPost, AddWebhookPath, bytes.NewReader(compressed))
+			r.Header.Set(contentEncodingHeader, gzipEncoding)
+			if _, err := readBody(r, true, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -5,19 +5,240 @@ package ancla
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/httpaux/erraux"
 )
 
-func newAddWebhookEndpoint(s Service) endpoint.Endpoint {
+// newAddWebhookEndpoint returns the add endpoint, responding with the added
+// webhook. A positive storeTimeout caps the time given to the service to store
+// the webhook. Create only requests require the service to be a Creator, and
+// other requests are only known to have created the webhook when the service is
+// a ResultAdder.
+func newAddWebhookEndpoint(s Service, storeTimeout time.Duration) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		r := request.(*addWebhookRequest)
-		return nil, s.Add(ctx, r.owner, r.internalWebook)
+		add := func(ctx context.Context, owner string, iw InternalWebhook) (chrysom.PushResult, error) {
+			return chrysom.UnknownPushResult, s.Add(ctx, owner, iw)
+		}
+		if ra, ok := s.(ResultAdder); ok {
+			add = ra.AddWithResult
+		}
+		if r.createOnly {
+			c, ok := s.(Creator)
+			if !ok {
+				return nil, &erraux.Error{Err: errCreateOnlyNotSupported, Code: http.StatusNotImplemented}
+			}
+			add = func(ctx context.Context, owner string, iw InternalWebhook) (chrysom.PushResult, error) {
+				return chrysom.CreatedPushResult, c.Create(ctx, owner, iw)
+			}
+		}
+
+		storeCtx := ctx
+		if storeTimeout > 0 {
+			var cancel context.CancelFunc
+			storeCtx, cancel = context.WithTimeoutCause(ctx, storeTimeout, errStoreBudgetExceeded)
+			defer cancel()
+		}
+		result, err := add(storeCtx, r.owner, r.internalWebook)
+		switch {
+		case err == nil:
+			return &addWebhookResponse{
+				webhook: r.internalWebook.Webhook,
+				created: result == chrysom.CreatedPushResult,
+			}, nil
+		case errors.Is(err, ErrWebhookExists):
+			return nil, &erraux.Error{Err: err, Code: http.StatusConflict}
+		case errors.Is(err, ErrItemTooLarge):
+			return nil, &erraux.Error{Err: err, Code: http.StatusRequestEntityTooLarge}
+		case errors.Is(context.Cause(storeCtx), errStoreBudgetExceeded):
+			return nil, &erraux.Error{Err: fmt.Errorf(errFmt, errStoreBudgetExceeded, err), Code: http.StatusGatewayTimeout}
+		}
+		return nil, err
+	}
+}
+
+// newGetWebhookEndpoint returns the get endpoint, responding with a 404 when
+// there is no such webhook and a 403 when it belongs to another owner.
+func newGetWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r := request.(*getWebhookRequest)
+		iw, err := s.Get(ctx, r.owner, r.id)
+		if err != nil {
+			return nil, getWebhookError(err)
+		}
+		return iw, nil
+	}
+}
+
+// getWebhookError gives the errors of Service.Get their status codes. Argus
+// refusing the service's own credentials is a failure of the service rather
+// than of the request, so it's a 502.
+func getWebhookError(err error) error {
+	switch {
+	case errors.Is(err, ErrWebhookNotFound):
+		return &erraux.Error{Err: err, Code: http.StatusNotFound}
+	case errors.Is(err, ErrWebhookNotOwned):
+		return &erraux.Error{Err: err, Code: http.StatusForbidden}
+	case errors.Is(err, chrysom.ErrFailedAuthentication):
+		return &erraux.Error{Err: err, Code: http.StatusBadGateway}
+	}
+	return err
+}
+
+// newExtendWebhookEndpoint returns the extend endpoint, which updates only the
+// until of an existing webhook once the validator accepts it, and stores it again
+// for the owner it's stored for. It responds with a 404 when there is no such
+// webhook and a 403 when it belongs to another owner.
+func newExtendWebhookEndpoint(s Service, config transportConfig) endpoint.Endpoint {
+	now := config.now
+	if now == nil {
+		now = time.Now
+	}
+	v := config.v
+	if v == nil {
+		v = AlwaysValid()
+	}
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r := request.(*extendWebhookRequest)
+		iw, owner, err := getWithOwner(ctx, s, r.owner, r.id)
+		if err != nil {
+			return nil, getWebhookError(err)
+		}
+
+		iw.Webhook.Until = r.until
+		if iw.Webhook.Until.IsZero() {
+			iw.Webhook.Until = now().Add(r.duration).UTC()
+		}
+		err = validateWithin(ctx, v, iw.Webhook, config.validationTimeout)
+		if errors.Is(err, errValidationBudgetExceeded) {
+			return nil, &erraux.Error{Err: err, Code: http.StatusGatewayTimeout}
+		}
+		if err != nil {
+			return nil, &erraux.Error{Err: invalidWebhookError{err}, Message: "failed webhook validation", Code: http.StatusBadRequest}
+		}
+
+		if err = s.Add(ctx, owner, iw); err != nil {
+			return nil, err
+		}
+		return iw, nil
+	}
+}
+
+// getWithOwner gets the webhook along with the owner it's stored for, which is
+// taken to be the requested owner for Services that aren't OwnerGetters.
+func getWithOwner(ctx context.Context, s Service, owner, id string) (InternalWebhook, string, error) {
+	if g, ok := s.(OwnerGetter); ok {
+		return g.GetWithOwner(ctx, owner, id)
+	}
+	iw, err := s.Get(ctx, owner, id)
+	return iw, owner, err
+}
+
+// newGetAllWebhooksEndpoint returns the get all endpoint. When fetching the
+// webhooks fails, a non-nil snapshot younger than maxStaleness is served instead,
+// unless the request is scoped to an owner since the snapshot holds everyone's.
+// With storeOrder, the webhooks of services that are ItemListers are listed in
+// the order the store returned their items.
+func newGetAllWebhooksEndpoint(s Service, snapshot *Snapshot, maxStaleness time.Duration, storeOrder bool) endpoint.Endpoint {
+	getAll := getAllWebhooks(s, snapshot, maxStaleness, storeOrder)
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		r, _ := request.(*getAllWebhooksRequest)
+		if r == nil {
+			return getAll(ctx, "")
+		}
+		getResponse := getAll
+		switch {
+		case r.includeRaw:
+			getResponse = getRawWebhooks(s)
+		case r.page != nil:
+			getResponse = getWebhooksPage(s, r.page)
+		}
+		response, err := getResponse(ctx, r.owner)
+		if err != nil || len(r.fields) == 0 {
+			return response, err
+		}
+		return &projectedWebhooksResponse{response: response, fields: r.fields}, nil
+	}
+}
+
+// getRawWebhooks fetches the owner's stored items, or everyone's when the owner
+// is empty, failing with a 501 unless the service is an ItemLister. The snapshot
+// isn't used since it doesn't hold the items.
+func getRawWebhooks(s Service) func(context.Context, string) (interface{}, error) {
+	return func(ctx context.Context, owner string) (interface{}, error) {
+		l, ok := s.(ItemLister)
+		if !ok {
+			return nil, &erraux.Error{Err: errRawNotSupported, Code: http.StatusNotImplemented}
+		}
+		items, err := l.GetAllItems(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		return &rawWebhooksResponse{items: items}, nil
+	}
+}
+
+// getWebhooksPage fetches the page of the owner's webhooks, or of everyone's when
+// the owner is empty, failing with a 501 unless the service is a Pager. The
+// snapshot isn't used since it isn't sorted by item ID.
+func getWebhooksPage(s Service, page *webhooksPage) func(context.Context, string) (interface{}, error) {
+	return func(ctx context.Context, owner string) (interface{}, error) {
+		p, ok := s.(Pager)
+		if !ok {
+			return nil, &erraux.Error{Err: errPagingNotSupported, Code: http.StatusNotImplemented}
+		}
+		iws, more, err := p.GetPage(ctx, owner, page.offset, page.limit)
+		if err != nil {
+			return nil, err
+		}
+		return &webhooksPageResponse{webhooks: iws, page: page, more: more}, nil
+	}
+}
+
+// getAllWebhooks fetches the owner's webhooks, or everyone's with the snapshot
+// fallback when the owner is empty.
+func getAllWebhooks(s Service, snapshot *Snapshot, maxStaleness time.Duration, storeOrder bool) func(context.Context, string) (interface{}, error) {
+	getAllByOwner, getAll := s.GetAllByOwner, s.GetAll
+	if l, ok := s.(ItemLister); ok && storeOrder {
+		getAllByOwner = getAllInStoreOrder(l)
+		getAll = func(ctx context.Context) ([]InternalWebhook, error) { return getAllByOwner(ctx, "") }
+	}
+	return func(ctx context.Context, owner string) (interface{}, error) {
+		if owner != "" {
+			return getAllByOwner(ctx, owner)
+		}
+
+		iws, err := getAll(ctx)
+		if err == nil || snapshot == nil {
+			return iws, err
+		}
+
+		stale, age, ok := snapshot.Latest()
+		if !ok || age > maxStaleness {
+			return nil, err
+		}
+		return &staleWebhooksResponse{webhooks: stale, age: age}, nil
 	}
 }
 
-func newGetAllWebhooksEndpoint(s Service) endpoint.Endpoint {
-	return func(ctx context.Context, _ interface{}) (interface{}, error) {
-		return s.GetAll(ctx)
+// getAllInStoreOrder returns a func fetching the owner's webhooks, or everyone's
+// when the owner is empty, in the order the store returned their items.
+func getAllInStoreOrder(l ItemLister) func(context.Context, string) ([]InternalWebhook, error) {
+	return func(ctx context.Context, owner string) ([]InternalWebhook, error) {
+		items, err := l.GetAllItems(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		iws, err := ItemsToInternalWebhooks(items)
+		if err != nil {
+			return nil, fmt.Errorf(errFmt, errFailedItemConversion, err)
+		}
+		return iws, nil
 	}
 }
@@ -6,15 +6,21 @@ package ancla
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
+	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xmidt-org/ancla/chrysom"
 )
 
 func TestNewAddWebhookEndpoint(t *testing.T) {
 	assert := assert.New(t)
 	m := new(mockService)
-	endpoint := newAddWebhookEndpoint(m)
+	endpoint := newAddWebhookEndpoint(m, 0)
 	input := &addWebhookRequest{
 		owner:          "owner-val",
 		internalWebook: InternalWebhook{},
@@ -30,10 +36,160 @@ func TestNewAddWebhookEndpoint(t *testing.T) {
 	m.AssertExpectations(t)
 }
 
+func TestAddWebhookEndpointStoreTimeout(t *testing.T) {
+	input := &addWebhookRequest{
+		owner:          "owner-val",
+		internalWebook: InternalWebhook{},
+	}
+	errFake := errors.New("failed")
+
+	t.Run("Slow store", func(t *testing.T) {
+		assert := assert.New(t)
+		m := new(mockService)
+		// nolint:typecheck
+		m.On("Add", mock.Anything, "owner-val", input.internalWebook).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+		_, err := newAddWebhookEndpoint(m, time.Millisecond)(context.Background(), input)
+		assert.ErrorIs(err, errStoreBudgetExceeded)
+		var sc kithttp.StatusCoder
+		if assert.ErrorAs(err, &sc) {
+			assert.Equal(http.StatusGatewayTimeout, sc.StatusCode())
+		}
+	})
+
+	t.Run("Store within budget", func(t *testing.T) {
+		assert := assert.New(t)
+		m := new(mockService)
+		// nolint:typecheck
+		m.On("Add", mock.Anything, "owner-val", input.internalWebook).Return(errFake)
+		_, err := newAddWebhookEndpoint(m, time.Second)(context.Background(), input)
+		assert.Equal(errFake, err)
+	})
+}
+
+func TestAddWebhookEndpointItemTooLarge(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockService)
+	input := &addWebhookRequest{owner: "owner-val"}
+	// nolint:typecheck
+	m.On("Add", context.Background(), "owner-val", input.internalWebook).Return(fmt.Errorf("%w: 2 bytes exceeds the limit of 1 bytes", ErrItemTooLarge))
+	_, err := newAddWebhookEndpoint(m, 0)(context.Background(), input)
+	var sc kithttp.StatusCoder
+	if assert.ErrorAs(err, &sc) {
+		assert.Equal(http.StatusRequestEntityTooLarge, sc.StatusCode())
+	}
+}
+
+func TestAddWebhookEndpointCreateOnly(t *testing.T) {
+	input := &addWebhookRequest{
+		owner:          "owner-val",
+		internalWebook: InternalWebhook{},
+		createOnly:     true,
+	}
+	tcs := []struct {
+		desc         string
+		createErr    error
+		noCreator    bool
+		expectedCode int
+	}{
+		{
+			desc: "Created",
+		},
+		{
+			desc:         "Already exists",
+			createErr:    fmt.Errorf("%w: url", ErrWebhookExists),
+			expectedCode: http.StatusConflict,
+		},
+		{
+			desc:         "Service isn't a Creator",
+			noCreator:    true,
+			expectedCode: http.StatusNotImplemented,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockCreatorService)
+			var s Service = m
+			if tc.noCreator {
+				s = &m.mockService
+			} else {
+				// nolint:typecheck
+				m.On("Create", context.Background(), "owner-val", input.internalWebook).Return(tc.createErr)
+			}
+
+			_, err := newAddWebhookEndpoint(s, 0)(context.Background(), input)
+			// nolint:typecheck
+			m.AssertExpectations(t)
+			// nolint:typecheck
+			m.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+			if tc.expectedCode == 0 {
+				assert.NoError(err)
+				return
+			}
+			var sc kithttp.StatusCoder
+			if assert.ErrorAs(err, &sc) {
+				assert.Equal(tc.expectedCode, sc.StatusCode())
+			}
+		})
+	}
+}
+
+func TestAddWebhookEndpointResponse(t *testing.T) {
+	input := &addWebhookRequest{
+		owner: "owner-val",
+		internalWebook: InternalWebhook{
+			Webhook: Webhook{Config: DeliveryConfig{URL: "https://example.com"}},
+		},
+	}
+	tcs := []struct {
+		desc            string
+		pushResult      chrysom.PushResult
+		noResultAdder   bool
+		expectedCreated bool
+	}{
+		{
+			desc:            "Created",
+			pushResult:      chrysom.CreatedPushResult,
+			expectedCreated: true,
+		},
+		{
+			desc:       "Updated",
+			pushResult: chrysom.UpdatedPushResult,
+		},
+		{
+			desc:          "Service isn't a ResultAdder",
+			noResultAdder: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockResultAdderService)
+			var s Service = m
+			if tc.noResultAdder {
+				s = &m.mockService
+				// nolint:typecheck
+				m.On("Add", context.Background(), "owner-val", input.internalWebook).Return(nil)
+			} else {
+				// nolint:typecheck
+				m.On("AddWithResult", context.Background(), "owner-val", input.internalWebook).Return(tc.pushResult, nil)
+			}
+
+			resp, err := newAddWebhookEndpoint(s, 0)(context.Background(), input)
+			assert.NoError(err)
+			assert.Equal(&addWebhookResponse{webhook: input.internalWebook.Webhook, created: tc.expectedCreated}, resp)
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetAllWebhooksEndpoint(t *testing.T) {
 	assert := assert.New(t)
 	m := new(mockService)
-	endpoint := newGetAllWebhooksEndpoint(m)
+	endpoint := newGetAllWebhooksEndpoint(m, nil, 0, false)
 
 	respFake := []InternalWebhook{}
 	// nolint:typecheck
@@ -44,3 +200,332 @@ func TestGetAllWebhooksEndpoint(t *testing.T) {
 	// nolint:typecheck
 	m.AssertExpectations(t)
 }
+
+func TestGetAllWebhooksEndpointByOwner(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockService)
+	snapshot := &Snapshot{}
+	snapshot.Update(getTestInternalWebhooks())
+	endpoint := newGetAllWebhooksEndpoint(m, snapshot, time.Minute, false)
+
+	// nolint:typecheck
+	m.On("GetAllByOwner", context.Background(), "owner").Return([]InternalWebhook(nil), errFailedWebhooksFetch)
+	resp, err := endpoint(context.Background(), &getAllWebhooksRequest{owner: "owner"})
+	assert.ErrorIs(err, errFailedWebhooksFetch)
+	assert.Nil(resp)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestGetAllWebhooksEndpointFields(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockService)
+	endpoint := newGetAllWebhooksEndpoint(m, nil, 0, false)
+	iws := getTestInternalWebhooks()
+
+	// nolint:typecheck
+	m.On("GetAll", context.Background()).Return(iws, nil)
+	resp, err := endpoint(context.Background(), &getAllWebhooksRequest{fields: []string{"url"}})
+	assert.NoError(err)
+	assert.Equal(&projectedWebhooksResponse{response: iws, fields: []string{"url"}}, resp)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestGetAllWebhooksEndpointIncludeRaw(t *testing.T) {
+	assert := assert.New(t)
+	items := getTestItems()
+
+	m := new(mockItemListerService)
+	// nolint:typecheck
+	m.On("GetAllItems", context.Background(), "owner").Return(items, nil)
+	resp, err := newGetAllWebhooksEndpoint(m, nil, 0, false)(context.Background(), &getAllWebhooksRequest{owner: "owner", includeRaw: true})
+	assert.NoError(err)
+	assert.Equal(&rawWebhooksResponse{items: items}, resp)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+
+	resp, err = newGetAllWebhooksEndpoint(new(mockService), nil, 0, false)(context.Background(), &getAllWebhooksRequest{includeRaw: true})
+	assert.ErrorIs(err, errRawNotSupported)
+	assert.Nil(resp)
+	var sc kithttp.StatusCoder
+	if assert.ErrorAs(err, &sc) {
+		assert.Equal(http.StatusNotImplemented, sc.StatusCode())
+	}
+}
+
+func TestGetAllWebhooksEndpointStoreOrder(t *testing.T) {
+	assert := assert.New(t)
+	items := getTestItems()
+	reversed := chrysom.Items{items[1], items[0]}
+	iws := getTestInternalWebhooks()
+
+	m := new(mockItemListerService)
+	// nolint:typecheck
+	m.On("GetAllItems", context.Background(), "").Return(reversed, nil)
+	// nolint:typecheck
+	m.On("GetAllItems", context.Background(), "owner").Return(chrysom.Items{{ID: "id", Data: map[string]interface{}{"PartnerIDs": "comcast"}}}, nil)
+	endpoint := newGetAllWebhooksEndpoint(m, nil, 0, true)
+	resp, err := endpoint(context.Background(), nil)
+	assert.NoError(err)
+	assert.Equal([]InternalWebhook{iws[1], iws[0]}, resp)
+
+	resp, err = endpoint(context.Background(), &getAllWebhooksRequest{owner: "owner"})
+	assert.ErrorIs(err, errFailedItemConversion)
+	assert.Nil(resp)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestGetAllWebhooksEndpointPage(t *testing.T) {
+	assert := assert.New(t)
+	iws := getTestInternalWebhooks()
+	page := &webhooksPage{offset: 1, limit: 1}
+
+	m := new(mockPagerService)
+	// nolint:typecheck
+	m.On("GetPage", context.Background(), "", 1, 1).Return(iws[1:], false, nil)
+	resp, err := newGetAllWebhooksEndpoint(m, nil, 0, false)(context.Background(), &getAllWebhooksRequest{page: page})
+	assert.NoError(err)
+	assert.Equal(&webhooksPageResponse{webhooks: iws[1:], page: page}, resp)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+
+	resp, err = newGetAllWebhooksEndpoint(new(mockService), nil, 0, false)(context.Background(), &getAllWebhooksRequest{page: page})
+	assert.ErrorIs(err, errPagingNotSupported)
+	assert.Nil(resp)
+	var sc kithttp.StatusCoder
+	if assert.ErrorAs(err, &sc) {
+		assert.Equal(http.StatusNotImplemented, sc.StatusCode())
+	}
+}
+
+func TestGetWebhookEndpoint(t *testing.T) {
+	tcs := []struct {
+		desc         string
+		err          error
+		expectedCode int
+	}{
+		{
+			desc: "Found",
+		},
+		{
+			desc:         "Not found",
+			err:          ErrWebhookNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "Owned by another principal",
+			err:          ErrWebhookNotOwned,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Failure",
+			err:          errFailedWebhooksFetch,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockService)
+			iw := getTestInternalWebhooks()[0]
+			// nolint:typecheck
+			m.On("Get", context.Background(), "owner", "id").Return(iw, tc.err)
+			resp, err := newGetWebhookEndpoint(m)(context.Background(), &getWebhookRequest{owner: "owner", id: "id"})
+			if tc.err == nil {
+				assert.NoError(err)
+				assert.Equal(iw, resp)
+				return
+			}
+			assert.ErrorIs(err, tc.err)
+			code := http.StatusInternalServerError
+			var sc kithttp.StatusCoder
+			if errors.As(err, &sc) {
+				code = sc.StatusCode()
+			}
+			assert.Equal(tc.expectedCode, code)
+		})
+	}
+}
+
+func TestExtendWebhookEndpoint(t *testing.T) {
+	refTime := getRefTime()
+	checkUntil, err := CheckUntil(0, time.Hour, getRefTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tooLateErr := checkUntil(Webhook{Until: refTime.Add(2 * time.Hour)})
+	tcs := []struct {
+		desc          string
+		request       *extendWebhookRequest
+		unowned       bool
+		getErr        error
+		addErr        error
+		expectedUntil time.Time
+		expectedErr   error
+		expectedCode  int
+		expectedText  string
+	}{
+		{
+			desc:          "Duration",
+			request:       &extendWebhookRequest{owner: "owner", id: "id", duration: 5 * time.Minute},
+			expectedUntil: refTime.Add(5 * time.Minute),
+		},
+		{
+			desc:          "Until",
+			request:       &extendWebhookRequest{owner: "owner", id: "id", until: refTime.Add(time.Hour)},
+			expectedUntil: refTime.Add(time.Hour),
+		},
+		{
+			desc:          "Stored without an owner",
+			request:       &extendWebhookRequest{owner: "owner", id: "id", duration: 5 * time.Minute},
+			unowned:       true,
+			expectedUntil: refTime.Add(5 * time.Minute),
+		},
+		{
+			desc:         "Past the max TTL",
+			request:      &extendWebhookRequest{owner: "owner", id: "id", duration: 2 * time.Hour},
+			expectedErr:  errInvalidUntil,
+			expectedCode: http.StatusBadRequest,
+			expectedText: tooLateErr.Error(),
+		},
+		{
+			desc:         "Not found",
+			request:      &extendWebhookRequest{owner: "owner", id: "id", duration: time.Minute},
+			getErr:       fmt.Errorf("%w: %w", ErrWebhookNotFound, chrysom.ErrItemNotFound),
+			expectedErr:  ErrWebhookNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "Owned by another principal",
+			request:      &extendWebhookRequest{owner: "owner", id: "id", duration: time.Minute},
+			getErr:       fmt.Errorf("%w: id", ErrWebhookNotOwned),
+			expectedErr:  ErrWebhookNotOwned,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:    "Argus authentication failure",
+			request: &extendWebhookRequest{owner: "owner", id: "id", duration: time.Minute},
+			getErr: fmt.Errorf("%w: %w", errFailedWebhooksFetch,
+				&chrysom.APIError{Code: http.StatusForbidden, Err: chrysom.ErrFailedAuthentication}),
+			expectedErr:  chrysom.ErrFailedAuthentication,
+			expectedCode: http.StatusBadGateway,
+		},
+		{
+			desc:          "Store failure",
+			request:       &extendWebhookRequest{owner: "owner", id: "id", duration: time.Minute},
+			addErr:        errFailedWebhookPush,
+			expectedUntil: refTime.Add(time.Minute),
+			expectedErr:   errFailedWebhookPush,
+			expectedCode:  http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockOwnerGetterService)
+			iw := getTestInternalWebhooks()[0]
+			extended := iw
+			extended.Webhook.Until = tc.expectedUntil
+			storedOwner := "owner"
+			if tc.unowned {
+				storedOwner = ""
+			}
+			// nolint:typecheck
+			m.On("GetWithOwner", context.Background(), "owner", "id").Return(iw, storedOwner, tc.getErr)
+			if !tc.expectedUntil.IsZero() {
+				// nolint:typecheck
+				m.On("Add", context.Background(), storedOwner, extended).Return(tc.addErr)
+			}
+
+			resp, err := newExtendWebhookEndpoint(m, transportConfig{now: getRefTime, v: checkUntil})(context.Background(), tc.request)
+			// nolint:typecheck
+			m.AssertExpectations(t)
+			if tc.expectedErr == nil {
+				assert.NoError(err)
+				assert.Equal(extended, resp)
+				assert.Equal(iw.Webhook.Config.Secret, resp.(InternalWebhook).Webhook.Config.Secret)
+				return
+			}
+			assert.ErrorIs(err, tc.expectedErr)
+			code := http.StatusInternalServerError
+			var sc kithttp.StatusCoder
+			if errors.As(err, &sc) {
+				code = sc.StatusCode()
+			}
+			assert.Equal(tc.expectedCode, code)
+			if tc.expectedCode == http.StatusBadRequest {
+				// so that ProblemJSON reports it as a validation failure.
+				assert.ErrorIs(err, errWebhookValidation)
+			}
+			if tc.expectedText != "" {
+				assert.Contains(err.Error(), tc.expectedText)
+			}
+		})
+	}
+}
+
+func TestGetAllWebhooksEndpointStale(t *testing.T) {
+	var (
+		errFetch = errors.New("argus unreachable")
+		now      = getRefTime()
+		stale    = getTestInternalWebhooks()
+	)
+	tcs := []struct {
+		desc        string
+		snapshot    bool
+		updated     bool
+		age         time.Duration
+		expectedErr error
+	}{
+		{
+			desc:     "Fresh enough snapshot",
+			snapshot: true,
+			updated:  true,
+			age:      30 * time.Second,
+		},
+		{
+			desc:        "Too stale snapshot",
+			snapshot:    true,
+			updated:     true,
+			age:         2 * time.Minute,
+			expectedErr: errFetch,
+		},
+		{
+			desc:        "Snapshot without updates",
+			snapshot:    true,
+			expectedErr: errFetch,
+		},
+		{
+			desc:        "No snapshot",
+			expectedErr: errFetch,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockService)
+			// nolint:typecheck
+			m.On("GetAll", context.Background()).Return([]InternalWebhook(nil), errFetch)
+			var snapshot *Snapshot
+			if tc.snapshot {
+				clock := now.Add(-tc.age)
+				snapshot = &Snapshot{Now: func() time.Time { return clock }}
+				if tc.updated {
+					snapshot.Update(stale)
+				}
+				clock = now
+			}
+
+			resp, err := newGetAllWebhooksEndpoint(m, snapshot, time.Minute, false)(context.Background(), nil)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+				assert.Nil(resp)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(&staleWebhooksResponse{webhooks: stale, age: tc.age}, resp)
+		})
+	}
+}
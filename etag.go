@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	etagHeader        = "ETag"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+type ifNoneMatchKey struct{}
+
+// captureIfNoneMatch is a kithttp.RequestFunc that keeps the request's
+// If-None-Match header for the response encoder.
+func captureIfNoneMatch(ctx context.Context, r *http.Request) context.Context {
+	if v := r.Header.Get(ifNoneMatchHeader); v != "" {
+		return context.WithValue(ctx, ifNoneMatchKey{}, v)
+	}
+	return ctx
+}
+
+// computeETag returns a strong ETag of the given response body.
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header kept in the context
+// matches the given ETag, using the weak comparison If-None-Match calls for.
+func etagMatches(ctx context.Context, etag string) bool {
+	ifNoneMatch, _ := ctx.Value(ifNoneMatchKey{}).(string)
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllWebhooksHandlerETag(t *testing.T) {
+	m := new(mockService)
+	// nolint:typecheck
+	m.On("GetAll", mock.Anything).Return(encodeGetAllInput(), nil)
+	handler := NewGetAllWebhooksHandler(m, HandlerConfig{})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, GetAllWebhooksPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	etag := recorder.Header().Get(etagHeader)
+	require.NotEmpty(t, etag)
+	assert.Equal(t, computeETag(recorder.Body.Bytes()), etag)
+
+	tcs := []struct {
+		desc         string
+		ifNoneMatch  string
+		expectedCode int
+	}{
+		{
+			desc:         "Missing header",
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Match",
+			ifNoneMatch:  etag,
+			expectedCode: http.StatusNotModified,
+		},
+		{
+			desc:         "Weak match in a list",
+			ifNoneMatch:  `"other", W/` + etag,
+			expectedCode: http.StatusNotModified,
+		},
+		{
+			desc:         "Mismatch",
+			ifNoneMatch:  `"other"`,
+			expectedCode: http.StatusOK,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			r := httptest.NewRequest(http.MethodGet, GetAllWebhooksPath, nil)
+			if tc.ifNoneMatch != "" {
+				r.Header.Set(ifNoneMatchHeader, tc.ifNoneMatch)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, r)
+
+			assert.Equal(tc.expectedCode, recorder.Code)
+			assert.Equal(etag, recorder.Header().Get(etagHeader))
+			if tc.expectedCode == http.StatusNotModified {
+				assert.Empty(recorder.Body.String())
+			} else {
+				assert.NotEmpty(recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xmidt-org/ancla/chrysom"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultExpiryMismatchTolerance = time.Minute
+	defaultMaxReadRepairsPerMinute = 10
+)
+
+// Directions of expiry mismatches.
+const (
+	// TTLLaterDirection is used for items the store keeps after their webhook's
+	// until, so they're served although their webhook has expired.
+	TTLLaterDirection = "ttl_later"
+	// UntilLaterDirection is used for items the store drops before their
+	// webhook's until.
+	UntilLaterDirection = "until_later"
+)
+
+// expiryChecker finds the items whose TTL doesn't agree with the until of
+// their webhook, and optionally rewrites them with the earlier of the two.
+type expiryChecker struct {
+	logger     *zap.Logger
+	pusher     chrysom.Pusher
+	now        func() time.Time
+	tolerance  time.Duration
+	mismatches *prometheus.CounterVec
+	repair     bool
+	maxRepairs int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	repairs     int
+}
+
+func newExpiryChecker(cfg Config, pusher chrysom.Pusher, now func() time.Time) *expiryChecker {
+	tolerance := cfg.ExpiryMismatchTolerance
+	if tolerance <= 0 {
+		tolerance = defaultExpiryMismatchTolerance
+	}
+	maxRepairs := cfg.MaxReadRepairsPerMinute
+	if maxRepairs <= 0 {
+		maxRepairs = defaultMaxReadRepairsPerMinute
+	}
+	return &expiryChecker{
+		logger:     cfg.Logger,
+		pusher:     pusher,
+		now:        now,
+		tolerance:  tolerance,
+		mismatches: cfg.ExpiryMismatches,
+		repair:     cfg.ReadRepair,
+		maxRepairs: maxRepairs,
+	}
+}
+
+// check logs and counts the items whose TTL and until disagree by more than
+// the tolerance, repairing them if enabled. Items without a TTL or an until,
+// or that can't be converted, are skipped.
+func (c *expiryChecker) check(ctx context.Context, items chrysom.Items) {
+	if c == nil {
+		return
+	}
+	now := c.now()
+	for _, item := range items {
+		if item.TTL == nil {
+			continue
+		}
+		iw, err := ItemToInternalWebhook(item)
+		if err != nil || iw.Webhook.Until.IsZero() {
+			continue
+		}
+
+		until := iw.Webhook.Until
+		ttlExpiry := now.Add(time.Duration(*item.TTL) * time.Second)
+		diff := ttlExpiry.Sub(until)
+		if diff.Abs() <= c.tolerance {
+			continue
+		}
+
+		direction := TTLLaterDirection
+		earliest := until
+		if diff < 0 {
+			direction = UntilLaterDirection
+			earliest = ttlExpiry
+		}
+		logger := c.logger.With(zap.String("id", item.ID), zap.String("direction", direction),
+			zap.Time("until", until), zap.Time("ttl_expiry", ttlExpiry))
+		logger.Warn("Item TTL and webhook until disagree")
+		if c.mismatches != nil {
+			c.mismatches.With(prometheus.Labels{DirectionLabel: direction}).Inc()
+		}
+
+		if !c.repair {
+			continue
+		}
+		if !c.allowRepair(now) {
+			logger.Debug("Skipping item repair, the repair rate limit is reached")
+			continue
+		}
+		iw.Webhook.Until = earliest
+		repaired, err := InternalWebhookToItem(c.now, iw)
+		if err != nil {
+			logger.Error("Failed to build repaired item", zap.Error(err))
+			continue
+		}
+		repaired.ID = item.ID
+		if _, err := c.pusher.PushItem(ctx, item.Owner, repaired); err != nil {
+			logger.Error("Failed to repair item", zap.Error(err))
+			continue
+		}
+		logger.Info("Repaired item expiry", zap.Time("repaired_until", earliest))
+	}
+}
+
+// allowRepair reports whether another repair fits in the current minute long
+// window, counting it if so.
+func (c *expiryChecker) allowRepair(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.windowStart) >= time.Minute {
+		c.windowStart = now
+		c.repairs = 0
+	}
+	if c.repairs >= c.maxRepairs {
+		return false
+	}
+	c.repairs++
+	return true
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/ancla/model"
+	"go.uber.org/zap"
+)
+
+// expiryTestItem returns the item of a webhook expiring at until, with a TTL
+// expiring at ttlExpiry.
+func expiryTestItem(t *testing.T, now time.Time, id string, until, ttlExpiry time.Duration) model.Item {
+	iw := getTestInternalWebhooks()[0]
+	iw.Webhook.Until = now.Add(until)
+	item, err := InternalWebhookToItem(func() time.Time { return now }, iw)
+	require.NoError(t, err)
+	ttl := int64(ttlExpiry.Seconds())
+	item.ID, item.TTL, item.Owner = id, &ttl, "owner"
+	return item
+}
+
+func TestExpiryCheck(t *testing.T) {
+	now := getRefTime()
+	tcs := []struct {
+		desc              string
+		until             time.Duration
+		ttlExpiry         time.Duration
+		expectedDirection string
+		expectedUntil     time.Duration
+	}{
+		{
+			desc:      "Within tolerance",
+			until:     time.Hour,
+			ttlExpiry: time.Hour + 30*time.Second,
+		},
+		{
+			desc:              "TTL later",
+			until:             10 * time.Minute,
+			ttlExpiry:         time.Hour,
+			expectedDirection: TTLLaterDirection,
+			expectedUntil:     10 * time.Minute,
+		},
+		{
+			desc:              "Until later",
+			until:             time.Hour,
+			ttlExpiry:         10 * time.Minute,
+			expectedDirection: UntilLaterDirection,
+			expectedUntil:     10 * time.Minute,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockPushReader)
+			mismatches := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "mismatches"}, []string{DirectionLabel})
+			c := newExpiryChecker(Config{
+				Logger:           zap.NewNop(),
+				ExpiryMismatches: mismatches,
+				ReadRepair:       true,
+			}, m, func() time.Time { return now })
+
+			if tc.expectedDirection != "" {
+				// nolint:typecheck
+				m.On("PushItem", context.TODO(), "owner", mock.MatchedBy(func(item model.Item) bool {
+					iw, err := ItemToInternalWebhook(item)
+					return err == nil && item.ID == "id" &&
+						*item.TTL == int64(tc.expectedUntil.Seconds()) &&
+						iw.Webhook.Until.Equal(now.Add(tc.expectedUntil))
+				})).Return(chrysom.UpdatedPushResult, nil).Once()
+			}
+			c.check(context.TODO(), chrysom.Items{expiryTestItem(t, now, "id", tc.until, tc.ttlExpiry)})
+
+			for _, direction := range []string{TTLLaterDirection, UntilLaterDirection} {
+				expected := 0.0
+				if direction == tc.expectedDirection {
+					expected = 1
+				}
+				assert.Equal(expected, counterValue(t, mismatches.WithLabelValues(direction)))
+			}
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExpiryCheckRepairRateLimit(t *testing.T) {
+	now := getRefTime()
+	m := new(mockPushReader)
+	c := newExpiryChecker(Config{
+		Logger:                  zap.NewNop(),
+		ReadRepair:              true,
+		MaxReadRepairsPerMinute: 1,
+	}, m, func() time.Time { return now })
+	items := chrysom.Items{
+		expiryTestItem(t, now, "a", time.Minute, time.Hour),
+		expiryTestItem(t, now, "b", time.Minute, time.Hour),
+	}
+	// nolint:typecheck
+	m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(chrysom.UpdatedPushResult, nil)
+
+	c.check(context.TODO(), items)
+	// nolint:typecheck
+	m.AssertNumberOfCalls(t, "PushItem", 1)
+
+	now = now.Add(time.Minute)
+	c.check(context.TODO(), items)
+	// nolint:typecheck
+	m.AssertNumberOfCalls(t, "PushItem", 2)
+
+	// without ReadRepair mismatches are only reported.
+	c.repair = false
+	now = now.Add(time.Minute)
+	c.check(context.TODO(), items)
+	// nolint:typecheck
+	m.AssertNumberOfCalls(t, "PushItem", 2)
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Names of the handlers provided by ProvideHandlers.
+const (
+	AddHandlerName    = "ancla_add_handler"
+	GetAllHandlerName = "ancla_get_all_handler"
+)
+
+// ClockName is the name of the optional func() time.Time used by NewTTLVConfig
+// as the clock of the until check, e.g. a fake clock in tests.
+const ClockName = "ancla_clock"
+
+// HandlersIn is an uber/fx parameter with the dependencies of the webhook handlers.
+type HandlersIn struct {
+	fx.In
+
+	Service Service
+	Config  HandlerConfig `optional:"true"`
+
+	// Measures, if provided, supplies the handlers' metrics missing from Config.
+	Measures *Measures `optional:"true"`
+}
+
+// HandlersOut is an uber/fx result with the named webhook handlers.
+type HandlersOut struct {
+	fx.Out
+
+	Add    http.Handler `name:"ancla_add_handler"`
+	GetAll http.Handler `name:"ancla_get_all_handler"`
+}
+
+// NewHandlers builds the webhook handlers from the given service and configuration.
+func NewHandlers(in HandlersIn) HandlersOut {
+	if in.Config.AddRequestBytes == nil && in.Measures != nil {
+		in.Config.AddRequestBytes = in.Measures.AddRequestBytes
+	}
+	if in.Config.BreakGlassUses == nil && in.Measures != nil {
+		in.Config.BreakGlassUses = in.Measures.BreakGlassUses
+	}
+	return HandlersOut{
+		Add:    NewAddWebhookHandler(in.Service, in.Config),
+		GetAll: NewGetAllWebhooksHandler(in.Service, in.Config),
+	}
+}
+
+// TTLVConfigIn is an uber/fx parameter with the dependencies of a TTLVConfig.
+type TTLVConfigIn struct {
+	fx.In
+
+	Config TTLConfig
+
+	// Now, if provided, is the clock of the until check.
+	Now func() time.Time `name:"ancla_clock" optional:"true"`
+}
+
+// NewTTLVConfig builds a TTLVConfig like TTLVConfigFromConfig, with the clock
+// named ClockName, or time.Now when there is none.
+func NewTTLVConfig(in TTLVConfigIn) (TTLVConfig, error) {
+	return TTLVConfigFromConfig(in.Config, in.Now)
+}
+
+// ServiceIn is an uber/fx parameter with the dependencies of the Service.
+type ServiceIn struct {
+	fx.In
+
+	Config    Config
+	Lifecycle fx.Lifecycle
+}
+
+// NewFxService builds the Service with NewService, closing it when the app
+// stops.
+func NewFxService(in ServiceIn) (Service, error) {
+	svc, err := NewService(in.Config, nil)
+	if err != nil {
+		return nil, err
+	}
+	in.Lifecycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return svc.Close()
+		},
+	})
+	return svc, nil
+}
+
+// ProvideService provides the Service as uber/fx options. It requires a Config,
+// and closes the Service on shutdown.
+func ProvideService() fx.Option {
+	return fx.Options(
+		fx.Provide(NewFxService),
+	)
+}
+
+// ProvideHandlers provides the webhook handlers as uber/fx options, named
+// AddHandlerName and GetAllHandlerName. It requires a Service and optionally
+// uses a HandlerConfig.
+func ProvideHandlers() fx.Option {
+	return fx.Options(
+		fx.Provide(NewHandlers),
+	)
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/ancla/model"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+// memoryPushReader is an in-memory chrysom.PushReader.
+type memoryPushReader struct {
+	mu    sync.Mutex
+	items map[string]model.Item
+}
+
+func (m *memoryPushReader) GetItems(context.Context, string) (chrysom.Items, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var items chrysom.Items
+	for _, item := range m.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (m *memoryPushReader) PushItem(_ context.Context, _ string, item model.Item) (chrysom.PushResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.items == nil {
+		m.items = make(map[string]model.Item)
+	}
+	_, exists := m.items[item.ID]
+	m.items[item.ID] = item
+	if exists {
+		return chrysom.UpdatedPushResult, nil
+	}
+	return chrysom.CreatedPushResult, nil
+}
+
+func (m *memoryPushReader) RemoveItem(_ context.Context, id, _ string) (model.Item, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[id]
+	if !ok {
+		return model.Item{}, chrysom.ErrItemNotFound
+	}
+	delete(m.items, id)
+	return item, nil
+}
+
+func TestProvideHandlers(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var handlers struct {
+		fx.In
+
+		Add    http.Handler `name:"ancla_add_handler"`
+		GetAll http.Handler `name:"ancla_get_all_handler"`
+	}
+	svc := &service{
+		argus:  new(memoryPushReader),
+		logger: zap.NewNop(),
+		now:    time.Now,
+	}
+	app := fxtest.New(t,
+		fx.Supply(
+			fx.Annotate(svc, fx.As(new(Service))),
+			HandlerConfig{DisablePartnerIDs: true},
+		),
+		ProvideHandlers(),
+		fx.Populate(&handlers),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	recorder := httptest.NewRecorder()
+	handlers.Add.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, AddWebhookPath, bytes.NewBufferString(addWebhookDecoderDurationInput())))
+	require.Equal(http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handlers.GetAll.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, GetAllWebhooksPath, nil))
+	require.Equal(http.StatusOK, recorder.Code)
+	assert.Contains(recorder.Body.String(), `"url":"example.com:443"`)
+	assert.NotContains(recorder.Body.String(), "superSecretXYZ")
+}
+
+func TestProvideService(t *testing.T) {
+	var svc Service
+	app := fxtest.New(t,
+		fx.Supply(Config{
+			BasicClientConfig: chrysom.BasicClientConfig{
+				Address: "example.com",
+				Bucket:  "bucket-name",
+			},
+		}),
+		ProvideService(),
+		fx.Populate(&svc),
+	)
+	app.RequireStart()
+	app.RequireStop()
+
+	// stopping the app closed the service.
+	_, err := svc.GetAll(context.Background())
+	assert.ErrorIs(t, err, chrysom.ErrClientClosed)
+}
+
+func TestNewTTLVConfig(t *testing.T) {
+	clock := fx.Provide(fx.Annotate(
+		func() func() time.Time { return getRefTime },
+		fx.ResultTags(`name:"ancla_clock"`),
+	))
+	tcs := []struct {
+		desc    string
+		options []fx.Option
+		fake    bool
+	}{
+		{
+			desc: "Without a clock",
+		},
+		{
+			desc:    "With a clock",
+			options: []fx.Option{clock},
+			fake:    true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			var ttl TTLVConfig
+			app := fxtest.New(t,
+				fx.Supply(TTLConfig{MaxTTL: "1h", Jitter: "1m"}),
+				fx.Options(tc.options...),
+				fx.Provide(NewTTLVConfig),
+				fx.Populate(&ttl),
+			)
+			app.RequireStart()
+			defer app.RequireStop()
+
+			assert.Equal(time.Hour, ttl.Max)
+			assert.Equal(time.Minute, ttl.Jitter)
+			if assert.NotNil(ttl.Now) {
+				assert.Equal(tc.fake, ttl.Now().Equal(getRefTime()))
+			}
+		})
+	}
+}
@@ -9,43 +9,280 @@ import (
 	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultMaxStaleness        = time.Minute
+	defaultPageLimit           = 100
+	defaultMaxRequestBodyBytes = 1 << 20
+)
+
 // NewAddWebhookHandler returns an HTTP handler for adding
 // a webhook registration.
 func NewAddWebhookHandler(s Service, config HandlerConfig) http.Handler {
-	return kithttp.NewServer(
-		newAddWebhookEndpoint(s),
+	return trackCommitted(kithttp.NewServer(
+		newAddWebhookEndpoint(s, config.StoreTimeout),
 		addWebhookRequestDecoder(newTransportConfig(config)),
-		encodeAddWebhookResponse,
-		kithttp.ServerErrorEncoder(errorEncoder(config.GetLogger)),
-	)
+		addWebhookResponseEncoder(config.LegacyResponse),
+		kithttp.ServerErrorEncoder(config.errorEncoder()),
+	))
 }
 
 // NewGetAllWebhooksHandler returns an HTTP handler for fetching
-// all the currently registered webhooks.
+// all the currently registered webhooks, sorted by item ID unless
+// HandlerConfig.StoreOrder is set. Requests with the limit or offset query
+// parameters get a page of the webhooks, with a Link header to the next page
+// when more remain.
 func NewGetAllWebhooksHandler(s Service, config HandlerConfig) http.Handler {
-	return kithttp.NewServer(
-		newGetAllWebhooksEndpoint(s),
-		kithttp.NopRequestDecoder,
-		encodeGetAllWebhooksResponse,
-		kithttp.ServerErrorEncoder(errorEncoder(config.GetLogger)),
-	)
+	return trackCommitted(kithttp.NewServer(
+		newGetAllWebhooksEndpoint(s, config.StaleSnapshot, config.maxStaleness(), config.StoreOrder),
+		getAllWebhooksRequestDecoder(config),
+		config.getAllEncoder(),
+		config.getAllServerOptions()...,
+	))
+}
+
+// NewGetWebhookHandler returns an HTTP handler for fetching a webhook
+// registration by its ID, taken from the "id" path value or else the last
+// segment of the request path.
+func NewGetWebhookHandler(s Service, config HandlerConfig) http.Handler {
+	return trackCommitted(kithttp.NewServer(
+		newGetWebhookEndpoint(s),
+		getWebhookRequestDecoder,
+		encodeGetWebhookResponse,
+		kithttp.ServerErrorEncoder(config.errorEncoder()),
+	))
+}
+
+// NewExtendWebhookHandler returns an HTTP handler for extending a webhook
+// registration, taken by ID like NewGetWebhookHandler, to the until or duration
+// from now given in a JSON body such as {"duration": "5m"}. Only the webhook's
+// until is changed, so the secret doesn't have to be sent again. The extended
+// webhook is validated by HandlerConfig.V.
+func NewExtendWebhookHandler(s Service, config HandlerConfig) http.Handler {
+	tc := newTransportConfig(config)
+	return trackCommitted(kithttp.NewServer(
+		newExtendWebhookEndpoint(s, tc),
+		extendWebhookRequestDecoder(tc),
+		encodeGetWebhookResponse,
+		kithttp.ServerErrorEncoder(config.errorEncoder()),
+	))
 }
 
 // HandlerConfig contains configuration for all components that handlers depend on
-// from the service to the transport layers.
+// from the service to the transport layers. NewHandlerConfig builds one with
+// safe defaults from HandlerOptions.
 type HandlerConfig struct {
+	// V validates added webhooks. Use AllValidators to report every failure,
+	// listed in the "details" of the 400 response.
 	V                 Validator
 	DisablePartnerIDs bool
 	GetLogger         func(context.Context) *zap.Logger
+
+	// DefaultsPolicy fills in the optional fields of added webhooks.
+	// (Optional). Defaults to DefaultPolicy.
+	DefaultsPolicy DefaultsPolicy
+
+	// ValidationTimeout caps the time spent validating an added webhook, which
+	// may include DNS lookups, so that a slow validation leaves time for storing it.
+	// (Optional). Defaults to no cap beyond the request's own deadline.
+	ValidationTimeout time.Duration
+
+	// StoreTimeout caps the time spent storing an added webhook.
+	// (Optional). Defaults to no cap beyond the request's own deadline.
+	StoreTimeout time.Duration
+
+	// AcceptEpochUntil, if true, accepts added webhooks whose until field is given
+	// as epoch seconds instead of an RFC 3339 time.
+	// (Optional). Defaults to false, which rejects them with a suggestion of the
+	// correct value.
+	AcceptEpochUntil bool
+
+	// RequireContentType, if true, rejects added webhooks without a Content-Type
+	// header. Requests with a Content-Type other than application/json are always
+	// rejected with a 415.
+	// (Optional). Defaults to false, which treats a missing Content-Type as JSON.
+	RequireContentType bool
+
+	// LowercasePartnerIDs, if true, lowercases the partner IDs of added webhooks
+	// along with the normalization NormalizePartnerIDs always does.
+	// (Optional). Defaults to false.
+	LowercasePartnerIDs bool
+
+	// CacheRejectedURLs, if true, rejects added webhooks whose receiver URL was
+	// recently rejected as invalid with the same error, without validating them
+	// again. Only invalid receiver URL errors are cached.
+	// (Optional). Defaults to false.
+	CacheRejectedURLs bool
+
+	// RejectedURLCacheTTL is how long a rejected receiver URL is cached.
+	// (Optional). Defaults to 30 seconds.
+	RejectedURLCacheTTL time.Duration
+
+	// StaleSnapshot, if set, is served by the get all handler when fetching the
+	// webhooks fails, as long as it is younger than MaxStaleness. Such responses
+	// carry the X-Ancla-Stale and Age headers. Pass it to StartListener as a Watch
+	// to keep it current.
+	// (Optional). Defaults to failing when the webhooks can't be fetched.
+	StaleSnapshot *Snapshot
+
+	// MaxStaleness is the age after which StaleSnapshot is no longer served.
+	// (Optional). Defaults to 1 minute.
+	MaxStaleness time.Duration
+
+	// GetAllByOwner, if true, makes the get all handler only list the webhooks of
+	// the request's principal, rejecting requests without one with a 403. The
+	// StaleSnapshot isn't served for these requests.
+	// (Optional). Defaults to false, which lists all webhooks.
+	GetAllByOwner bool
+
+	// StoreOrder, if true, lists the webhooks of the get all handler in the order
+	// the store returned them, for debugging. That order may change between calls.
+	// The service must be an ItemLister for it, and pages stay sorted.
+	// (Optional). Defaults to false, which sorts them by item ID.
+	StoreOrder bool
+
+	// IsAdmin reports whether a request comes from an admin. Only admins may set
+	// the get all handler's include_raw query parameter, which lists the stored
+	// items, with their secrets redacted, along with the webhooks decoded from
+	// them, sorted by item ID unless StoreOrder is set. The service must be an
+	// ItemLister for it.
+	// (Optional). Defaults to nil, which rejects include_raw with a 403.
+	IsAdmin func(*http.Request) bool
+
+	// DefaultPageLimit is the number of webhooks in a page of the get all handler
+	// when the request's limit query parameter is missing or 0. Paging requires
+	// the service to be a Pager.
+	// (Optional). Defaults to 100.
+	DefaultPageLimit int
+
+	// DisableCompression, if true, turns off the gzip support of the handlers:
+	// decompressing added webhooks sent with Content-Encoding: gzip, and gzip
+	// encoding the get all responses of requests with Accept-Encoding: gzip.
+	// (Optional). Defaults to false.
+	DisableCompression bool
+
+	// AddRequestBytes observes the body size of every add webhook request,
+	// labeled by VersionLabel: V1Version once the body is decoded, otherwise
+	// InvalidVersion.
+	// (Optional). Defaults to Measures.AddRequestBytes when the handlers are
+	// provided by ProvideHandlers, otherwise sizes aren't observed.
+	AddRequestBytes prometheus.ObserverVec
+
+	// BreakGlass decides whether an add webhook request sent with the
+	// X-Ancla-Break-Glass: true header may skip the host lookups of URL
+	// validation, to restore registrations while DNS is down. Only the lookups
+	// are skipped: URLs whose host is an IP and every other validation are still
+	// checked, but the rejected URL cache is bypassed. Every use is logged as an
+	// audit entry and counted by BreakGlassUses. Requests with the header that
+	// BreakGlass refuses are rejected with a 403.
+	// (Optional). Defaults to nil, which refuses every break glass request.
+	BreakGlass func(*http.Request) bool
+
+	// BreakGlassUses counts the add webhook requests that skipped host lookups
+	// through BreakGlass.
+	// (Optional). Defaults to Measures.BreakGlassUses when the handlers are
+	// provided by ProvideHandlers, otherwise uses aren't counted.
+	BreakGlassUses prometheus.Counter
+
+	// MaxRequestBodyBytes caps the body of add webhook requests, which are
+	// rejected with a 413 beyond it. Gzip encoded bodies are capped both before
+	// and after decompression. A negative value disables the cap.
+	// (Optional). Defaults to 1 MiB.
+	MaxRequestBodyBytes int64
+
+	// StrictDecoding, if true, rejects added webhooks with a 400 naming the
+	// first field, top level or nested, that isn't part of a registration,
+	// instead of ignoring it. This catches typos such as "evnts" that would
+	// otherwise register a webhook missing the intended value.
+	// (Optional). Defaults to false.
+	StrictDecoding bool
+
+	// LegacyResponse, if true, makes the add handler respond with
+	// {"message": "Success"} and a 200 as it used to, instead of the stored
+	// webhook, with its defaults filled in and its secrets obfuscated, and a
+	// 201 when it was created.
+	// (Optional). Defaults to false.
+	LegacyResponse bool
+
+	// ErrorFormat is the format of the bodies of error responses, e.g.
+	// ProblemJSON for RFC 7807 problem details.
+	// (Optional). Defaults to MessageJSON.
+	ErrorFormat ErrorFormat
+}
+
+func (c HandlerConfig) maxStaleness() time.Duration {
+	if c.MaxStaleness <= 0 {
+		return defaultMaxStaleness
+	}
+	return c.MaxStaleness
+}
+
+// maxRequestBodyBytes returns the cap of add webhook request bodies, 0 when
+// there is none.
+func (c HandlerConfig) maxRequestBodyBytes() int64 {
+	switch {
+	case c.MaxRequestBodyBytes < 0:
+		return 0
+	case c.MaxRequestBodyBytes == 0:
+		return defaultMaxRequestBodyBytes
+	}
+	return c.MaxRequestBodyBytes
+}
+
+func (c HandlerConfig) defaultPageLimit() int {
+	if c.DefaultPageLimit <= 0 {
+		return defaultPageLimit
+	}
+	return c.DefaultPageLimit
+}
+
+// errorEncoder returns the encoder of error responses in c.ErrorFormat.
+func (c HandlerConfig) errorEncoder() kithttp.ErrorEncoder {
+	return formattedErrorEncoder(c.GetLogger, c.ErrorFormat)
+}
+
+func (c HandlerConfig) getAllEncoder() kithttp.EncodeResponseFunc {
+	if c.StoreOrder {
+		return encodeGetAllWebhooksResponseInStoreOrder
+	}
+	return encodeGetAllWebhooksResponse
+}
+
+func (c HandlerConfig) getAllServerOptions() []kithttp.ServerOption {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(captureIfNoneMatch),
+		kithttp.ServerErrorEncoder(c.errorEncoder()),
+	}
+	if !c.DisableCompression {
+		opts = append(opts, kithttp.ServerBefore(captureAcceptEncoding))
+	}
+	return opts
 }
 
 func newTransportConfig(hConfig HandlerConfig) transportConfig {
+	var rejectedURLs *rejectionCache
+	if hConfig.CacheRejectedURLs {
+		rejectedURLs = newRejectionCache(hConfig.RejectedURLCacheTTL, time.Now)
+	}
 	return transportConfig{
-		now:               time.Now,
-		v:                 hConfig.V,
-		disablePartnerIDs: hConfig.DisablePartnerIDs,
+		now:                 time.Now,
+		v:                   hConfig.V,
+		defaults:            hConfig.DefaultsPolicy,
+		validationTimeout:   hConfig.ValidationTimeout,
+		acceptEpochUntil:    hConfig.AcceptEpochUntil,
+		requireContentType:  hConfig.RequireContentType,
+		lowercasePartnerIDs: hConfig.LowercasePartnerIDs,
+		getLogger:           hConfig.GetLogger,
+		disablePartnerIDs:   hConfig.DisablePartnerIDs,
+		breakGlass:          hConfig.BreakGlass,
+		breakGlassUses:      hConfig.BreakGlassUses,
+		rejectedURLs:        rejectedURLs,
+		addRequestBytes:     hConfig.AddRequestBytes,
+		disableCompression:  hConfig.DisableCompression,
+		maxBodyBytes:        hConfig.maxRequestBodyBytes(),
+		strictDecoding:      hConfig.StrictDecoding,
 	}
 }
@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidHandlerConfig is returned by NewHandlerConfig when its options
+// can't be used together.
+var ErrInvalidHandlerConfig = errors.New("invalid handler config")
+
+// HandlerOption sets a field of the HandlerConfig built by NewHandlerConfig.
+type HandlerOption func(*HandlerConfig)
+
+// NewHandlerConfig builds a HandlerConfig from the options, which are applied
+// in order. Unlike a HandlerConfig literal, it defaults GetLogger to a no op
+// logger and V to AlwaysValid, warning that added webhooks won't be validated,
+// and fails with ErrInvalidHandlerConfig for negative timeouts or limits and
+// for options that only matter along with one that is missing.
+func NewHandlerConfig(opts ...HandlerOption) (HandlerConfig, error) {
+	var c HandlerConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+
+	if c.GetLogger == nil {
+		c.GetLogger = func(context.Context) *zap.Logger { return zap.NewNop() }
+	}
+	if c.V == nil {
+		c.GetLogger(context.Background()).Warn("No validator was configured, added webhooks won't be validated.")
+		c.V = AlwaysValid()
+	}
+
+	if err := c.validate(); err != nil {
+		return HandlerConfig{}, err
+	}
+	return c, nil
+}
+
+func (c HandlerConfig) validate() error {
+	var errs []error
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"ValidationTimeout", c.ValidationTimeout},
+		{"StoreTimeout", c.StoreTimeout},
+		{"RejectedURLCacheTTL", c.RejectedURLCacheTTL},
+		{"MaxStaleness", c.MaxStaleness},
+	} {
+		if d.value < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s can't be negative", ErrInvalidHandlerConfig, d.name))
+		}
+	}
+	if c.DefaultPageLimit < 0 {
+		errs = append(errs, fmt.Errorf("%w: DefaultPageLimit can't be negative", ErrInvalidHandlerConfig))
+	}
+	if c.RejectedURLCacheTTL != 0 && !c.CacheRejectedURLs {
+		errs = append(errs, fmt.Errorf("%w: RejectedURLCacheTTL requires CacheRejectedURLs", ErrInvalidHandlerConfig))
+	}
+	if c.MaxStaleness != 0 && c.StaleSnapshot == nil {
+		errs = append(errs, fmt.Errorf("%w: MaxStaleness requires a StaleSnapshot", ErrInvalidHandlerConfig))
+	}
+	if c.BreakGlassUses != nil && c.BreakGlass == nil {
+		errs = append(errs, fmt.Errorf("%w: BreakGlassUses requires a BreakGlass predicate", ErrInvalidHandlerConfig))
+	}
+	return errors.Join(errs...)
+}
+
+// WithValidator sets HandlerConfig.V.
+func WithValidator(v Validator) HandlerOption {
+	return func(c *HandlerConfig) { c.V = v }
+}
+
+// WithDisablePartnerIDs sets HandlerConfig.DisablePartnerIDs.
+func WithDisablePartnerIDs(disable bool) HandlerOption {
+	return func(c *HandlerConfig) { c.DisablePartnerIDs = disable }
+}
+
+// WithGetLogger sets HandlerConfig.GetLogger.
+func WithGetLogger(getLogger func(context.Context) *zap.Logger) HandlerOption {
+	return func(c *HandlerConfig) { c.GetLogger = getLogger }
+}
+
+// WithDefaultsPolicy sets HandlerConfig.DefaultsPolicy.
+func WithDefaultsPolicy(p DefaultsPolicy) HandlerOption {
+	return func(c *HandlerConfig) { c.DefaultsPolicy = p }
+}
+
+// WithValidationTimeout sets HandlerConfig.ValidationTimeout.
+func WithValidationTimeout(d time.Duration) HandlerOption {
+	return func(c *HandlerConfig) { c.ValidationTimeout = d }
+}
+
+// WithStoreTimeout sets HandlerConfig.StoreTimeout.
+func WithStoreTimeout(d time.Duration) HandlerOption {
+	return func(c *HandlerConfig) { c.StoreTimeout = d }
+}
+
+// WithAcceptEpochUntil sets HandlerConfig.AcceptEpochUntil.
+func WithAcceptEpochUntil(accept bool) HandlerOption {
+	return func(c *HandlerConfig) { c.AcceptEpochUntil = accept }
+}
+
+// WithRequireContentType sets HandlerConfig.RequireContentType.
+func WithRequireContentType(require bool) HandlerOption {
+	return func(c *HandlerConfig) { c.RequireContentType = require }
+}
+
+// WithLowercasePartnerIDs sets HandlerConfig.LowercasePartnerIDs.
+func WithLowercasePartnerIDs(lowercase bool) HandlerOption {
+	return func(c *HandlerConfig) { c.LowercasePartnerIDs = lowercase }
+}
+
+// WithRejectedURLCache sets HandlerConfig.CacheRejectedURLs, caching rejected
+// receiver URLs for the ttl, or the default when it's 0.
+func WithRejectedURLCache(ttl time.Duration) HandlerOption {
+	return func(c *HandlerConfig) {
+		c.CacheRejectedURLs = true
+		c.RejectedURLCacheTTL = ttl
+	}
+}
+
+// WithStaleSnapshot sets HandlerConfig.StaleSnapshot and its MaxStaleness, or
+// the default when maxStaleness is 0.
+func WithStaleSnapshot(s *Snapshot, maxStaleness time.Duration) HandlerOption {
+	return func(c *HandlerConfig) {
+		c.StaleSnapshot = s
+		c.MaxStaleness = maxStaleness
+	}
+}
+
+// WithGetAllByOwner sets HandlerConfig.GetAllByOwner.
+func WithGetAllByOwner(byOwner bool) HandlerOption {
+	return func(c *HandlerConfig) { c.GetAllByOwner = byOwner }
+}
+
+// WithStoreOrder sets HandlerConfig.StoreOrder.
+func WithStoreOrder(storeOrder bool) HandlerOption {
+	return func(c *HandlerConfig) { c.StoreOrder = storeOrder }
+}
+
+// WithIsAdmin sets HandlerConfig.IsAdmin.
+func WithIsAdmin(isAdmin func(*http.Request) bool) HandlerOption {
+	return func(c *HandlerConfig) { c.IsAdmin = isAdmin }
+}
+
+// WithDefaultPageLimit sets HandlerConfig.DefaultPageLimit.
+func WithDefaultPageLimit(limit int) HandlerOption {
+	return func(c *HandlerConfig) { c.DefaultPageLimit = limit }
+}
+
+// WithDisableCompression sets HandlerConfig.DisableCompression.
+func WithDisableCompression(disable bool) HandlerOption {
+	return func(c *HandlerConfig) { c.DisableCompression = disable }
+}
+
+// WithAddRequestBytes sets HandlerConfig.AddRequestBytes.
+func WithAddRequestBytes(o prometheus.ObserverVec) HandlerOption {
+	return func(c *HandlerConfig) { c.AddRequestBytes = o }
+}
+
+// WithMaxRequestBodyBytes sets HandlerConfig.MaxRequestBodyBytes.
+func WithMaxRequestBodyBytes(n int64) HandlerOption {
+	return func(c *HandlerConfig) { c.MaxRequestBodyBytes = n }
+}
+
+// WithStrictDecoding sets HandlerConfig.StrictDecoding.
+func WithStrictDecoding(strict bool) HandlerOption {
+	return func(c *HandlerConfig) { c.StrictDecoding = strict }
+}
+
+// WithLegacyResponse sets HandlerConfig.LegacyResponse.
+func WithLegacyResponse(legacy bool) HandlerOption {
+	return func(c *HandlerConfig) { c.LegacyResponse = legacy }
+}
+
+// WithErrorFormat sets HandlerConfig.ErrorFormat.
+func WithErrorFormat(format ErrorFormat) HandlerOption {
+	return func(c *HandlerConfig) { c.ErrorFormat = format }
+}
+
+// WithBreakGlass sets HandlerConfig.BreakGlass and the BreakGlassUses counter,
+// which may be nil.
+func WithBreakGlass(breakGlass func(*http.Request) bool, uses prometheus.Counter) HandlerOption {
+	return func(c *HandlerConfig) {
+		c.BreakGlass = breakGlass
+		c.BreakGlassUses = uses
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewHandlerConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	c, err := NewHandlerConfig()
+	require.NoError(err)
+	require.NotNil(c.GetLogger)
+	assert.NotNil(c.GetLogger(context.Background()))
+	require.NotNil(c.V)
+	assert.NoError(c.V.Validate(Webhook{}))
+
+	core, logs := observer.New(zap.WarnLevel)
+	getLogger := func(context.Context) *zap.Logger { return zap.New(core) }
+	_, err = NewHandlerConfig(WithGetLogger(getLogger), nil)
+	require.NoError(err)
+	assert.Equal(1, logs.FilterMessageSnippet("No validator").Len())
+
+	_, err = NewHandlerConfig(WithGetLogger(getLogger), WithValidator(AlwaysValid()))
+	require.NoError(err)
+	assert.Equal(1, logs.Len())
+}
+
+func TestNewHandlerConfigOptions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	snapshot := &Snapshot{}
+	uses := prometheus.NewCounter(prometheus.CounterOpts{Name: "uses"})
+	c, err := NewHandlerConfig(
+		WithDisablePartnerIDs(true),
+		WithValidationTimeout(time.Second),
+		WithStoreTimeout(2*time.Second),
+		WithAcceptEpochUntil(true),
+		WithRequireContentType(true),
+		WithLowercasePartnerIDs(true),
+		WithRejectedURLCache(time.Minute),
+		WithStaleSnapshot(snapshot, time.Hour),
+		WithGetAllByOwner(true),
+		WithStoreOrder(true),
+		WithIsAdmin(func(*http.Request) bool { return true }),
+		WithDefaultPageLimit(10),
+		WithDisableCompression(true),
+		WithBreakGlass(func(*http.Request) bool { return true }, uses),
+	)
+	require.NoError(err)
+	assert.True(c.DisablePartnerIDs)
+	assert.Equal(time.Second, c.ValidationTimeout)
+	assert.Equal(2*time.Second, c.StoreTimeout)
+	assert.True(c.AcceptEpochUntil)
+	assert.True(c.RequireContentType)
+	assert.True(c.LowercasePartnerIDs)
+	assert.True(c.CacheRejectedURLs)
+	assert.Equal(time.Minute, c.RejectedURLCacheTTL)
+	assert.Same(snapshot, c.StaleSnapshot)
+	assert.Equal(time.Hour, c.MaxStaleness)
+	assert.True(c.GetAllByOwner)
+	assert.True(c.StoreOrder)
+	assert.NotNil(c.IsAdmin)
+	assert.Equal(10, c.DefaultPageLimit)
+	assert.True(c.DisableCompression)
+	assert.NotNil(c.BreakGlass)
+	assert.Equal(uses, c.BreakGlassUses)
+}
+
+func TestNewHandlerConfigValidation(t *testing.T) {
+	tcs := []struct {
+		desc     string
+		opt      HandlerOption
+		expected string
+	}{
+		{
+			desc:     "Negative validation timeout",
+			opt:      WithValidationTimeout(-time.Second),
+			expected: "ValidationTimeout can't be negative",
+		},
+		{
+			desc:     "Negative store timeout",
+			opt:      WithStoreTimeout(-time.Second),
+			expected: "StoreTimeout can't be negative",
+		},
+		{
+			desc:     "Negative rejected URL cache TTL",
+			opt:      WithRejectedURLCache(-time.Second),
+			expected: "RejectedURLCacheTTL can't be negative",
+		},
+		{
+			desc:     "Negative max staleness",
+			opt:      WithStaleSnapshot(&Snapshot{}, -time.Second),
+			expected: "MaxStaleness can't be negative",
+		},
+		{
+			desc:     "Negative default page limit",
+			opt:      WithDefaultPageLimit(-1),
+			expected: "DefaultPageLimit can't be negative",
+		},
+		{
+			desc:     "Rejected URL cache TTL without the cache",
+			opt:      func(c *HandlerConfig) { c.RejectedURLCacheTTL = time.Minute },
+			expected: "RejectedURLCacheTTL requires CacheRejectedURLs",
+		},
+		{
+			desc:     "Max staleness without a snapshot",
+			opt:      WithStaleSnapshot(nil, time.Minute),
+			expected: "MaxStaleness requires a StaleSnapshot",
+		},
+		{
+			desc:     "Break glass uses without a predicate",
+			opt:      WithBreakGlass(nil, prometheus.NewCounter(prometheus.CounterOpts{Name: "uses"})),
+			expected: "BreakGlassUses requires a BreakGlass predicate",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			c, err := NewHandlerConfig(tc.opt)
+			assert.ErrorIs(err, ErrInvalidHandlerConfig)
+			assert.ErrorContains(err, tc.expected)
+			assert.Equal(HandlerConfig{}, c)
+		})
+	}
+}
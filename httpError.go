@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import "net/http"
+
+// HTTPError is an error with the HTTP status the handlers respond with when it
+// is returned, e.g. by a custom Service or Validator, or anywhere else in the
+// error chain. It is the supported way to influence the response status and
+// message.
+//
+// The response's JSON body carries SanitizedError as its message, so Err can
+// hold details that shouldn't reach clients; they are still logged.
+type HTTPError struct {
+	// Code is the status code of the response.
+	// (Optional). Defaults to 500.
+	Code int
+
+	// Message is the message of the response.
+	// (Optional). Defaults to the status text of Code.
+	Message string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message followed by the underlying error.
+func (e *HTTPError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.SanitizedError()
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the status code of the response, i.e. kithttp.StatusCoder.
+func (e *HTTPError) StatusCode() int {
+	if e.Code == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Code
+}
+
+// SanitizedError returns the message safe to send to clients.
+func (e *HTTPError) SanitizedError() string {
+	if e.Message == "" {
+		return http.StatusText(e.StatusCode())
+	}
+	return e.Message
+}
+
+// SanitizedError is implemented by errors with a message safe to send to
+// clients, which error responses carry instead of the error's own message.
+// Errors anywhere in the chain are found. Without one, 5xx responses carry
+// a generic message, while 4xx responses carry the error's message.
+type SanitizedError interface {
+	SanitizedError() string
+}
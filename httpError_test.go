@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError(t *testing.T) {
+	errCause := errors.New("connection refused by 10.0.0.7")
+	tcs := []struct {
+		desc              string
+		err               error
+		expectedError     string
+		expectedCode      int
+		expectedSanitized string
+	}{
+		{
+			desc:              "All fields",
+			err:               &HTTPError{Code: http.StatusConflict, Message: "webhook already exists", Err: errCause},
+			expectedError:     "webhook already exists: connection refused by 10.0.0.7",
+			expectedCode:      http.StatusConflict,
+			expectedSanitized: "webhook already exists",
+		},
+		{
+			desc:              "No message",
+			err:               &HTTPError{Code: http.StatusServiceUnavailable, Err: errCause},
+			expectedError:     "connection refused by 10.0.0.7",
+			expectedCode:      http.StatusServiceUnavailable,
+			expectedSanitized: "Service Unavailable",
+		},
+		{
+			desc:              "No error",
+			err:               &HTTPError{Code: http.StatusTooManyRequests},
+			expectedError:     "Too Many Requests",
+			expectedCode:      http.StatusTooManyRequests,
+			expectedSanitized: "Too Many Requests",
+		},
+		{
+			desc:              "No code",
+			err:               &HTTPError{Err: errCause},
+			expectedError:     "connection refused by 10.0.0.7",
+			expectedCode:      http.StatusInternalServerError,
+			expectedSanitized: "Internal Server Error",
+		},
+		{
+			desc:              "Wrapped",
+			err:               fmt.Errorf("storing webhook: %w", &HTTPError{Code: http.StatusBadGateway, Message: "store failed", Err: errCause}),
+			expectedError:     "storing webhook: store failed: connection refused by 10.0.0.7",
+			expectedCode:      http.StatusBadGateway,
+			expectedSanitized: "store failed",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(tc.expectedError, tc.err.Error())
+			var he *HTTPError
+			if assert.ErrorAs(tc.err, &he) {
+				assert.Equal(tc.expectedCode, he.StatusCode())
+				assert.Equal(tc.expectedSanitized, he.SanitizedError())
+				assert.Equal(he.Err, errors.Unwrap(he))
+			}
+
+			recorder := httptest.NewRecorder()
+			errorEncoder(nil)(context.Background(), tc.err, recorder)
+			assert.Equal(tc.expectedCode, recorder.Code)
+			assert.JSONEq(fmt.Sprintf(`{"message": %q}`, tc.expectedSanitized), recorder.Body.String())
+		})
+	}
+}
@@ -4,9 +4,10 @@
 package ancla
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"math"
 	"time"
 
@@ -15,10 +16,29 @@ import (
 )
 
 type InternalWebhook struct {
-	PartnerIDs []string
+	PartnerIDs []string `json:"partner_ids"`
 	Webhook    Webhook
 }
 
+// UnmarshalJSON decodes the webhook, also accepting the partner IDs under the
+// keys used before partner_ids, i.e. "PartnerIDs" and its other casings such
+// as "partnerids". Migrate rewrites such items with the partner_ids key.
+func (iw *InternalWebhook) UnmarshalJSON(b []byte) error {
+	type internalWebhook InternalWebhook
+	var decoded struct {
+		internalWebhook
+		LegacyPartnerIDs []string `json:"PartnerIDs"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	*iw = InternalWebhook(decoded.internalWebhook)
+	if iw.PartnerIDs == nil {
+		iw.PartnerIDs = decoded.LegacyPartnerIDs
+	}
+	return nil
+}
+
 func InternalWebhookToItem(now func() time.Time, iw InternalWebhook) (model.Item, error) {
 	encodedWebhook, err := json.Marshal(iw)
 	if err != nil {
@@ -34,15 +54,37 @@ func InternalWebhookToItem(now func() time.Time, iw InternalWebhook) (model.Item
 	SecondsToExpiry := iw.Webhook.Until.Sub(now()).Seconds()
 	TTLSeconds := int64(math.Max(0, SecondsToExpiry))
 
-	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(iw.Webhook.Config.URL)))
-
 	return model.Item{
 		Data: data,
-		ID:   checksum,
+		ID:   SHA256IDHasher(iw.Webhook.Config.URL),
 		TTL:  &TTLSeconds,
 	}, nil
 }
 
+// IDHasher derives the item ID of a webhook from its URL.
+//
+// Changing the IDHasher of a deployment doesn't affect reads, since items are
+// never looked up by recomputing their IDs. Webhooks stored with the previous
+// IDHasher keep their IDs though, so re-adding one stores a second item under
+// the new ID and the old item lingers until it expires.
+type IDHasher func(url string) string
+
+// SHA256IDHasher is the default IDHasher, the hex encoded SHA-256 of the URL.
+func SHA256IDHasher(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// HMACIDHasher returns an IDHasher deriving IDs with HMAC-SHA256 keyed with
+// the given secret, so IDs can't be precomputed by anyone without the secret.
+func HMACIDHasher(secret []byte) IDHasher {
+	return func(url string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(url))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
 func ItemToInternalWebhook(i model.Item) (InternalWebhook, error) {
 	encodedWebhook, err := json.Marshal(i.Data)
 	if err != nil {
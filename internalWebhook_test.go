@@ -4,10 +4,14 @@
 package ancla
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/ancla/model"
 )
 
@@ -85,6 +89,66 @@ func TestInternalWebhookToItem(t *testing.T) {
 	}
 }
 
+func TestInternalWebhookPartnerIDsJSON(t *testing.T) {
+	tcs := []struct {
+		Description string
+		Input       string
+		Expected    []string
+	}{
+		{
+			Description: "Canonical key",
+			Input:       `{"partner_ids": ["comcast"]}`,
+			Expected:    []string{"comcast"},
+		},
+		{
+			Description: "Legacy key",
+			Input:       `{"PartnerIDs": ["comcast"]}`,
+			Expected:    []string{"comcast"},
+		},
+		{
+			Description: "Legacy lowercase key",
+			Input:       `{"partnerids": ["comcast"]}`,
+			Expected:    []string{"comcast"},
+		},
+		{
+			Description: "Legacy mixed case key",
+			Input:       `{"PartnerIds": ["comcast"]}`,
+			Expected:    []string{"comcast"},
+		},
+		{
+			Description: "Canonical key wins",
+			Input:       `{"partner_ids": ["comcast"], "PartnerIDs": ["legacy"]}`,
+			Expected:    []string{"comcast"},
+		},
+		{
+			Description: "No partner IDs",
+			Input:       `{}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var iw InternalWebhook
+			require.NoError(json.Unmarshal([]byte(tc.Input), &iw))
+			assert.Equal(tc.Expected, iw.PartnerIDs)
+
+			// round trip always emits the canonical key.
+			b, err := json.Marshal(iw)
+			require.NoError(err)
+			var raw map[string]interface{}
+			require.NoError(json.Unmarshal(b, &raw))
+			assert.Contains(raw, "partner_ids")
+			assert.NotContains(raw, "PartnerIDs")
+
+			var decoded InternalWebhook
+			require.NoError(json.Unmarshal(b, &decoded))
+			assert.Equal(iw, decoded)
+		})
+	}
+}
+
 func getExpiredItem() model.Item {
 	var expiresInSecs int64 = 0
 	return model.Item{
@@ -105,7 +169,7 @@ func getExpiredItem() model.Item {
 				"duration":    float64(time.Second.Nanoseconds()),
 				"until":       "1970-01-01T00:00:01Z",
 			},
-			"PartnerIDs": []interface{}{},
+			"partner_ids": []interface{}{},
 		},
 		TTL: &expiresInSecs,
 	}
@@ -184,3 +248,16 @@ func getRefTime() time.Time {
 	}
 	return refTime
 }
+
+func TestIDHashers(t *testing.T) {
+	assert := assert.New(t)
+	url := "http://deliver-here-0.example.net"
+	assert.Equal(fmt.Sprintf("%x", sha256.Sum256([]byte(url))), SHA256IDHasher(url))
+	assert.Equal("b3bbc3467366959e0aba3c33588a08c599f68a740fabf4aa348463d3dc7dcfe8", SHA256IDHasher(url))
+
+	hasher := HMACIDHasher([]byte("secret"))
+	assert.Equal(hasher(url), HMACIDHasher([]byte("secret"))(url))
+	assert.NotEqual(hasher(url), HMACIDHasher([]byte("other secret"))(url))
+	assert.NotEqual(hasher(url), SHA256IDHasher(url))
+	assert.Len(hasher(url), 64)
+}
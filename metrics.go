@@ -13,15 +13,40 @@ import (
 
 // Names
 const (
-	WebhookListSizeGaugeName     = "webhook_list_size"
-	WebhookListSizeGaugeHelp     = "Size of the current list of webhooks."
-	ChrysomPollsTotalCounterName = chrysom.PollCounter
-	ChrysomPollsTotalCounterHelp = "Counter for the number of polls (and their success/failure outcomes) to fetch new items."
+	WebhookListSizeGaugeName        = "webhook_list_size"
+	WebhookListSizeGaugeHelp        = "Size of the current list of webhooks."
+	ChrysomPollsTotalCounterName    = chrysom.PollCounter
+	ChrysomPollsTotalCounterHelp    = "Counter for the number of polls (and their success/failure outcomes) to fetch new items."
+	RegistrationsAddedCounterName   = "ancla_registrations_added_total"
+	RegistrationsAddedCounterHelp   = "Counter for the number of webhook registrations that appeared between polls."
+	RegistrationsRemovedCounterName = "ancla_registrations_removed_total"
+	RegistrationsRemovedCounterHelp = "Counter for the number of webhook registrations that disappeared between polls."
+	RegistrationsUpdatedCounterName = "ancla_registrations_updated_total"
+	RegistrationsUpdatedCounterHelp = "Counter for the number of webhook registrations that changed between polls, including renewals."
+	WatcherTimeoutsCounterName      = "ancla_watcher_timeouts_total"
+	WatcherTimeoutsCounterHelp      = "Counter for the number of watch updates that ran past ListenerConfig.WatchTimeout."
+	AddRequestBytesHistogramName    = "ancla_add_request_bytes"
+	AddRequestBytesHistogramHelp    = "Histogram of the body sizes of add webhook requests."
+	BreakGlassCounterName           = "ancla_break_glass_total"
+	BreakGlassCounterHelp           = "Counter for the number of add webhook requests that skipped the host lookups of URL validation with the break glass header."
+	ExpiryMismatchesCounterName     = "ancla_expiry_mismatches_total"
+	ExpiryMismatchesCounterHelp     = "Counter for the number of read items whose TTL and webhook until disagree, by which of the two is later."
+	ChrysomPollDurationName         = chrysom.PollDurationHistogram
+	ChrysomPollDurationHelp         = "Histogram of the durations of polls, delivery to the listeners included, by outcome."
+	ChrysomLastSuccessfulPollName   = chrysom.LastSuccessfulPollGauge
+	ChrysomLastSuccessfulPollHelp   = "Unix time in seconds of the latest successful poll."
+	ChrysomItemsFetchedName         = chrysom.ItemsFetchedGauge
+	ChrysomItemsFetchedHelp         = "Number of items fetched by the latest successful poll."
+	ChrysomLastPollStatusName       = chrysom.LastPollStatusGauge
+	ChrysomLastPollStatusHelp       = "Kind of the result of the latest poll, set to 1 for its kind and 0 for the others."
 )
 
 // Labels
 const (
 	OutcomeLabel = "outcome"
+	VersionLabel = "version"
+	// DirectionLabel is TTLLaterDirection or UntilLaterDirection.
+	DirectionLabel = "direction"
 )
 
 // Outcomes
@@ -30,10 +55,27 @@ const (
 	FailureOutcome = "failure"
 )
 
+// Versions of add webhook request bodies.
+const (
+	V1Version      = "v1"
+	InvalidVersion = "invalid"
+)
+
 // Measures describes the defined metrics that will be used by clients.
 type Measures struct {
 	WebhookListSizeGaugeName     prometheus.Gauge       `name:"webhook_list_size"`
 	ChrysomPollsTotalCounterName *prometheus.CounterVec `name:"chrysom_polls_total"`
+	RegistrationsAdded           prometheus.Counter     `name:"ancla_registrations_added_total"`
+	RegistrationsRemoved         prometheus.Counter     `name:"ancla_registrations_removed_total"`
+	RegistrationsUpdated         prometheus.Counter     `name:"ancla_registrations_updated_total"`
+	WatcherTimeouts              prometheus.Counter     `name:"ancla_watcher_timeouts_total"`
+	AddRequestBytes              prometheus.ObserverVec `name:"ancla_add_request_bytes"`
+	BreakGlassUses               prometheus.Counter     `name:"ancla_break_glass_total"`
+	ExpiryMismatches             *prometheus.CounterVec `name:"ancla_expiry_mismatches_total"`
+	ChrysomPollDuration          prometheus.ObserverVec `name:"chrysom_poll_duration_seconds"`
+	ChrysomLastSuccessfulPoll    prometheus.Gauge       `name:"chrysom_last_successful_poll_timestamp"`
+	ChrysomItemsFetched          prometheus.Gauge       `name:"chrysom_items_fetched"`
+	ChrysomLastPollStatus        *prometheus.GaugeVec   `name:"ancla_chrysom_last_poll_status"`
 }
 
 type MeasuresOut struct {
@@ -67,11 +109,105 @@ func NewMeasures(in MeasuresIn) (MeasuresOut, error) {
 		OutcomeLabel,
 	)
 	err = multierr.Append(err, err2)
+	ram, err3 := in.Factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: RegistrationsAddedCounterName,
+			Help: RegistrationsAddedCounterHelp,
+		},
+	)
+	err = multierr.Append(err, err3)
+	rrm, err4 := in.Factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: RegistrationsRemovedCounterName,
+			Help: RegistrationsRemovedCounterHelp,
+		},
+	)
+	err = multierr.Append(err, err4)
+	rum, err5 := in.Factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: RegistrationsUpdatedCounterName,
+			Help: RegistrationsUpdatedCounterHelp,
+		},
+	)
+	err = multierr.Append(err, err5)
+	wtm, err6 := in.Factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: WatcherTimeoutsCounterName,
+			Help: WatcherTimeoutsCounterHelp,
+		},
+	)
+	err = multierr.Append(err, err6)
+	arb, err7 := in.Factory.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    AddRequestBytesHistogramName,
+			Help:    AddRequestBytesHistogramHelp,
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		},
+		VersionLabel,
+	)
+	err = multierr.Append(err, err7)
+	bgm, err8 := in.Factory.NewCounter(
+		prometheus.CounterOpts{
+			Name: BreakGlassCounterName,
+			Help: BreakGlassCounterHelp,
+		},
+	)
+	err = multierr.Append(err, err8)
+	emm, err9 := in.Factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ExpiryMismatchesCounterName,
+			Help: ExpiryMismatchesCounterHelp,
+		},
+		DirectionLabel,
+	)
+	err = multierr.Append(err, err9)
+	cpd, err10 := in.Factory.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    ChrysomPollDurationName,
+			Help:    ChrysomPollDurationHelp,
+			Buckets: prometheus.DefBuckets,
+		},
+		OutcomeLabel,
+	)
+	err = multierr.Append(err, err10)
+	clp, err11 := in.Factory.NewGauge(
+		prometheus.GaugeOpts{
+			Name: ChrysomLastSuccessfulPollName,
+			Help: ChrysomLastSuccessfulPollHelp,
+		},
+	)
+	err = multierr.Append(err, err11)
+	cif, err12 := in.Factory.NewGauge(
+		prometheus.GaugeOpts{
+			Name: ChrysomItemsFetchedName,
+			Help: ChrysomItemsFetchedHelp,
+		},
+	)
+	err = multierr.Append(err, err12)
+	cps, err13 := in.Factory.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: ChrysomLastPollStatusName,
+			Help: ChrysomLastPollStatusHelp,
+		},
+		chrysom.KindLabel,
+	)
+	err = multierr.Append(err, err13)
 
 	return MeasuresOut{
 		M: &Measures{
 			WebhookListSizeGaugeName:     wlm,
 			ChrysomPollsTotalCounterName: cpm,
+			RegistrationsAdded:           ram,
+			RegistrationsRemoved:         rrm,
+			RegistrationsUpdated:         rum,
+			WatcherTimeouts:              wtm,
+			AddRequestBytes:              arb,
+			BreakGlassUses:               bgm,
+			ExpiryMismatches:             emm,
+			ChrysomPollDuration:          cpd,
+			ChrysomLastSuccessfulPoll:    clp,
+			ChrysomItemsFetched:          cif,
+			ChrysomLastPollStatus:        cps,
 		},
 	}, multierr.Append(err, metricErr)
 }
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/ancla/model"
+)
+
+const defaultMigrateConcurrency = 8
+
+// MigrateOptions configures Migrate.
+type MigrateOptions struct {
+	// Owner is the owner the items are read as. Each item is written back for
+	// its own owner.
+	// (Optional). Defaults to no owner, which reads every owner's items.
+	Owner string
+
+	// Transform rewrites an item. Its ID and owner are always kept.
+	// (Optional). Defaults to CanonicalizeItem.
+	Transform func(model.Item) (model.Item, error)
+
+	// DryRun, if true, reports the items that would change without writing them.
+	DryRun bool
+
+	// Concurrency is the maximum number of items written at a time.
+	// (Optional). Defaults to 8.
+	Concurrency int
+
+	// StartAfter skips the items whose IDs sort before or equal to it, so a run
+	// can be resumed from the LastID of a previous report.
+	// (Optional). Defaults to migrating all items.
+	StartAfter string
+}
+
+// MigrateReport lists the outcome of each item Migrate went through.
+type MigrateReport struct {
+	// Changed are the IDs of the items rewritten, or that would be in a dry run.
+	Changed []string
+
+	// Skipped are the IDs of the items already in their transformed form.
+	Skipped []string
+
+	// Failed are the errors of the items that couldn't be transformed or written.
+	Failed map[string]error
+
+	// LastID is the greatest ID up to which every item was migrated, for resuming
+	// with MigrateOptions.StartAfter.
+	LastID string
+}
+
+// CanonicalizeItem rewrites the item's data in the form InternalWebhookToItem
+// encodes webhooks today, e.g. dropping unknown fields and filling in the ones
+// older versions didn't store. The ID, TTL and owner are kept.
+func CanonicalizeItem(item model.Item) (model.Item, error) {
+	iw, err := ItemToInternalWebhook(item)
+	if err != nil {
+		return item, fmt.Errorf(errFmt, errFailedItemConversion, err)
+	}
+	encoded, err := json.Marshal(iw)
+	if err != nil {
+		return item, fmt.Errorf(errFmt, errFailedWebhookConversion, err)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return item, fmt.Errorf(errFmt, errFailedWebhookConversion, err)
+	}
+	item.Data = data
+	return item, nil
+}
+
+// Migrate rewrites the stored items with the configured transformation, pushing
+// back only the items whose data changed. Items are gone through in ID order.
+// Running it again is safe since items already migrated are skipped.
+// Once ctx is done no more writes are started and the remaining items fail with
+// the context's error. Item failures are only reported in the MigrateReport; an
+// error is returned when the items can't be fetched.
+func Migrate(ctx context.Context, client chrysom.PushReader, opts MigrateOptions) (MigrateReport, error) {
+	if opts.Transform == nil {
+		opts.Transform = CanonicalizeItem
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultMigrateConcurrency
+	}
+
+	items, err := client.GetItems(ctx, opts.Owner)
+	if err != nil {
+		return MigrateReport{}, fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+	items = items.Sorted()
+	start := sort.Search(len(items), func(i int) bool { return items[i].ID > opts.StartAfter })
+	items = items[start:]
+
+	changed := make([]bool, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(items); j++ {
+				errs[j] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item model.Item) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			changed[i], errs[i] = migrateItem(ctx, client, opts, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	report := MigrateReport{
+		Failed: make(map[string]error),
+		LastID: opts.StartAfter,
+	}
+	for i, item := range items {
+		switch {
+		case errs[i] != nil:
+			report.Failed[item.ID] = errs[i]
+		case changed[i]:
+			report.Changed = append(report.Changed, item.ID)
+		default:
+			report.Skipped = append(report.Skipped, item.ID)
+		}
+		if len(report.Failed) == 0 {
+			report.LastID = item.ID
+		}
+	}
+	return report, nil
+}
+
+// migrateItem transforms the item and pushes it back if its data changed.
+func migrateItem(ctx context.Context, client chrysom.Pusher, opts MigrateOptions, item model.Item) (bool, error) {
+	before, err := json.Marshal(item.Data)
+	if err != nil {
+		return false, err
+	}
+	migrated, err := opts.Transform(item)
+	if err != nil {
+		return false, err
+	}
+	migrated.ID, migrated.Owner = item.ID, item.Owner
+	after, err := json.Marshal(migrated.Data)
+	if err != nil {
+		return false, err
+	}
+	if string(before) == string(after) {
+		return false, nil
+	}
+	if opts.DryRun {
+		return true, nil
+	}
+
+	result, err := client.PushItem(ctx, item.Owner, migrated)
+	if err != nil {
+		return false, fmt.Errorf(errFmt, errFailedWebhookPush, err)
+	}
+	if result == chrysom.CreatedPushResult || result == chrysom.UpdatedPushResult {
+		return true, nil
+	}
+	return false, fmt.Errorf("%w: %s", errNonSuccessPushResult, result)
+}
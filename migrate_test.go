@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/ancla/model"
+)
+
+// migrateFixtures returns items in each layout found in stores, keyed by ID.
+func migrateFixtures(t *testing.T) map[string]model.Item {
+	canonical, err := InternalWebhookToItem(time.Now, InternalWebhook{
+		PartnerIDs: []string{"comcast"},
+		Webhook: Webhook{
+			Config: DeliveryConfig{URL: "https://example.com/canonical"},
+			Events: []string{"online"},
+			Until:  getRefTime(),
+		},
+	})
+	require.NoError(t, err)
+	canonical.ID = "a-canonical"
+
+	return map[string]model.Item{
+		canonical.ID: canonical,
+		"b-legacy-fields": {
+			ID: "b-legacy-fields",
+			Data: map[string]interface{}{
+				"PartnerIDs": []interface{}{"comcast"},
+				"Webhook": map[string]interface{}{
+					"config":                  map[string]interface{}{"url": "https://example.com/legacy", "content_type": "json"},
+					"events":                  []interface{}{"online"},
+					"registered_from_address": "example.com",
+					"failure_url":             "",
+					"matcher":                 map[string]interface{}{"device_id": nil},
+					"duration":                float64(0),
+					"until":                   "2021-01-02T15:04:10Z",
+					"legacy_field":            true,
+				},
+			},
+		},
+		"c-missing-fields": {
+			ID: "c-missing-fields",
+			Data: map[string]interface{}{
+				"Webhook": map[string]interface{}{
+					"config": map[string]interface{}{"url": "https://example.com/sparse"},
+				},
+			},
+		},
+		"d-undecodable": {
+			ID: "d-undecodable",
+			Data: map[string]interface{}{
+				"PartnerIDs": "comcast",
+			},
+		},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tcs := []struct {
+		desc            string
+		opts            MigrateOptions
+		expectedChanged []string
+		expectedSkipped []string
+		expectedFailed  []string
+		expectedLastID  string
+		expectWrites    bool
+	}{
+		{
+			desc:            "Canonicalize",
+			expectedChanged: []string{"b-legacy-fields", "c-missing-fields"},
+			expectedSkipped: []string{"a-canonical"},
+			expectedFailed:  []string{"d-undecodable"},
+			expectedLastID:  "c-missing-fields",
+			expectWrites:    true,
+		},
+		{
+			desc:            "Dry run",
+			opts:            MigrateOptions{DryRun: true, Concurrency: 1},
+			expectedChanged: []string{"b-legacy-fields", "c-missing-fields"},
+			expectedSkipped: []string{"a-canonical"},
+			expectedFailed:  []string{"d-undecodable"},
+			expectedLastID:  "c-missing-fields",
+		},
+		{
+			desc:            "Resumed",
+			opts:            MigrateOptions{StartAfter: "b-legacy-fields"},
+			expectedChanged: []string{"c-missing-fields"},
+			expectedFailed:  []string{"d-undecodable"},
+			expectedLastID:  "c-missing-fields",
+			expectWrites:    true,
+		},
+		{
+			desc: "Custom transform",
+			opts: MigrateOptions{
+				Transform: func(item model.Item) (model.Item, error) {
+					item.Data = map[string]interface{}{"migrated": true}
+					item.ID = "ignored"
+					return item, nil
+				},
+			},
+			expectedChanged: []string{"a-canonical", "b-legacy-fields", "c-missing-fields", "d-undecodable"},
+			expectedLastID:  "d-undecodable",
+			expectWrites:    true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			fixtures := migrateFixtures(t)
+			store := &memoryPushReader{items: migrateFixtures(t)}
+
+			report, err := Migrate(context.Background(), store, tc.opts)
+			require.NoError(err)
+			assert.Equal(tc.expectedChanged, report.Changed)
+			assert.Equal(tc.expectedSkipped, report.Skipped)
+			assert.Len(report.Failed, len(tc.expectedFailed))
+			for _, id := range tc.expectedFailed {
+				assert.Contains(report.Failed, id)
+			}
+			assert.Equal(tc.expectedLastID, report.LastID)
+
+			require.Len(store.items, len(fixtures))
+			for _, id := range tc.expectedChanged {
+				if tc.expectWrites {
+					assert.NotEqual(fixtures[id].Data, store.items[id].Data, id)
+					assert.Equal(fixtures[id].TTL, store.items[id].TTL, id)
+				} else {
+					assert.Equal(fixtures[id].Data, store.items[id].Data, id)
+				}
+			}
+
+			// migrating again finds nothing left to change.
+			if tc.expectWrites {
+				report, err = Migrate(context.Background(), store, tc.opts)
+				require.NoError(err)
+				assert.Empty(report.Changed)
+			}
+		})
+	}
+}
+
+func TestMigrateOwners(t *testing.T) {
+	fixtures := migrateFixtures(t)
+	alice := fixtures["b-legacy-fields"]
+	alice.Owner = "alice"
+	bob := fixtures["c-missing-fields"]
+	bob.Owner = "bob"
+	canonical := fixtures["a-canonical"]
+	canonical.Owner = "carol"
+
+	m := new(mockPushReader)
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "").Return(chrysom.Items{alice, bob, canonical}, nil)
+	for _, item := range []model.Item{alice, bob} {
+		// nolint:typecheck
+		m.On("PushItem", context.TODO(), item.Owner, mock.MatchedBy(func(pushed model.Item) bool {
+			return pushed.ID == item.ID && pushed.Owner == item.Owner
+		})).Return(chrysom.UpdatedPushResult, nil).Once()
+	}
+
+	report, err := Migrate(context.TODO(), m, MigrateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b-legacy-fields", "c-missing-fields"}, report.Changed)
+	assert.Empty(t, report.Failed)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestMigrateCanceled(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Migrate(ctx, &memoryPushReader{items: migrateFixtures(t)}, MigrateOptions{})
+	assert.NoError(err)
+	assert.Empty(report.Changed)
+	assert.Len(report.Failed, 4)
+	for _, err := range report.Failed {
+		assert.ErrorIs(err, context.Canceled)
+	}
+	assert.Empty(report.LastID)
+}
+
+func TestMigrateFetchFailure(t *testing.T) {
+	m := new(mockPushReader)
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "").Return(chrysom.Items(nil), errors.New("unreachable"))
+	_, err := Migrate(context.TODO(), m, MigrateOptions{})
+	assert.ErrorIs(t, err, errFailedWebhooksFetch)
+}
+
+func TestMigratePartnerIDsKey(t *testing.T) {
+	assert := assert.New(t)
+	store := &memoryPushReader{items: migrateFixtures(t)}
+	_, err := Migrate(context.Background(), store, MigrateOptions{})
+	require.NoError(t, err)
+
+	data := store.items["b-legacy-fields"].Data
+	assert.NotContains(data, "PartnerIDs")
+	assert.Equal([]interface{}{"comcast"}, data["partner_ids"])
+}
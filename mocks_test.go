@@ -69,6 +69,78 @@ func (m *mockService) GetAll(ctx context.Context) ([]InternalWebhook, error) {
 	return args.Get(0).([]InternalWebhook), args.Error(1)
 }
 
+func (m *mockService) GetAllByOwner(ctx context.Context, owner string) ([]InternalWebhook, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner)
+	return args.Get(0).([]InternalWebhook), args.Error(1)
+}
+
+func (m *mockService) Get(ctx context.Context, owner, id string) (InternalWebhook, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(InternalWebhook), args.Error(1)
+}
+
+type mockItemGetterPushReader struct {
+	mockPushReader
+}
+
+func (m *mockItemGetterPushReader) GetItem(ctx context.Context, owner, id string) (model.Item, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(model.Item), args.Error(1)
+}
+
+type mockOwnerGetterService struct {
+	mockService
+}
+
+func (m *mockOwnerGetterService) GetWithOwner(ctx context.Context, owner, id string) (InternalWebhook, string, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, id)
+	return args.Get(0).(InternalWebhook), args.String(1), args.Error(2)
+}
+
+type mockCreatorService struct {
+	mockService
+}
+
+func (m *mockCreatorService) Create(ctx context.Context, owner string, iw InternalWebhook) error {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, iw)
+	return args.Error(0)
+}
+
+type mockResultAdderService struct {
+	mockService
+}
+
+func (m *mockResultAdderService) AddWithResult(ctx context.Context, owner string, iw InternalWebhook) (chrysom.PushResult, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, iw)
+	return args.Get(0).(chrysom.PushResult), args.Error(1)
+}
+
+type mockItemListerService struct {
+	mockService
+}
+
+func (m *mockItemListerService) GetAllItems(ctx context.Context, owner string) (chrysom.Items, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner)
+	return args.Get(0).(chrysom.Items), args.Error(1)
+}
+
+type mockPagerService struct {
+	mockService
+}
+
+func (m *mockPagerService) GetPage(ctx context.Context, owner string, offset, limit int) ([]InternalWebhook, bool, error) {
+	// nolint:typecheck
+	args := m.Called(ctx, owner, offset, limit)
+	return args.Get(0).([]InternalWebhook), args.Bool(1), args.Error(2)
+}
+
 type mockCounter struct {
 	mock.Mock
 }
@@ -3,6 +3,8 @@
 
 package model
 
+import "time"
+
 // Key defines the field mapping to retrieve an item from storage.
 type Key struct {
 	// Bucket is the name for a collection or partition to which an item belongs.
@@ -26,4 +28,63 @@ type Item struct {
 	// TTL is the time to live in storage, specified in seconds.
 	// Optional. When not set, items don't expire.
 	TTL *int64 `json:"ttl,omitempty"`
+
+	// Owner is the owner of the item as reported by the store.
+	// Optional. Not all stores include it in their responses.
+	Owner string `json:"owner,omitempty"`
+
+	// FetchedAt is when the item was fetched from the store, which its TTL
+	// counts down from. It isn't stored.
+	// Optional. Zero for items that weren't fetched.
+	FetchedAt time.Time `json:"-"`
+}
+
+// DeepCopy returns a copy of the item that shares no memory with it. Data is
+// copied recursively through the maps and slices JSON decoding produces, and
+// []string values. Other reference values in Data are shared.
+func (i Item) DeepCopy() Item {
+	if i.Data != nil {
+		i.Data = copyMap(i.Data)
+	}
+	if i.TTL != nil {
+		ttl := *i.TTL
+		i.TTL = &ttl
+	}
+	return i
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = copyValue(v)
+	}
+	return c
+}
+
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		return copyMap(v)
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		c := make([]interface{}, len(v))
+		for i, e := range v {
+			c[i] = copyValue(e)
+		}
+		return c
+	case []string:
+		if v == nil {
+			return v
+		}
+		c := make([]string, len(v))
+		copy(c, v)
+		return c
+	default:
+		return v
+	}
 }
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NormalizePartnerIDs trims the whitespace around the partner IDs, drops the
+// empty ones and removes duplicates, keeping the first occurrence. When lowercase
+// is true, the IDs are also lowercased. Partner IDs should be normalized the same
+// way wherever they are compared, so the ones stored with webhooks match.
+func NormalizePartnerIDs(ids []string, lowercase bool) []string {
+	normalized := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if lowercase {
+			id = strings.ToLower(id)
+		}
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		normalized = append(normalized, id)
+	}
+	return normalized
+}
+
+// headerPartnerIDs returns the partner IDs listed in the request's header of
+// the given name, comma separated and possibly repeated, with the whitespace
+// around them trimmed and the empty ones dropped. It returns false when no
+// partner ID is listed.
+func headerPartnerIDs(r *http.Request, name string) ([]string, bool) {
+	var ids []string
+	for _, v := range r.Header.Values(name) {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, len(ids) > 0
+}
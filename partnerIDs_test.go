@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePartnerIDs(t *testing.T) {
+	tcs := []struct {
+		desc      string
+		ids       []string
+		lowercase bool
+		expected  []string
+	}{
+		{
+			desc:     "Nil",
+			expected: []string{},
+		},
+		{
+			desc:     "Unchanged",
+			ids:      []string{"comcast", "sky"},
+			expected: []string{"comcast", "sky"},
+		},
+		{
+			desc:     "Whitespace trimmed",
+			ids:      []string{" comcast", "sky\t"},
+			expected: []string{"comcast", "sky"},
+		},
+		{
+			desc:     "Empties dropped",
+			ids:      []string{"", "comcast", "  "},
+			expected: []string{"comcast"},
+		},
+		{
+			desc:     "Duplicates removed in order",
+			ids:      []string{"sky", "comcast", "", "sky", " comcast "},
+			expected: []string{"sky", "comcast"},
+		},
+		{
+			desc:     "Case kept",
+			ids:      []string{"Comcast", "comcast"},
+			expected: []string{"Comcast", "comcast"},
+		},
+		{
+			desc:      "Lowercased",
+			ids:       []string{"Comcast", "comcast", "SKY"},
+			lowercase: true,
+			expected:  []string{"comcast", "sky"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizePartnerIDs(tc.ids, tc.lowercase))
+		})
+	}
+}
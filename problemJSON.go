@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"net/http"
+)
+
+const problemJSONContentType = "application/problem+json"
+
+// errWebhookValidation is matched by the errors of added webhooks failing
+// validation.
+var errWebhookValidation = errors.New("failed webhook validation")
+
+// ErrorFormat is the format of the bodies of the handlers' error responses.
+type ErrorFormat int
+
+const (
+	// MessageJSON error responses are application/json bodies such as
+	// {"message": "..."}, with the failures of webhook validation listed in
+	// "details".
+	MessageJSON ErrorFormat = iota
+
+	// ProblemJSON error responses are RFC 7807 application/problem+json bodies
+	// with a type, title, status and detail, and the failures of webhook
+	// validation listed in "details".
+	ProblemJSON
+)
+
+// Types of ProblemJSON error responses. Errors without a known type use
+// "about:blank", with the status text as their title.
+const (
+	UnmarshalFailureProblem       = "urn:xmidt:ancla:problem:unmarshal-failure"
+	ValidationFailureProblem      = "urn:xmidt:ancla:problem:validation-failure"
+	MissingPartnerIDsProblem      = "urn:xmidt:ancla:problem:missing-partner-ids"
+	MissingPrincipalProblem       = "urn:xmidt:ancla:problem:missing-principal"
+	MissingWebhookIDProblem       = "urn:xmidt:ancla:problem:missing-webhook-id"
+	UnsupportedContentTypeProblem = "urn:xmidt:ancla:problem:unsupported-content-type"
+	RequestBodyTooLargeProblem    = "urn:xmidt:ancla:problem:request-body-too-large"
+	ItemTooLargeProblem           = "urn:xmidt:ancla:problem:item-too-large"
+	WebhookExistsProblem          = "urn:xmidt:ancla:problem:webhook-exists"
+	WebhookNotFoundProblem        = "urn:xmidt:ancla:problem:webhook-not-found"
+	WebhookNotOwnedProblem        = "urn:xmidt:ancla:problem:webhook-not-owned"
+	TimeoutProblem                = "urn:xmidt:ancla:problem:timeout"
+	NotSupportedProblem           = "urn:xmidt:ancla:problem:not-supported"
+	MethodNotAllowedProblem       = "urn:xmidt:ancla:problem:method-not-allowed"
+
+	blankProblem = "about:blank"
+)
+
+// problemTypes maps the errors matched with errors.Is to their problem type and
+// title, the first match winning.
+var problemTypes = []struct {
+	err   error
+	typ   string
+	title string
+}{
+	{errFailedWebhookUnmarshal, UnmarshalFailureProblem, "Failed to unmarshal the webhook"},
+	{errWebhookValidation, ValidationFailureProblem, "Webhook validation failed"},
+	{errGettingPartnerIDs, MissingPartnerIDsProblem, "Missing partner IDs"},
+	{errNoPartnerIDs, MissingPartnerIDsProblem, "Missing partner IDs"},
+	{errPrincipalMissing, MissingPrincipalProblem, "Missing principal"},
+	{errWebhookIDMissing, MissingWebhookIDProblem, "Missing webhook ID"},
+	{errUnsupportedContentType, UnsupportedContentTypeProblem, "Unsupported content type"},
+	{errMissingContentType, UnsupportedContentTypeProblem, "Unsupported content type"},
+	{errRequestBodyTooLarge, RequestBodyTooLargeProblem, "Request body too large"},
+	{ErrItemTooLarge, ItemTooLargeProblem, "Webhook item too large"},
+	{ErrWebhookExists, WebhookExistsProblem, "Webhook already exists"},
+	{ErrWebhookNotFound, WebhookNotFoundProblem, "Webhook not found"},
+	{ErrWebhookNotOwned, WebhookNotOwnedProblem, "Webhook owned by another principal"},
+	{errValidationBudgetExceeded, TimeoutProblem, "Webhook validation timed out"},
+	{errStoreBudgetExceeded, TimeoutProblem, "Webhook store timed out"},
+	{errCreateOnlyNotSupported, NotSupportedProblem, "Create only adds not supported"},
+	{errPagingNotSupported, NotSupportedProblem, "Paging not supported"},
+	{errRawNotSupported, NotSupportedProblem, "Raw items not supported"},
+	{errMethodNotAllowed, MethodNotAllowedProblem, "Method not allowed"},
+}
+
+// problemType returns the type and title of the error's problem.
+func problemType(err error, code int) (string, string) {
+	for _, p := range problemTypes {
+		if errors.Is(err, p.err) {
+			return p.typ, p.title
+		}
+	}
+	return blankProblem, http.StatusText(code)
+}
+
+// problemBody returns the ProblemJSON body of the error response, the detail
+// being its message.
+func problemBody(err error, code int, detail string, details []string) map[string]interface{} {
+	typ, title := problemType(err, code)
+	body := map[string]interface{}{
+		"type":   typ,
+		"title":  title,
+		"status": code,
+		"detail": detail,
+	}
+	if details != nil {
+		body["details"] = details
+	}
+	return body
+}
+
+// invalidWebhookError marks the error of a webhook failing validation, so it
+// matches errWebhookValidation, keeping its message.
+type invalidWebhookError struct {
+	err error
+}
+
+func (e invalidWebhookError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidWebhookError) Unwrap() error {
+	return e.err
+}
+
+func (e invalidWebhookError) Is(target error) bool {
+	return target == errWebhookValidation
+}
@@ -0,0 +1,250 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/httpaux/erraux"
+)
+
+type problem struct {
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	Status  int      `json:"status"`
+	Detail  string   `json:"detail"`
+	Details []string `json:"details"`
+}
+
+func TestProblemJSONErrorEncoder(t *testing.T) {
+	tcs := []struct {
+		desc          string
+		err           error
+		expectedType  string
+		expectedTitle string
+		expectedCode  int
+	}{
+		{
+			desc:          "Unmarshal failure",
+			err:           &erraux.Error{Err: fmt.Errorf("%w: unexpected EOF", errFailedWebhookUnmarshal), Code: http.StatusBadRequest},
+			expectedType:  UnmarshalFailureProblem,
+			expectedTitle: "Failed to unmarshal the webhook",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:          "Validation failure",
+			err:           &erraux.Error{Err: invalidWebhookError{errZeroEvents}, Message: "failed webhook validation", Code: http.StatusBadRequest},
+			expectedType:  ValidationFailureProblem,
+			expectedTitle: "Webhook validation failed",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:          "Partner IDs unavailable",
+			err:           &erraux.Error{Err: errGettingPartnerIDs, Message: "failed getting partnerIDs", Code: http.StatusBadRequest},
+			expectedType:  MissingPartnerIDsProblem,
+			expectedTitle: "Missing partner IDs",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:          "No partner IDs",
+			err:           &erraux.Error{Err: errNoPartnerIDs, Message: "failed getting partnerIDs", Code: http.StatusBadRequest},
+			expectedType:  MissingPartnerIDsProblem,
+			expectedTitle: "Missing partner IDs",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:          "Missing principal",
+			err:           &erraux.Error{Err: errPrincipalMissing, Code: http.StatusForbidden},
+			expectedType:  MissingPrincipalProblem,
+			expectedTitle: "Missing principal",
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			desc:          "Missing webhook ID",
+			err:           &erraux.Error{Err: errWebhookIDMissing, Code: http.StatusBadRequest},
+			expectedType:  MissingWebhookIDProblem,
+			expectedTitle: "Missing webhook ID",
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:          "Unsupported content type",
+			err:           &erraux.Error{Err: errUnsupportedContentType, Code: http.StatusUnsupportedMediaType},
+			expectedType:  UnsupportedContentTypeProblem,
+			expectedTitle: "Unsupported content type",
+			expectedCode:  http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:          "Missing content type",
+			err:           &erraux.Error{Err: errMissingContentType, Code: http.StatusUnsupportedMediaType},
+			expectedType:  UnsupportedContentTypeProblem,
+			expectedTitle: "Unsupported content type",
+			expectedCode:  http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:          "Request body too large",
+			err:           &erraux.Error{Err: fmt.Errorf("%w: more than 1 bytes", errRequestBodyTooLarge), Code: http.StatusRequestEntityTooLarge},
+			expectedType:  RequestBodyTooLargeProblem,
+			expectedTitle: "Request body too large",
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "Item too large",
+			err:           &erraux.Error{Err: ErrItemTooLarge, Code: http.StatusRequestEntityTooLarge},
+			expectedType:  ItemTooLargeProblem,
+			expectedTitle: "Webhook item too large",
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:          "Webhook exists",
+			err:           &erraux.Error{Err: ErrWebhookExists, Code: http.StatusConflict},
+			expectedType:  WebhookExistsProblem,
+			expectedTitle: "Webhook already exists",
+			expectedCode:  http.StatusConflict,
+		},
+		{
+			desc:          "Webhook not found",
+			err:           &erraux.Error{Err: ErrWebhookNotFound, Code: http.StatusNotFound},
+			expectedType:  WebhookNotFoundProblem,
+			expectedTitle: "Webhook not found",
+			expectedCode:  http.StatusNotFound,
+		},
+		{
+			desc:          "Webhook not owned",
+			err:           &erraux.Error{Err: ErrWebhookNotOwned, Code: http.StatusForbidden},
+			expectedType:  WebhookNotOwnedProblem,
+			expectedTitle: "Webhook owned by another principal",
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			desc:          "Validation timeout",
+			err:           &erraux.Error{Err: errValidationBudgetExceeded, Code: http.StatusGatewayTimeout},
+			expectedType:  TimeoutProblem,
+			expectedTitle: "Webhook validation timed out",
+			expectedCode:  http.StatusGatewayTimeout,
+		},
+		{
+			desc:          "Store timeout",
+			err:           &erraux.Error{Err: errStoreBudgetExceeded, Code: http.StatusGatewayTimeout},
+			expectedType:  TimeoutProblem,
+			expectedTitle: "Webhook store timed out",
+			expectedCode:  http.StatusGatewayTimeout,
+		},
+		{
+			desc:          "Create only not supported",
+			err:           &erraux.Error{Err: errCreateOnlyNotSupported, Code: http.StatusNotImplemented},
+			expectedType:  NotSupportedProblem,
+			expectedTitle: "Create only adds not supported",
+			expectedCode:  http.StatusNotImplemented,
+		},
+		{
+			desc:          "Paging not supported",
+			err:           &erraux.Error{Err: errPagingNotSupported, Code: http.StatusNotImplemented},
+			expectedType:  NotSupportedProblem,
+			expectedTitle: "Paging not supported",
+			expectedCode:  http.StatusNotImplemented,
+		},
+		{
+			desc:          "Raw items not supported",
+			err:           &erraux.Error{Err: errRawNotSupported, Code: http.StatusNotImplemented},
+			expectedType:  NotSupportedProblem,
+			expectedTitle: "Raw items not supported",
+			expectedCode:  http.StatusNotImplemented,
+		},
+		{
+			desc:          "Method not allowed",
+			err:           &erraux.Error{Err: errMethodNotAllowed, Code: http.StatusMethodNotAllowed},
+			expectedType:  MethodNotAllowedProblem,
+			expectedTitle: "Method not allowed",
+			expectedCode:  http.StatusMethodNotAllowed,
+		},
+		{
+			desc:          "Unknown error",
+			err:           errors.New("something broke"),
+			expectedType:  "about:blank",
+			expectedTitle: "Internal Server Error",
+			expectedCode:  http.StatusInternalServerError,
+		},
+		{
+			desc:          "Unknown error with a status",
+			err:           &HTTPError{Code: http.StatusTeapot, Message: "short and stout"},
+			expectedType:  "about:blank",
+			expectedTitle: "I'm a teapot",
+			expectedCode:  http.StatusTeapot,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			recorder := httptest.NewRecorder()
+			formattedErrorEncoder(nil, ProblemJSON)(context.Background(), tc.err, recorder)
+
+			assert.Equal(tc.expectedCode, recorder.Code)
+			assert.Equal("application/problem+json", recorder.Header().Get("Content-Type"))
+			var p problem
+			require.NoError(json.Unmarshal(recorder.Body.Bytes(), &p))
+			assert.Equal(tc.expectedType, p.Type)
+			assert.Equal(tc.expectedTitle, p.Title)
+			assert.Equal(tc.expectedCode, p.Status)
+			assert.NotEmpty(p.Detail)
+		})
+	}
+}
+
+func TestProblemJSONAddWebhookHandler(t *testing.T) {
+	tcs := []struct {
+		desc           string
+		format         ErrorFormat
+		expectedType   string
+		expectedBody   string
+		expectedHeader string
+	}{
+		{
+			desc:           "Default format",
+			expectedBody:   `{"message": "failed webhook validation: cannot have zero events", "details": ["cannot have zero events"]}`,
+			expectedHeader: "application/json",
+		},
+		{
+			desc:           "ProblemJSON",
+			format:         ProblemJSON,
+			expectedType:   ValidationFailureProblem,
+			expectedHeader: "application/problem+json",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			handler := NewAddWebhookHandler(new(mockService), HandlerConfig{
+				V:                 ValidatorFunc(func(Webhook) error { return ValidationErrors{errZeroEvents} }),
+				DisablePartnerIDs: true,
+				ErrorFormat:       tc.format,
+			})
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/hook", bytes.NewBufferString(addWebhookDecoderInput())))
+
+			assert.Equal(http.StatusBadRequest, recorder.Code)
+			assert.Equal(tc.expectedHeader, recorder.Header().Get("Content-Type"))
+			if tc.expectedBody != "" {
+				assert.JSONEq(tc.expectedBody, recorder.Body.String())
+				return
+			}
+			var p problem
+			if assert.NoError(json.Unmarshal(recorder.Body.Bytes(), &p)) {
+				assert.Equal(tc.expectedType, p.Type)
+				assert.Equal(http.StatusBadRequest, p.Status)
+				assert.Equal("failed webhook validation: cannot have zero events", p.Detail)
+				assert.Equal([]string{"cannot have zero events"}, p.Details)
+			}
+		})
+	}
+}
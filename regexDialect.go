@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+)
+
+var (
+	errUnknownRegexDialect = errors.New("unknown regex dialect")
+	errRegexLookaround     = errors.New("lookaround assertions are not supported")
+	errRegexBackreference  = errors.New("backreferences are not supported")
+	errRegexDollar         = errors.New(`"$" also matches before a trailing newline in PCRE engines, use "\z" instead`)
+)
+
+// RegexDialect decides which regular expressions are accepted in a webhook's Events.
+type RegexDialect string
+
+const (
+	// RegexDialectRE2 accepts any expression Go's regexp package can compile.
+	RegexDialectRE2 RegexDialect = "re2"
+
+	// RegexDialectPortable additionally rejects constructs that consumers using
+	// PCRE style engines evaluate differently, so that an expression is either
+	// accepted and matches the same everywhere or rejected with a targeted message.
+	RegexDialectPortable RegexDialect = "portable"
+)
+
+// checkPortable parses the expression and reports the first construct
+// RegexDialectPortable rejects.
+func checkPortable(expr string) error {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		var serr *syntax.Error
+		if errors.As(err, &serr) {
+			switch {
+			// lookbehinds are reported as invalid named captures, so only the
+			// expression is checked.
+			case isLookaround(serr.Expr):
+				return fmt.Errorf("%w: %v", errRegexLookaround, serr.Expr)
+			case serr.Code == syntax.ErrInvalidEscape && isBackreference(serr.Expr):
+				return fmt.Errorf("%w: %v", errRegexBackreference, serr.Expr)
+			}
+		}
+		return err
+	}
+	return walkPortable(re)
+}
+
+func walkPortable(re *syntax.Regexp) error {
+	if re.Op == syntax.OpEndText && re.Flags&syntax.WasDollar != 0 {
+		return errRegexDollar
+	}
+	for _, sub := range re.Sub {
+		if err := walkPortable(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isLookaround(expr string) bool {
+	for _, prefix := range []string{"(?=", "(?!", "(?<=", "(?<!"} {
+		if strings.HasPrefix(expr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBackreference(expr string) bool {
+	return len(expr) == 2 && expr[0] == '\\' && (expr[1] >= '1' && expr[1] <= '9' || expr[1] == 'k' || expr[1] == 'g')
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRejectedURLCacheTTL = 30 * time.Second
+	maxRejectedURLs            = 1024
+)
+
+// rejectionCache remembers the validation errors of recently rejected receiver
+// URLs for a short time, so clients retrying a blocked URL don't make each
+// attempt pay for the DNS lookups of the validators again.
+type rejectionCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	lock    sync.Mutex
+	entries map[string]rejection
+}
+
+type rejection struct {
+	err     error
+	expires time.Time
+}
+
+func newRejectionCache(ttl time.Duration, now func() time.Time) *rejectionCache {
+	if ttl <= 0 {
+		ttl = defaultRejectedURLCacheTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &rejectionCache{
+		ttl:     ttl,
+		now:     now,
+		entries: make(map[string]rejection),
+	}
+}
+
+// get returns the cached error of the URL, or nil if it wasn't recently rejected.
+func (c *rejectionCache) get(rawURL string) error {
+	key := canonicalURL(rawURL)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if !c.now().Before(r.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return r.err
+}
+
+// add caches the error of the rejected URL. When the cache is full, expired
+// entries are dropped first and then arbitrary ones.
+func (c *rejectionCache) add(rawURL string, err error) {
+	key := canonicalURL(rawURL)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxRejectedURLs {
+		for k, r := range c.entries {
+			if !now.Before(r.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxRejectedURLs {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = rejection{err: err, expires: now.Add(c.ttl)}
+}
+
+// canonicalURL lowercases the scheme and host of the URL and drops its fragment,
+// returning unparseable URLs as is.
+func canonicalURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
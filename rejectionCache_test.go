@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectionCache(t *testing.T) {
+	assert := assert.New(t)
+	errBlocked := errors.New("blocked")
+	now := getRefTime()
+	cache := newRejectionCache(time.Second, func() time.Time { return now })
+
+	assert.NoError(cache.get("https://blocked.example.net"))
+	cache.add("https://blocked.example.net", errBlocked)
+	assert.ErrorIs(cache.get("https://blocked.example.net"), errBlocked)
+	assert.ErrorIs(cache.get(" HTTPS://Blocked.Example.net#fragment"), errBlocked)
+	assert.NoError(cache.get("https://blocked.example.net/other"))
+
+	now = now.Add(time.Second)
+	assert.NoError(cache.get("https://blocked.example.net"))
+	assert.Empty(cache.entries)
+}
+
+func TestRejectionCacheFull(t *testing.T) {
+	assert := assert.New(t)
+	errBlocked := errors.New("blocked")
+	now := getRefTime()
+	cache := newRejectionCache(0, func() time.Time { return now })
+	assert.Equal(defaultRejectedURLCacheTTL, cache.ttl)
+
+	for i := 0; i < maxRejectedURLs; i++ {
+		cache.add(fmt.Sprintf("https://%v.example.net", i), errBlocked)
+	}
+	now = now.Add(defaultRejectedURLCacheTTL / 2)
+	cache.add("https://0.example.net", errBlocked)
+	assert.Len(cache.entries, maxRejectedURLs)
+
+	// a new URL makes room by evicting one.
+	cache.add("https://new.example.net", errBlocked)
+	assert.Len(cache.entries, maxRejectedURLs)
+	assert.ErrorIs(cache.get("https://new.example.net"), errBlocked)
+
+	// expired entries are dropped first.
+	now = now.Add(defaultRejectedURLCacheTTL)
+	cache.add("https://newer.example.net", errBlocked)
+	assert.Len(cache.entries, 1)
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/xmidt-org/httpaux/erraux"
+)
+
+// Conventional paths on which NewRouter mounts the webhook registration handlers.
+const (
+	AddWebhookPath     = "/hook"
+	GetAllWebhooksPath = "/hooks"
+	GetWebhookPath     = "/hooks/{id}"
+)
+
+const allowHeader = "Allow"
+
+var errMethodNotAllowed = errors.New("method not allowed")
+
+// NewRouter returns an HTTP handler that mounts the webhook registration handlers
+// on their conventional paths:
+//
+//	POST  AddWebhookPath      adds a webhook registration.
+//	GET   GetAllWebhooksPath  lists all the currently registered webhooks.
+//	GET   GetWebhookPath      fetches a webhook registration by its ID.
+//	PATCH GetWebhookPath      extends a webhook registration by its ID.
+//
+// Requests to a known path with an unsupported method receive a 405 response with
+// an Allow header listing the supported methods and a JSON error body. Requests to
+// unknown paths receive a 404 response.
+func NewRouter(s Service, config HandlerConfig) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(AddWebhookPath, newMethodRouter(config, map[string]http.Handler{
+		http.MethodPost: NewAddWebhookHandler(s, config),
+	}))
+	mux.Handle(GetAllWebhooksPath, newMethodRouter(config, map[string]http.Handler{
+		http.MethodGet: NewGetAllWebhooksHandler(s, config),
+	}))
+	mux.Handle(GetWebhookPath, newMethodRouter(config, map[string]http.Handler{
+		http.MethodGet:   NewGetWebhookHandler(s, config),
+		http.MethodPatch: NewExtendWebhookHandler(s, config),
+	}))
+	return mux
+}
+
+// methodRouter dispatches requests to a handler based on the request method.
+type methodRouter struct {
+	handlers map[string]http.Handler
+	allow    string
+	config   HandlerConfig
+}
+
+func newMethodRouter(config HandlerConfig, handlers map[string]http.Handler) methodRouter {
+	methods := make([]string, 0, len(handlers))
+	for m := range handlers {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+
+	return methodRouter{
+		handlers: handlers,
+		allow:    strings.Join(methods, ", "),
+		config:   config,
+	}
+}
+
+func (mr methodRouter) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if h, ok := mr.handlers[r.Method]; ok {
+		h.ServeHTTP(rw, r)
+		return
+	}
+
+	mr.config.errorEncoder()(r.Context(), &erraux.Error{
+		Err:    errMethodNotAllowed,
+		Code:   http.StatusMethodNotAllowed,
+		Header: http.Header{allowHeader: []string{mr.allow}},
+	}, rw)
+}
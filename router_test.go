@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xmidt-org/ancla/auth"
+)
+
+func TestNewRouter(t *testing.T) {
+	methods := []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+		http.MethodOptions,
+	}
+	tcs := []struct {
+		desc    string
+		path    string
+		allowed string
+		// expectedCode is the status code of the request using the allowed method.
+		expectedCode int
+	}{
+		{
+			desc:         "Add webhook",
+			path:         AddWebhookPath,
+			allowed:      http.MethodPost,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Get all webhooks",
+			path:         GetAllWebhooksPath,
+			allowed:      http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Get webhook",
+			path:         "/hooks/some-id",
+			allowed:      "GET, PATCH",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tcs {
+		for _, method := range methods {
+			t.Run(tc.desc+" "+method, func(t *testing.T) {
+				assert := assert.New(t)
+				m := new(mockService)
+				// nolint:typecheck
+				m.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+				// nolint:typecheck
+				m.On("GetAll", mock.Anything).Return([]InternalWebhook{}, nil).Maybe()
+				// nolint:typecheck
+				m.On("Get", mock.Anything, "owner", "some-id").Return(InternalWebhook{}, nil).Maybe()
+				router := NewRouter(m, HandlerConfig{DisablePartnerIDs: true})
+
+				r := httptest.NewRequest(method, tc.path, strings.NewReader(addWebhookDecoderInput()))
+				r = r.WithContext(auth.SetPrincipal(context.Background(), "owner"))
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, r)
+
+				if strings.Contains(tc.allowed, method) {
+					assert.Equal(tc.expectedCode, recorder.Code)
+					assert.Empty(recorder.Header().Get("Allow"))
+					return
+				}
+
+				assert.Equal(http.StatusMethodNotAllowed, recorder.Code)
+				assert.Equal(tc.allowed, recorder.Header().Get("Allow"))
+				assert.Equal("application/json", recorder.Header().Get("Content-Type"))
+				assert.JSONEq(`{"message": "method not allowed"}`, recorder.Body.String())
+			})
+		}
+	}
+
+	t.Run("Unknown path", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		NewRouter(new(mockService), HandlerConfig{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
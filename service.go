@@ -5,15 +5,20 @@ package ancla
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/xmidt-org/ancla/chrysom"
+	"github.com/xmidt-org/ancla/model"
 	"go.uber.org/zap"
 )
 
-const errFmt = "%w: %v"
+const errFmt = "%w: %w"
 
 var (
 	errNonSuccessPushResult    = errors.New("got a push result but was not of success type")
@@ -21,17 +26,105 @@ var (
 	errFailedWebhookConversion = errors.New("failed to convert webhook to argus item")
 	errFailedItemConversion    = errors.New("failed to convert argus item to webhook")
 	errFailedWebhooksFetch     = errors.New("failed to fetch webhooks")
+
+	// ErrWebhookExists is returned by Creators when the webhook is already registered.
+	ErrWebhookExists = errors.New("webhook already exists")
+
+	// ErrWebhookNotFound is returned by Get when there is no webhook with the given ID.
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrWebhookNotOwned is returned by Get when the stored webhook belongs to
+	// another owner.
+	ErrWebhookNotOwned = errors.New("webhook owned by someone else")
+
+	// ErrInitialUpdateTimeout is returned by StartListener when the first update
+	// doesn't arrive within ListenerConfig.WaitForInitialUpdate.
+	ErrInitialUpdateTimeout = errors.New("timed out waiting for the initial webhook update")
+
+	// ErrItemTooLarge is returned when a webhook's encoded item exceeds Config.MaxItemSize.
+	ErrItemTooLarge = errors.New("webhook item is too large")
 )
 
+const defaultMaxItemSize = 256 * 1024
+
 // Service describes the core operations around webhook subscriptions.
 // Initialize() provides a service ready to use and the controls around watching for updates.
+// Errors returned by the Argus backed service wrap the chrysom errors that caused them,
+// so errors.Is() can be used with the exported chrysom errors such as chrysom.ErrBadRequest.
 type Service interface {
 	// Add adds the given owned webhook to the current list of webhooks. If the operation
 	// succeeds, a non-nil error is returned.
 	Add(ctx context.Context, owner string, iw InternalWebhook) error
 
-	// GetAll lists all the current registered webhooks.
+	// GetAll lists all the current registered webhooks, sorted by item ID.
 	GetAll(ctx context.Context) ([]InternalWebhook, error)
+
+	// GetAllByOwner lists the current registered webhooks of the given owner,
+	// sorted by item ID.
+	GetAllByOwner(ctx context.Context, owner string) ([]InternalWebhook, error)
+
+	// Get returns the owned webhook with the given item ID, failing with
+	// ErrWebhookNotFound if there is none and ErrWebhookNotOwned if it belongs
+	// to another owner.
+	Get(ctx context.Context, owner, id string) (InternalWebhook, error)
+}
+
+// Creator is implemented by Services that support create-only adds, which fail
+// with ErrWebhookExists instead of replacing an existing registration.
+type Creator interface {
+	Create(ctx context.Context, owner string, iw InternalWebhook) error
+}
+
+// ResultAdder is implemented by Services that report whether an add created
+// the webhook or replaced an existing one, so the add handler can respond with
+// a 201 or a 200.
+type ResultAdder interface {
+	// AddWithResult is Add, also returning chrysom.CreatedPushResult or
+	// chrysom.UpdatedPushResult when it succeeds.
+	AddWithResult(ctx context.Context, owner string, iw InternalWebhook) (chrysom.PushResult, error)
+}
+
+// BatchAdder is implemented by Services that can add many webhooks at once,
+// e.g. to migrate registrations into a new bucket.
+type BatchAdder interface {
+	// AddBatch adds the webhooks for the owner. Webhooks that fail don't keep
+	// the others from being added, and are reported by a *chrysom.BatchError
+	// holding their errors by index.
+	AddBatch(ctx context.Context, owner string, iws []InternalWebhook) error
+}
+
+// ItemLister is implemented by Services that can list the stored items behind
+// the webhooks, as served by the get all handler's include_raw query parameter.
+type ItemLister interface {
+	// GetAllItems lists the stored items of the given owner, or all of them when
+	// the owner is empty.
+	GetAllItems(ctx context.Context, owner string) (chrysom.Items, error)
+}
+
+// OwnerGetter is implemented by Services that can get a webhook along with the
+// owner it's stored for, so that the extend handler stores it again for that
+// owner.
+type OwnerGetter interface {
+	// GetWithOwner is Get, also returning the owner of the stored webhook.
+	GetWithOwner(ctx context.Context, owner, id string) (InternalWebhook, string, error)
+}
+
+// Pager is implemented by Services that can list the webhooks a page at a time,
+// as served by the get all handler's limit and offset query parameters.
+type Pager interface {
+	// GetPage lists up to limit webhooks of the given owner, or of everyone when
+	// the owner is empty, sorted by item ID and skipping the first offset of them.
+	// An offset past the last webhook lists none. It also reports whether any
+	// webhooks remain after the page.
+	GetPage(ctx context.Context, owner string, offset, limit int) ([]InternalWebhook, bool, error)
+}
+
+// Counter is implemented by Services that can count webhooks without
+// converting them, e.g. for quota checks.
+type Counter interface {
+	// Count returns the number of webhooks of the given owner, or of everyone
+	// when the owner is empty, counting no further than limit when it's above 0.
+	Count(ctx context.Context, owner string, limit int) (int, error)
 }
 
 // Config contains information needed to initialize the Argus Client service.
@@ -53,6 +146,44 @@ type Config struct {
 	// compile into regular expressions, and the Events field must have at
 	// least one value and all values must compile into regular expressions.
 	Validation ValidatorConfig
+
+	// Reader is where webhooks are read from by GetAll and the listener, e.g. a
+	// chrysom.MultiReader merging several Argus clusters during a migration.
+	// Writes always go to the client built from BasicClientConfig.
+	// (Optional). Defaults to that same client.
+	Reader chrysom.Reader
+
+	// IDHasher derives the item IDs of added webhooks from their URLs.
+	// See IDHasher for how to change it in an existing deployment.
+	// (Optional). Defaults to SHA256IDHasher.
+	IDHasher IDHasher
+
+	// MaxItemSize is the maximum size in bytes of a webhook's JSON encoded item
+	// data. It is checked by Add, so it covers every path that adds webhooks.
+	// (Optional). Defaults to 256KiB.
+	MaxItemSize int
+
+	// ExpiryMismatchTolerance is how far the expiry implied by an item's TTL may
+	// be from its webhook's until before GetAll and the listener log and count
+	// the item with ExpiryMismatches.
+	// (Optional). Defaults to 1 minute.
+	ExpiryMismatchTolerance time.Duration
+
+	// ExpiryMismatches counts the items whose TTL and until disagree, labeled
+	// by DirectionLabel.
+	// (Optional). Defaults to not counting them.
+	ExpiryMismatches *prometheus.CounterVec
+
+	// ReadRepair, if true, rewrites items whose TTL and until disagree with the
+	// earlier of the two as both their until and TTL.
+	// (Optional). Defaults to false.
+	ReadRepair bool
+
+	// MaxReadRepairsPerMinute caps the items rewritten by ReadRepair, so that
+	// many mismatched items can't cause a write storm. Mismatched items over the
+	// cap are repaired on later reads.
+	// (Optional). Defaults to 10.
+	MaxReadRepairsPerMinute int
 }
 
 // ListenerConfig contains information needed to initialize the Listener Client service.
@@ -67,13 +198,58 @@ type ListenerConfig struct {
 	// Measures for instrumenting this package.
 	// Gets passed to Argus config before initializing the client.
 	Measures Measures
+
+	// CountInitialRegistrations, if true, counts all the registrations of the
+	// first poll as added. Otherwise the first poll only sets the baseline for
+	// the registration churn counters.
+	// (Optional). Defaults to false.
+	CountInitialRegistrations bool
+
+	// WatchTimeout caps the time a watch's update may take before the remaining
+	// watches are updated, so a stuck watch can't freeze all future updates. A
+	// watch that runs over is logged, counted by Measures.WatcherTimeouts and left
+	// to finish in the background. Such a watch leaks a goroutine for as long as
+	// it's stuck and may be updated again before its previous update returns.
+	// (Optional). Defaults to 0, which waits for each watch without a cap.
+	WatchTimeout time.Duration
+
+	// WaitForInitialUpdate makes StartListener block until the watches receive
+	// their first update, for up to this long, so that a service doesn't start
+	// serving with an empty list of webhooks. The first poll happens one
+	// Config.PullInterval after starting.
+	// (Optional). Defaults to 0, which doesn't wait.
+	WaitForInitialUpdate time.Duration
+
+	// FailOnInitialUpdateTimeout, if true, makes StartListener stop the listener
+	// and fail with ErrInitialUpdateTimeout when WaitForInitialUpdate elapses.
+	// Otherwise the timeout is logged and the listener keeps polling.
+	// (Optional). Defaults to false.
+	FailOnInitialUpdateTimeout bool
+
+	// RefreshAfterAdd, if true, makes the listener poll RefreshDebounce after
+	// webhooks are added through the service, so the watches see them without
+	// waiting up to Config.PullInterval. Adds made before that poll share it,
+	// while adds made during it, e.g. by a watch, wait for the next scheduled
+	// poll so they can't trigger polls in a loop.
+	// (Optional). Defaults to false.
+	RefreshAfterAdd bool
+
+	// RefreshDebounce is how long RefreshAfterAdd waits after an add before
+	// polling.
+	// (Optional). Defaults to 100ms.
+	RefreshDebounce time.Duration
 }
 
 type service struct {
 	argus  chrysom.PushReader
+	reader chrysom.Reader
 	logger *zap.Logger
 	config Config
 	now    func() time.Time
+	expiry *expiryChecker
+	// refresher refreshes the listener after adds when
+	// ListenerConfig.RefreshAfterAdd is set.
+	refresher *addRefresher
 }
 
 // NewService builds the Argus client service from the given configuration.
@@ -84,14 +260,17 @@ func NewService(cfg Config, getLogger func(context.Context) *zap.Logger) (*servi
 
 	basic, err := chrysom.NewBasicClient(cfg.BasicClientConfig, getLogger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chrysom basic client: %v", err)
+		return nil, fmt.Errorf("failed to create chrysom basic client: %w", err)
 	}
 	svc := &service{
-		logger: cfg.Logger,
-		argus:  basic,
-		config: cfg,
-		now:    time.Now,
+		logger:    cfg.Logger,
+		argus:     basic,
+		reader:    cfg.Reader,
+		config:    cfg,
+		now:       time.Now,
+		refresher: new(addRefresher),
 	}
+	svc.expiry = newExpiryChecker(cfg, basic, svc.now)
 	return svc, nil
 }
 
@@ -102,46 +281,197 @@ func (s *service) StartListener(cfg ListenerConfig, setLogger func(context.Conte
 	if cfg.Logger == nil {
 		cfg.Logger = zap.NewNop()
 	}
+	initialUpdate := make(chan struct{})
+	if cfg.WaitForInitialUpdate > 0 {
+		var once sync.Once
+		watches = append(watches, WatchFunc(func([]InternalWebhook) {
+			once.Do(func() { close(initialUpdate) })
+		}))
+	}
 	prepArgusListenerClientConfig(&cfg, watches...)
+	if l, ok := cfg.Config.Listener.(*watchListener); ok {
+		l.checkItems = s.expiry.check
+	}
 	m := &chrysom.Measures{
-		Polls: cfg.Measures.ChrysomPollsTotalCounterName,
+		Polls:              cfg.Measures.ChrysomPollsTotalCounterName,
+		PollDuration:       cfg.Measures.ChrysomPollDuration,
+		LastSuccessfulPoll: cfg.Measures.ChrysomLastSuccessfulPoll,
+		ItemsFetched:       cfg.Measures.ChrysomItemsFetched,
+		LastPollStatus:     cfg.Measures.ChrysomLastPollStatus,
 	}
-	listener, err := chrysom.NewListenerClient(cfg.Config, setLogger, m, s.argus)
+	listener, err := chrysom.NewListenerClient(cfg.Config, setLogger, m, s.itemReader())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chrysom listener client: %v", err)
+		return nil, fmt.Errorf("failed to create chrysom listener client: %w", err)
 	}
 
 	listener.Start(context.Background())
-	return func() { listener.Stop(context.Background()) }, nil
+	stop := func() { listener.Close() }
+	if cfg.RefreshAfterAdd {
+		if s.refresher == nil {
+			s.refresher = new(addRefresher)
+		}
+		s.refresher.start(listener.Refresh, cfg.RefreshDebounce, cfg.Logger)
+		stop = func() {
+			s.refresher.stop()
+			listener.Close()
+		}
+	}
+	if cfg.WaitForInitialUpdate <= 0 {
+		return stop, nil
+	}
+
+	timer := time.NewTimer(cfg.WaitForInitialUpdate)
+	defer timer.Stop()
+	select {
+	case <-initialUpdate:
+	case <-timer.C:
+		if cfg.FailOnInitialUpdateTimeout {
+			stop()
+			return nil, fmt.Errorf("%w after %v", ErrInitialUpdateTimeout, cfg.WaitForInitialUpdate)
+		}
+		cfg.Logger.Warn("Timed out waiting for the initial webhook update, continuing without it",
+			zap.Duration("timeout", cfg.WaitForInitialUpdate))
+	}
+	return stop, nil
+}
+
+// Close closes the Argus client. The service can't be used afterwards.
+func (s *service) Close() error {
+	if c, ok := s.argus.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
 
 func (s *service) Add(ctx context.Context, owner string, iw InternalWebhook) error {
-	item, err := InternalWebhookToItem(s.now, iw)
+	_, err := s.AddWithResult(ctx, owner, iw)
+	return err
+}
+
+// AddWithResult is Add, also reporting whether the webhook was created or
+// replaced an existing one.
+func (s *service) AddWithResult(ctx context.Context, owner string, iw InternalWebhook) (chrysom.PushResult, error) {
+	item, err := s.toItem(iw)
 	if err != nil {
-		return fmt.Errorf(errFmt, errFailedWebhookConversion, err)
+		return chrysom.UnknownPushResult, fmt.Errorf(errFmt, errFailedWebhookConversion, err)
 	}
 	result, err := s.argus.PushItem(ctx, owner, item)
 	if err != nil {
-		return fmt.Errorf(errFmt, errFailedWebhookPush, err)
+		return chrysom.UnknownPushResult, fmt.Errorf(errFmt, errFailedWebhookPush, err)
 	}
 
 	if result == chrysom.CreatedPushResult || result == chrysom.UpdatedPushResult {
+		s.refresher.trigger()
+		return result, nil
+	}
+	return chrysom.UnknownPushResult, fmt.Errorf("%w: %s", errNonSuccessPushResult, result)
+}
+
+// AddBatch adds the webhooks, pushing them all at once when the client is a
+// chrysom.BulkPusher and one after the other otherwise. The webhooks that can't
+// be converted or pushed are reported by a *chrysom.BatchError by their index.
+func (s *service) AddBatch(ctx context.Context, owner string, iws []InternalWebhook) error {
+	errs := make([]error, len(iws))
+	items := make([]model.Item, 0, len(iws))
+	indices := make([]int, 0, len(iws))
+	for i, iw := range iws {
+		item, err := s.toItem(iw)
+		if err != nil {
+			errs[i] = fmt.Errorf(errFmt, errFailedWebhookConversion, err)
+			continue
+		}
+		items = append(items, item)
+		indices = append(indices, i)
+	}
+
+	results := make([]chrysom.PushResult, len(items))
+	pushErrs := make([]error, len(items))
+	if p, ok := s.argus.(chrysom.BulkPusher); ok {
+		var err error
+		results, err = p.PushItems(ctx, owner, items)
+		var batchErr *chrysom.BatchError
+		switch {
+		case errors.As(err, &batchErr):
+			for j, err := range batchErr.Errs {
+				pushErrs[j] = err
+			}
+		case err != nil:
+			return fmt.Errorf(errFmt, errFailedWebhookPush, err)
+		}
+	} else {
+		for j, item := range items {
+			results[j], pushErrs[j] = s.argus.PushItem(ctx, owner, item)
+		}
+	}
+
+	for j, i := range indices {
+		switch {
+		case pushErrs[j] != nil:
+			errs[i] = fmt.Errorf(errFmt, errFailedWebhookPush, pushErrs[j])
+		case results[j] != chrysom.CreatedPushResult && results[j] != chrysom.UpdatedPushResult:
+			errs[i] = fmt.Errorf("%w: %s", errNonSuccessPushResult, results[j])
+		default:
+			s.refresher.trigger()
+		}
+	}
+	return chrysom.NewBatchError(errs)
+}
+
+// Create adds the webhook unless one with the same ID is already registered.
+// The check and the add are separate requests since Argus has no conditional
+// writes, so a webhook registered in between is replaced. This is best effort:
+// such a replacement is detected and logged but not reported as an error.
+func (s *service) Create(ctx context.Context, owner string, iw InternalWebhook) error {
+	item, err := s.toItem(iw)
+	if err != nil {
+		return fmt.Errorf(errFmt, errFailedWebhookConversion, err)
+	}
+	items, err := s.argus.GetItems(ctx, owner)
+	if err != nil {
+		return fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+	for _, existing := range items {
+		if existing.ID == item.ID {
+			return fmt.Errorf("%w: %s", ErrWebhookExists, iw.Webhook.Config.URL)
+		}
+	}
+
+	result, err := s.argus.PushItem(ctx, owner, item)
+	if err != nil {
+		return fmt.Errorf(errFmt, errFailedWebhookPush, err)
+	}
+	switch result {
+	case chrysom.CreatedPushResult:
+		s.refresher.trigger()
+		return nil
+	case chrysom.UpdatedPushResult:
+		s.logger.Warn("Webhook registered concurrently with a create only add was replaced",
+			zap.String("id", item.ID))
+		s.refresher.trigger()
 		return nil
 	}
 	return fmt.Errorf("%w: %s", errNonSuccessPushResult, result)
 }
 
 // GetAll returns all webhooks found on the configured webhooks partition
-// of Argus.
+// of Argus, sorted by item ID.
 func (s *service) GetAll(ctx context.Context) ([]InternalWebhook, error) {
-	items, err := s.argus.GetItems(ctx, "")
+	return s.GetAllByOwner(ctx, "")
+}
+
+// GetAllByOwner returns the webhooks of the given owner found on the configured
+// webhooks partition of Argus, sorted by item ID. An empty owner returns all
+// webhooks.
+func (s *service) GetAllByOwner(ctx context.Context, owner string) ([]InternalWebhook, error) {
+	items, err := s.itemReader().GetItems(ctx, owner)
 	if err != nil {
 		return nil, fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
 	}
+	s.expiry.check(ctx, items)
 
 	iws := make([]InternalWebhook, len(items))
 
-	for i, item := range items {
+	for i, item := range items.Sorted() {
 		webhook, err := ItemToInternalWebhook(item)
 		if err != nil {
 			return nil, fmt.Errorf(errFmt, errFailedItemConversion, err)
@@ -152,17 +482,225 @@ func (s *service) GetAll(ctx context.Context) ([]InternalWebhook, error) {
 	return iws, nil
 }
 
+// GetPage returns a page of the webhooks of the given owner found on the
+// configured webhooks partition of Argus, sorted by item ID. Argus doesn't page
+// the items, so all of them are fetched but only the page's are converted.
+func (s *service) GetPage(ctx context.Context, owner string, offset, limit int) ([]InternalWebhook, bool, error) {
+	items, err := s.itemReader().GetItems(ctx, owner)
+	if err != nil {
+		return nil, false, fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+
+	items = items.Sorted()
+	start := min(max(offset, 0), len(items))
+	end := len(items)
+	if limit < end-start {
+		end = start + max(limit, 0)
+	}
+	iws := make([]InternalWebhook, 0, end-start)
+	for _, item := range items[start:end] {
+		webhook, err := ItemToInternalWebhook(item)
+		if err != nil {
+			return nil, false, fmt.Errorf(errFmt, errFailedItemConversion, err)
+		}
+		iws = append(iws, webhook)
+	}
+	return iws, end < len(items), nil
+}
+
+// Count returns the number of webhooks of the given owner found on the
+// configured webhooks partition of Argus, counting no further than limit when
+// it's above 0. Stores that support it are only asked for that many items.
+func (s *service) Count(ctx context.Context, owner string, limit int) (int, error) {
+	n, err := chrysom.CountItems(ctx, s.itemReader(), owner, limit)
+	if err != nil {
+		return 0, fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+	return n, nil
+}
+
+// GetAllItems returns the items of the given owner found on the configured
+// webhooks partition of Argus as they are stored. An empty owner returns all items.
+func (s *service) GetAllItems(ctx context.Context, owner string) (chrysom.Items, error) {
+	items, err := s.itemReader().GetItems(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+	return items, nil
+}
+
+// Get returns the webhook with the given item ID. Clients that aren't
+// chrysom.ItemGetters are asked for all of the owner's items instead. Items
+// without an owner are returned to anyone.
+func (s *service) Get(ctx context.Context, owner, id string) (InternalWebhook, error) {
+	iw, _, err := s.GetWithOwner(ctx, owner, id)
+	return iw, err
+}
+
+// GetWithOwner returns the webhook like Get, along with the owner of its item.
+func (s *service) GetWithOwner(ctx context.Context, owner, id string) (InternalWebhook, string, error) {
+	item, err := s.getItem(ctx, owner, id)
+	if errors.Is(err, chrysom.ErrItemNotFound) {
+		return InternalWebhook{}, "", fmt.Errorf(errFmt, ErrWebhookNotFound, err)
+	}
+	if err != nil {
+		return InternalWebhook{}, "", fmt.Errorf(errFmt, errFailedWebhooksFetch, err)
+	}
+	if owner != "" && item.Owner != "" && item.Owner != owner {
+		return InternalWebhook{}, "", fmt.Errorf("%w: %s", ErrWebhookNotOwned, id)
+	}
+
+	iw, err := ItemToInternalWebhook(item)
+	if err != nil {
+		return InternalWebhook{}, "", fmt.Errorf(errFmt, errFailedItemConversion, err)
+	}
+	return iw, item.Owner, nil
+}
+
+func (s *service) getItem(ctx context.Context, owner, id string) (model.Item, error) {
+	if g, ok := s.argus.(chrysom.ItemGetter); ok {
+		return g.GetItem(ctx, owner, id)
+	}
+	items, err := s.argus.GetItems(ctx, owner)
+	if err != nil {
+		return model.Item{}, err
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return model.Item{}, chrysom.ErrItemNotFound
+}
+
+// toItem converts the webhook into an item, with its ID derived by the
+// configured IDHasher.
+func (s *service) toItem(iw InternalWebhook) (model.Item, error) {
+	item, err := InternalWebhookToItem(s.now, iw)
+	if err != nil {
+		return item, err
+	}
+	if s.config.IDHasher != nil {
+		item.ID = s.config.IDHasher(iw.Webhook.Config.URL)
+	}
+
+	maxSize := s.config.MaxItemSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxItemSize
+	}
+	data, err := json.Marshal(item.Data)
+	if err != nil {
+		return item, err
+	}
+	if len(data) > maxSize {
+		return item, fmt.Errorf("%w: %v bytes exceeds the limit of %v bytes", ErrItemTooLarge, len(data), maxSize)
+	}
+	return item, nil
+}
+
+// itemReader returns the configured Reader, falling back to the Argus client.
+func (s *service) itemReader() chrysom.Reader {
+	if s.reader != nil {
+		return s.reader
+	}
+	return s.argus
+}
+
 func prepArgusListenerClientConfig(cfg *ListenerConfig, watches ...Watch) {
 	logger := cfg.Logger
 	watches = append(watches, webhookListSizeWatch(cfg.Measures.WebhookListSizeGaugeName))
-	cfg.Config.Listener = chrysom.ListenerFunc(func(items chrysom.Items) {
-		iws, err := ItemsToInternalWebhooks(items)
+	if m := cfg.Measures; m.RegistrationsAdded != nil && m.RegistrationsRemoved != nil && m.RegistrationsUpdated != nil {
+		watches = append(watches, registrationChurnWatch(m.RegistrationsAdded, m.RegistrationsRemoved, m.RegistrationsUpdated, cfg.CountInitialRegistrations))
+	}
+	cfg.Config.Listener = &watchListener{
+		logger:   logger,
+		watches:  watches,
+		timeout:  cfg.WatchTimeout,
+		timeouts: cfg.Measures.WatcherTimeouts,
+	}
+}
+
+// watchListener converts the polled items into webhooks for the watches.
+type watchListener struct {
+	logger   *zap.Logger
+	watches  []Watch
+	timeout  time.Duration
+	timeouts prometheus.Counter
+	// checkItems, if set, is given the items of successful polls.
+	checkItems func(context.Context, chrysom.Items)
+}
+
+func (l *watchListener) Update(items chrysom.Items) {
+	l.update(nil, items)
+}
+
+func (l *watchListener) UpdateWithMeta(meta chrysom.UpdateMeta, items chrysom.Items) {
+	l.update(&meta, items)
+}
+
+// update updates the watches, only telling the MetaWatches about failed polls
+// and polls with unchanged items. The webhooks are sorted by item ID so that
+// the watches see them in the same order on every poll, whatever order the
+// store returned them in.
+func (l *watchListener) update(meta *chrysom.UpdateMeta, items chrysom.Items) {
+	var iws []InternalWebhook
+	failed := meta != nil && meta.Outcome != chrysom.SuccessOutcome
+	metaOnly := failed || (meta != nil && meta.Unchanged)
+	if !failed {
+		if l.checkItems != nil {
+			l.checkItems(context.Background(), items)
+		}
+		var err error
+		iws, err = ItemsToInternalWebhooks(items.Sorted())
 		if err != nil {
-			logger.Error("Failed to convert items to webhooks", zap.Error(err))
+			l.logger.Error("Failed to convert items to webhooks", zap.Error(err))
 			return
 		}
-		for _, watch := range watches {
-			watch.Update(iws)
+	}
+	for _, watch := range l.watches {
+		if _, ok := watch.(MetaWatch); metaOnly && !ok {
+			continue
 		}
-	})
+		l.updateWithin(watch, meta, iws)
+	}
+}
+
+// updateWithin updates the watch, giving up waiting on it once the timeout elapses.
+func (l *watchListener) updateWithin(watch Watch, meta *chrysom.UpdateMeta, iws []InternalWebhook) {
+	if l.timeout <= 0 {
+		safeUpdate(l.logger, watch, meta, iws)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		safeUpdate(l.logger, watch, meta, iws)
+	}()
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		l.logger.Error("Webhook watch timed out, continuing with the remaining watches",
+			zap.Duration("timeout", l.timeout), zap.String("watch", fmt.Sprintf("%T", watch)))
+		if l.timeouts != nil {
+			l.timeouts.Inc()
+		}
+	}
+}
+
+// safeUpdate updates the watch, logging instead of propagating a panic so the
+// remaining watches are still updated. MetaWatches are given the meta if there is one.
+func safeUpdate(logger *zap.Logger, watch Watch, meta *chrysom.UpdateMeta, iws []InternalWebhook) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Webhook watch panicked", zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+	if mw, ok := watch.(MetaWatch); ok && meta != nil {
+		mw.UpdateWithMeta(*meta, iws)
+		return
+	}
+	watch.Update(iws)
 }
@@ -4,16 +4,26 @@ package ancla
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/xmidt-org/ancla/chrysom"
 	"github.com/xmidt-org/ancla/model"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewService(t *testing.T) {
@@ -110,6 +120,13 @@ func TestAdd(t *testing.T) {
 			},
 			ExpectedErr: errFailedWebhookPush,
 		},
+		{
+			Description: "PushItem fails with chrysom error",
+			PushItemResults: pushItemResults{
+				err: fmt.Errorf("%w: received status 400", chrysom.ErrBadRequest),
+			},
+			ExpectedErr: chrysom.ErrBadRequest,
+		},
 		{
 			Description: "Unknown push result",
 			PushItemResults: pushItemResults{
@@ -155,6 +172,257 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+func TestAddIDHasher(t *testing.T) {
+	inputWebhook := getTestInternalWebhooks()[0]
+	tcs := []struct {
+		desc       string
+		hasher     IDHasher
+		expectedID string
+	}{
+		{
+			desc:       "Default",
+			expectedID: SHA256IDHasher(inputWebhook.Webhook.Config.URL),
+		},
+		{
+			desc:       "Custom",
+			hasher:     func(url string) string { return "id-of-" + url },
+			expectedID: "id-of-" + inputWebhook.Webhook.Config.URL,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := new(mockPushReader)
+			svc := service{
+				logger: zap.NewNop(),
+				config: Config{IDHasher: tc.hasher},
+				argus:  m,
+				now:    time.Now,
+			}
+			// nolint:typecheck
+			m.On("PushItem", context.TODO(), "owner", mock.MatchedBy(func(item model.Item) bool {
+				return item.ID == tc.expectedID
+			})).Return(chrysom.CreatedPushResult, nil)
+			assert.NoError(t, svc.Add(context.TODO(), "owner", inputWebhook))
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddMaxItemSize(t *testing.T) {
+	inputWebhook := getTestInternalWebhooks()[0]
+	item, err := InternalWebhookToItem(time.Now, inputWebhook)
+	require.NoError(t, err)
+	data, err := json.Marshal(item.Data)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		desc        string
+		maxItemSize int
+		expectedErr error
+	}{
+		{
+			desc: "Default limit",
+		},
+		{
+			desc:        "At the limit",
+			maxItemSize: len(data),
+		},
+		{
+			desc:        "Over the limit",
+			maxItemSize: len(data) - 1,
+			expectedErr: ErrItemTooLarge,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := new(mockPushReader)
+			svc := service{
+				logger: zap.NewNop(),
+				config: Config{MaxItemSize: tc.maxItemSize},
+				argus:  m,
+				now:    time.Now,
+			}
+			if tc.expectedErr == nil {
+				// nolint:typecheck
+				m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(chrysom.CreatedPushResult, nil)
+			}
+			err := svc.Add(context.TODO(), "owner", inputWebhook)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	inputWebhook := getTestInternalWebhooks()[0]
+	inputItem, err := InternalWebhookToItem(time.Now, inputWebhook)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		desc          string
+		existing      chrysom.Items
+		getItemsErr   error
+		pushResult    chrysom.PushResult
+		expectedErr   error
+		expectPush    bool
+		expectWarning bool
+	}{
+		{
+			desc:        "Already exists",
+			existing:    chrysom.Items{{ID: "other"}, {ID: inputItem.ID}},
+			expectedErr: ErrWebhookExists,
+		},
+		{
+			desc:        "Fetching existing webhooks fails",
+			getItemsErr: errors.New("db failed"),
+			expectedErr: errFailedWebhooksFetch,
+		},
+		{
+			desc:       "Missing",
+			existing:   chrysom.Items{{ID: "other"}},
+			pushResult: chrysom.CreatedPushResult,
+			expectPush: true,
+		},
+		{
+			desc:          "Created concurrently",
+			pushResult:    chrysom.UpdatedPushResult,
+			expectPush:    true,
+			expectWarning: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockPushReader)
+			core, logs := observer.New(zap.WarnLevel)
+			svc := service{
+				logger: zap.New(core),
+				argus:  m,
+				now:    time.Now,
+			}
+			// nolint:typecheck
+			m.On("GetItems", context.TODO(), "owner").Return(tc.existing, tc.getItemsErr)
+			if tc.expectPush {
+				// nolint:typecheck
+				m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(tc.pushResult, nil)
+			}
+
+			err := svc.Create(context.TODO(), "owner", inputWebhook)
+			assert.ErrorIs(err, tc.expectedErr)
+			assert.Equal(tc.expectWarning, logs.Len() == 1)
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+// ownedItem returns the item with its owner set.
+func ownedItem(item model.Item, owner string) model.Item {
+	item.Owner = owner
+	return item
+}
+
+func TestGet(t *testing.T) {
+	items := getTestItems()
+	tcs := []struct {
+		desc          string
+		itemGetter    bool
+		item          model.Item
+		items         chrysom.Items
+		err           error
+		expected      InternalWebhook
+		expectedOwner string
+		expectedErr   error
+	}{
+		{
+			desc:       "Item getter",
+			itemGetter: true,
+			item:       items[0],
+			expected:   getTestInternalWebhooks()[0],
+		},
+		{
+			desc:          "Item getter owned",
+			itemGetter:    true,
+			item:          ownedItem(items[0], "owner"),
+			expected:      getTestInternalWebhooks()[0],
+			expectedOwner: "owner",
+		},
+		{
+			desc:        "Item getter owned by another",
+			itemGetter:  true,
+			item:        ownedItem(items[0], "someone-else"),
+			expectedErr: ErrWebhookNotOwned,
+		},
+		{
+			desc:        "Item getter not found",
+			itemGetter:  true,
+			err:         fmt.Errorf("%w: 404", chrysom.ErrItemNotFound),
+			expectedErr: ErrWebhookNotFound,
+		},
+		{
+			desc:        "Item getter failure",
+			itemGetter:  true,
+			err:         chrysom.ErrFailedAuthentication,
+			expectedErr: chrysom.ErrFailedAuthentication,
+		},
+		{
+			desc:        "Item conversion failure",
+			itemGetter:  true,
+			item:        model.Item{ID: "id", Data: map[string]interface{}{"PartnerIDs": "comcast"}},
+			expectedErr: errFailedItemConversion,
+		},
+		{
+			desc:     "Filtered from all items",
+			items:    items,
+			expected: getTestInternalWebhooks()[1],
+		},
+		{
+			desc:        "Not found in all items",
+			items:       chrysom.Items{items[0]},
+			expectedErr: ErrWebhookNotFound,
+		},
+		{
+			desc:        "Fetching all items failure",
+			err:         errors.New("db failed"),
+			expectedErr: errFailedWebhooksFetch,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockItemGetterPushReader)
+			svc := service{
+				logger: zap.NewNop(),
+				now:    time.Now,
+			}
+			id := tc.item.ID
+			if tc.itemGetter {
+				svc.argus = m
+				// nolint:typecheck
+				m.On("GetItem", context.TODO(), "owner", id).Return(tc.item, tc.err)
+			} else {
+				svc.argus = &m.mockPushReader
+				id = items[1].ID
+				// nolint:typecheck
+				m.On("GetItems", context.TODO(), "owner").Return(tc.items, tc.err)
+			}
+
+			iw, owner, err := svc.GetWithOwner(context.TODO(), "owner", id)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+			} else {
+				assert.NoError(err)
+				assert.Equal(tc.expected, iw)
+				assert.Equal(tc.expectedOwner, owner)
+			}
+			// nolint:typecheck
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAllInternalWebhooks(t *testing.T) {
 	type testCase struct {
 		Description              string
@@ -170,11 +438,26 @@ func TestAllInternalWebhooks(t *testing.T) {
 			GetItemsErr: errors.New("db failed"),
 			ExpectedErr: errFailedWebhooksFetch,
 		},
+		{
+			Description: "Fetching argus webhooks fails with chrysom error",
+			GetItemsErr: fmt.Errorf("%w: received status 404", chrysom.ErrItemNotFound),
+			ExpectedErr: chrysom.ErrItemNotFound,
+		},
+		{
+			Description:  "Item conversion fails",
+			GetItemsResp: chrysom.Items{{ID: "id", Data: map[string]interface{}{"PartnerIDs": "comcast"}}},
+			ExpectedErr:  errFailedItemConversion,
+		},
 		{
 			Description:              "Webhooks fetch success",
 			GetItemsResp:             getTestItems(),
 			ExpectedInternalWebhooks: getTestInternalWebhooks(),
 		},
+		{
+			Description:              "Webhooks sorted by item ID",
+			GetItemsResp:             chrysom.Items{getTestItems()[1], getTestItems()[0]},
+			ExpectedInternalWebhooks: getTestInternalWebhooks(),
+		},
 	}
 
 	for _, tc := range tcs {
@@ -228,7 +511,7 @@ func getTestItems() chrysom.Items {
 					"duration":    float64((10 * time.Second).Nanoseconds()),
 					"until":       "2021-01-02T15:04:10Z",
 				},
-				"PartnerIDs": []interface{}{"comcast"},
+				"partner_ids": []interface{}{"comcast"},
 			},
 
 			TTL: &firstItemExpiresInSecs,
@@ -257,3 +540,606 @@ func getTestItems() chrysom.Items {
 		},
 	}
 }
+
+func TestGetAllByOwner(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockPushReader)
+	svc := service{
+		logger: zap.NewNop(),
+		argus:  m,
+		now:    time.Now,
+	}
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "owner").Return(getTestItems(), nil)
+	iws, err := svc.GetAllByOwner(context.TODO(), "owner")
+	assert.NoError(err)
+	assert.Equal(getTestInternalWebhooks(), iws)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestGetAllWithReader(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockPushReader)
+	r := new(mockPushReader)
+	svc := service{
+		argus:  m,
+		reader: chrysom.NewMultiReader(r),
+		logger: zap.NewNop(),
+	}
+	// nolint:typecheck
+	r.On("GetItems", context.TODO(), "").Return(getTestItems(), nil)
+	iws, err := svc.GetAll(context.TODO())
+	assert.NoError(err)
+	assert.EqualValues(getTestInternalWebhooks(), iws)
+	// nolint:typecheck
+	r.AssertExpectations(t)
+	// nolint:typecheck
+	m.AssertNotCalled(t, "GetItems", mock.Anything, mock.Anything)
+}
+
+func TestGetPage(t *testing.T) {
+	items := getTestItems()
+	iws := getTestInternalWebhooks()
+	tcs := []struct {
+		desc         string
+		offset       int
+		limit        int
+		expected     []InternalWebhook
+		expectedMore bool
+	}{
+		{
+			desc:         "First page",
+			limit:        1,
+			expected:     iws[:1],
+			expectedMore: true,
+		},
+		{
+			desc:     "Last page",
+			offset:   1,
+			limit:    1,
+			expected: iws[1:],
+		},
+		{
+			desc:     "Everything",
+			limit:    10,
+			expected: iws,
+		},
+		{
+			desc:     "Out of range offset",
+			offset:   5,
+			limit:    1,
+			expected: []InternalWebhook{},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			m := new(mockPushReader)
+			svc := service{
+				argus:  m,
+				logger: zap.NewNop(),
+			}
+			// the page is sorted by ID whatever the order of the items.
+			reversed := slices.Clone(items)
+			slices.Reverse(reversed)
+			// nolint:typecheck
+			m.On("GetItems", context.TODO(), "owner").Return(reversed, nil)
+			page, more, err := svc.GetPage(context.TODO(), "owner", tc.offset, tc.limit)
+			assert.NoError(err)
+			assert.Equal(tc.expected, page)
+			assert.Equal(tc.expectedMore, more)
+		})
+	}
+}
+
+type bulkPushReader struct {
+	mockPushReader
+	results []chrysom.PushResult
+	err     error
+}
+
+func (b *bulkPushReader) PushItems(context.Context, string, []model.Item) ([]chrysom.PushResult, error) {
+	return b.results, b.err
+}
+
+func TestAddBatch(t *testing.T) {
+	iws := getTestInternalWebhooks()
+	third := iws[1]
+	third.Webhook.Config.URL = "http://deliver-here-2.example.net"
+	iws = append(iws, third)
+
+	t.Run("Sequential", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		m := new(mockPushReader)
+		svc := service{logger: zap.NewNop(), argus: m, now: time.Now}
+		// nolint:typecheck
+		m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(chrysom.CreatedPushResult, nil).Once()
+		// nolint:typecheck
+		m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(chrysom.NilPushResult, errors.New("push failed")).Once()
+		// nolint:typecheck
+		m.On("PushItem", context.TODO(), "owner", mock.Anything).Return(chrysom.UnknownPushResult, nil).Once()
+
+		err := svc.AddBatch(context.TODO(), "owner", iws)
+		var batchErr *chrysom.BatchError
+		require.ErrorAs(err, &batchErr)
+		assert.Equal([]int{1, 2}, batchErr.Indices())
+		assert.ErrorIs(batchErr.Errs[1], errFailedWebhookPush)
+		assert.ErrorIs(batchErr.Errs[2], errNonSuccessPushResult)
+		// nolint:typecheck
+		m.AssertExpectations(t)
+	})
+
+	t.Run("Bulk", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+		m := &bulkPushReader{
+			results: []chrysom.PushResult{chrysom.UpdatedPushResult, chrysom.NilPushResult, chrysom.CreatedPushResult},
+			err:     &chrysom.BatchError{Errs: map[int]error{1: chrysom.ErrBadRequest}},
+		}
+		svc := service{logger: zap.NewNop(), argus: m, now: time.Now}
+
+		err := svc.AddBatch(context.TODO(), "owner", iws)
+		var batchErr *chrysom.BatchError
+		require.ErrorAs(err, &batchErr)
+		assert.Equal([]int{1}, batchErr.Indices())
+		assert.ErrorIs(err, chrysom.ErrBadRequest)
+
+		m.err = nil
+		m.results[1] = chrysom.CreatedPushResult
+		assert.NoError(svc.AddBatch(context.TODO(), "owner", iws))
+
+		m.err = errors.New("unreachable")
+		assert.ErrorIs(svc.AddBatch(context.TODO(), "owner", iws), errFailedWebhookPush)
+	})
+}
+
+func TestGetAllItems(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockPushReader)
+	svc := service{
+		argus:  m,
+		logger: zap.NewNop(),
+	}
+	items := chrysom.Items{{ID: "id", Data: map[string]interface{}{"PartnerIDs": "comcast"}}}
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "owner").Return(items, nil).Once()
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "").Return(chrysom.Items(nil), errors.New("unreachable")).Once()
+
+	got, err := svc.GetAllItems(context.TODO(), "owner")
+	assert.NoError(err)
+	assert.Equal(items, got)
+	_, err = svc.GetAllItems(context.TODO(), "")
+	assert.ErrorIs(err, errFailedWebhooksFetch)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestCount(t *testing.T) {
+	assert := assert.New(t)
+	m := new(mockPushReader)
+	svc := service{
+		argus:  m,
+		logger: zap.NewNop(),
+	}
+	items := chrysom.Items{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "owner").Return(items, nil).Twice()
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "").Return(chrysom.Items(nil), errors.New("unreachable")).Once()
+
+	n, err := svc.Count(context.TODO(), "owner", 0)
+	assert.NoError(err)
+	assert.Equal(3, n)
+	n, err = svc.Count(context.TODO(), "owner", 2)
+	assert.NoError(err)
+	assert.Equal(2, n)
+	_, err = svc.Count(context.TODO(), "", 0)
+	assert.ErrorIs(err, errFailedWebhooksFetch)
+	// nolint:typecheck
+	m.AssertExpectations(t)
+}
+
+func TestGetAllItemConversionErrorChain(t *testing.T) {
+	m := new(mockPushReader)
+	svc := service{
+		argus:  m,
+		logger: zap.NewNop(),
+	}
+	// nolint:typecheck
+	m.On("GetItems", context.TODO(), "").Return(chrysom.Items{{ID: "id", Data: map[string]interface{}{"PartnerIDs": "comcast"}}}, nil)
+	_, err := svc.GetAll(context.TODO())
+	var typeErr *json.UnmarshalTypeError
+	require.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "PartnerIDs", typeErr.Field)
+}
+
+func TestPrepArgusListenerClientConfigWatchPanic(t *testing.T) {
+	assert := assert.New(t)
+	core, logs := observer.New(zap.ErrorLevel)
+	cfg := ListenerConfig{
+		Logger:   zap.New(core),
+		Measures: Measures{WebhookListSizeGaugeName: prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"})},
+	}
+	var updated []InternalWebhook
+	prepArgusListenerClientConfig(&cfg,
+		WatchFunc(func([]InternalWebhook) { panic("watch bug") }),
+		WatchFunc(func(iws []InternalWebhook) { updated = iws }),
+	)
+
+	assert.NotPanics(func() { cfg.Config.Listener.Update(getTestItems()) })
+	assert.Equal(getTestInternalWebhooks(), updated)
+	entries := logs.FilterMessage("Webhook watch panicked").All()
+	if assert.Len(entries, 1) {
+		assert.Equal("watch bug", entries[0].ContextMap()["panic"])
+		assert.Contains(entries[0].ContextMap()["stack"], "safeUpdate")
+	}
+}
+
+func TestPrepArgusListenerClientConfigWatchOrder(t *testing.T) {
+	cfg := ListenerConfig{
+		Logger:   zap.NewNop(),
+		Measures: Measures{WebhookListSizeGaugeName: prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"})},
+	}
+	var updated []InternalWebhook
+	prepArgusListenerClientConfig(&cfg, WatchFunc(func(iws []InternalWebhook) { updated = iws }))
+
+	items := getTestItems()
+	slices.Reverse(items)
+	cfg.Config.Listener.Update(items)
+	assert.Equal(t, getTestInternalWebhooks(), updated)
+	assert.Equal(t, getTestItems()[1].ID, items[0].ID, "the polled items must not be reordered")
+}
+
+type recordingMetaWatch struct {
+	metas    []chrysom.UpdateMeta
+	webhooks [][]InternalWebhook
+}
+
+func (w *recordingMetaWatch) Update([]InternalWebhook) {
+	panic("Update called on a MetaWatch")
+}
+
+func (w *recordingMetaWatch) UpdateWithMeta(meta chrysom.UpdateMeta, webhooks []InternalWebhook) {
+	w.metas = append(w.metas, meta)
+	w.webhooks = append(w.webhooks, webhooks)
+}
+
+func TestPrepArgusListenerClientConfigMetaWatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	cfg := ListenerConfig{
+		Logger:   zap.NewNop(),
+		Measures: Measures{WebhookListSizeGaugeName: prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"})},
+	}
+	metaWatch := &recordingMetaWatch{}
+	var updates int
+	prepArgusListenerClientConfig(&cfg,
+		metaWatch,
+		WatchFunc(func([]InternalWebhook) { updates++ }),
+	)
+	listener, ok := cfg.Config.Listener.(chrysom.MetaListener)
+	require.True(ok)
+
+	success := chrysom.UpdateMeta{Outcome: chrysom.SuccessOutcome, PollSeq: 1, FetchedAt: getRefTime()}
+	failure := chrysom.UpdateMeta{Outcome: chrysom.FailureOutcome, PollSeq: 2, FetchedAt: getRefTime()}
+	unchanged := chrysom.UpdateMeta{Outcome: chrysom.SuccessOutcome, PollSeq: 3, FetchedAt: getRefTime(), Unchanged: true}
+	listener.UpdateWithMeta(success, getTestItems())
+	listener.UpdateWithMeta(failure, nil)
+	listener.UpdateWithMeta(unchanged, getTestItems())
+
+	assert.Equal([]chrysom.UpdateMeta{success, failure, unchanged}, metaWatch.metas)
+	assert.Equal([][]InternalWebhook{getTestInternalWebhooks(), nil, getTestInternalWebhooks()}, metaWatch.webhooks)
+	assert.Equal(1, updates)
+}
+
+func TestPrepArgusListenerClientConfigWatchTimeout(t *testing.T) {
+	assert := assert.New(t)
+	core, logs := observer.New(zap.ErrorLevel)
+	timeouts := prometheus.NewCounter(prometheus.CounterOpts{Name: "timeouts"})
+	cfg := ListenerConfig{
+		Logger: zap.New(core),
+		Measures: Measures{
+			WebhookListSizeGaugeName: prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"}),
+			WatcherTimeouts:          timeouts,
+		},
+		WatchTimeout: 10 * time.Millisecond,
+	}
+	release := make(chan struct{})
+	defer close(release)
+	updated := make(chan []InternalWebhook, 2)
+	prepArgusListenerClientConfig(&cfg,
+		WatchFunc(func([]InternalWebhook) { <-release }),
+		WatchFunc(func(iws []InternalWebhook) { updated <- iws }),
+	)
+
+	cfg.Config.Listener.Update(getTestItems())
+	cfg.Config.Listener.Update(getTestItems())
+
+	assert.Len(updated, 2)
+	assert.Equal(getTestInternalWebhooks(), <-updated)
+	assert.Equal(float64(2), counterValue(t, timeouts))
+	assert.Len(logs.FilterMessage("Webhook watch timed out, continuing with the remaining watches").All(), 2)
+}
+
+// delayedReader is a chrysom.Reader that takes delay to respond.
+type delayedReader struct {
+	delay time.Duration
+	items chrysom.Items
+	err   error
+}
+
+func (r delayedReader) GetItems(ctx context.Context, _ string) (chrysom.Items, error) {
+	time.Sleep(r.delay)
+	return r.items, r.err
+}
+
+func TestStartListenerWaitForInitialUpdate(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		reader      delayedReader
+		fatal       bool
+		expectedErr error
+		expectWarn  bool
+	}{
+		{
+			desc:   "Initial update received",
+			reader: delayedReader{delay: 20 * time.Millisecond, items: getTestItems()},
+			fatal:  true,
+		},
+		{
+			desc:        "Timeout fatal",
+			reader:      delayedReader{err: errors.New("argus down")},
+			fatal:       true,
+			expectedErr: ErrInitialUpdateTimeout,
+		},
+		{
+			desc:       "Timeout lenient",
+			reader:     delayedReader{err: errors.New("argus down")},
+			expectWarn: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			core, logs := observer.New(zap.WarnLevel)
+			svc := &service{
+				logger: zap.NewNop(),
+				argus:  new(mockPushReader),
+				reader: tc.reader,
+				now:    time.Now,
+			}
+			wait := 200 * time.Millisecond
+			if tc.expectedErr != nil || tc.expectWarn {
+				wait = 50 * time.Millisecond
+			}
+			var updates []InternalWebhook
+			var mu sync.Mutex
+			stop, err := svc.StartListener(ListenerConfig{
+				Config: chrysom.ListenerClientConfig{PullInterval: 5 * time.Millisecond},
+				Logger: zap.New(core),
+				Measures: Measures{
+					WebhookListSizeGaugeName:     prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"}),
+					ChrysomPollsTotalCounterName: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel}),
+				},
+				WaitForInitialUpdate:       wait,
+				FailOnInitialUpdateTimeout: tc.fatal,
+			}, nil, WatchFunc(func(iws []InternalWebhook) {
+				mu.Lock()
+				defer mu.Unlock()
+				updates = iws
+			}))
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+				assert.Nil(stop)
+				return
+			}
+			require.NoError(err)
+			defer stop()
+
+			assert.Equal(tc.expectWarn, logs.FilterMessage("Timed out waiting for the initial webhook update, continuing without it").Len() == 1)
+			if !tc.expectWarn {
+				mu.Lock()
+				defer mu.Unlock()
+				assert.Equal(getTestInternalWebhooks(), updates)
+			}
+		})
+	}
+}
+
+func TestStartListenerPollMetrics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	r := new(mockPushReader)
+	// nolint:typecheck
+	r.On("GetItems", mock.Anything, "").Return(getTestItems(), nil)
+	svc := &service{
+		logger: zap.NewNop(),
+		argus:  r,
+		now:    time.Now,
+	}
+	var (
+		duration   = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "duration"}, []string{OutcomeLabel})
+		lastPoll   = prometheus.NewGauge(prometheus.GaugeOpts{Name: "last_poll"})
+		fetched    = prometheus.NewGauge(prometheus.GaugeOpts{Name: "fetched"})
+		pollStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "status"}, []string{chrysom.KindLabel})
+	)
+	stop, err := svc.StartListener(ListenerConfig{
+		Config: chrysom.ListenerClientConfig{PullInterval: 5 * time.Millisecond},
+		Measures: Measures{
+			WebhookListSizeGaugeName:     prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"}),
+			ChrysomPollsTotalCounterName: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel}),
+			ChrysomPollDuration:          duration,
+			ChrysomLastSuccessfulPoll:    lastPoll,
+			ChrysomItemsFetched:          fetched,
+			ChrysomLastPollStatus:        pollStatus,
+		},
+		WaitForInitialUpdate:       time.Second,
+		FailOnInitialUpdateTimeout: true,
+	}, nil)
+	require.NoError(err)
+	// stopping waits for the poll to be observed.
+	stop()
+
+	var m dto.Metric
+	require.NoError(duration.WithLabelValues(SuccessOutcome).(prometheus.Metric).Write(&m))
+	assert.NotZero(m.GetHistogram().GetSampleCount())
+	require.NoError(lastPoll.Write(&m))
+	assert.NotZero(m.GetGauge().GetValue())
+	require.NoError(fetched.Write(&m))
+	assert.Equal(float64(len(getTestItems())), m.GetGauge().GetValue())
+	require.NoError(pollStatus.WithLabelValues(chrysom.OKPollKind).Write(&m))
+	assert.Equal(float64(1), m.GetGauge().GetValue())
+}
+
+func TestGetAllSharesConditionalClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var (
+		mu          sync.Mutex
+		ifNoneMatch []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(rw).Encode(getTestItems())
+	}))
+	defer server.Close()
+
+	svc, err := NewService(Config{
+		BasicClientConfig: chrysom.BasicClientConfig{
+			Address:             server.URL,
+			Bucket:              "test",
+			ConditionalRequests: true,
+		},
+	}, nil)
+	require.NoError(err)
+	stop, err := svc.StartListener(ListenerConfig{
+		Config: chrysom.ListenerClientConfig{PullInterval: 5 * time.Millisecond},
+		Measures: Measures{
+			WebhookListSizeGaugeName:     prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"}),
+			ChrysomPollsTotalCounterName: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel}),
+		},
+		WaitForInitialUpdate:       time.Second,
+		FailOnInitialUpdateTimeout: true,
+	}, nil)
+	require.NoError(err)
+	stop()
+	mu.Lock()
+	ifNoneMatch = nil
+	mu.Unlock()
+
+	// the listener's poll remembered the ETag, but GetAll doesn't send it.
+	iws, err := svc.GetAll(context.Background())
+	require.NoError(err)
+	assert.Equal(getTestInternalWebhooks(), iws)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal([]string{""}, ifNoneMatch)
+}
+
+// countingReader counts the GetItems calls of a chrysom.Reader.
+type countingReader struct {
+	chrysom.Reader
+	calls atomic.Int32
+}
+
+func (r *countingReader) GetItems(ctx context.Context, owner string) (chrysom.Items, error) {
+	r.calls.Add(1)
+	return r.Reader.GetItems(ctx, owner)
+}
+
+func TestStartListenerRefreshAfterAdd(t *testing.T) {
+	newWebhook := func(url string) InternalWebhook {
+		return InternalWebhook{Webhook: Webhook{
+			Config: DeliveryConfig{URL: url},
+			Until:  time.Now().Add(time.Hour),
+		}}
+	}
+	tcs := []struct {
+		desc            string
+		refreshAfterAdd bool
+		addFromWatch    bool
+	}{
+		{
+			desc:            "Refresh after add",
+			refreshAfterAdd: true,
+		},
+		{
+			desc:            "Add from a watch",
+			refreshAfterAdd: true,
+			addFromWatch:    true,
+		},
+		{
+			desc: "Disabled",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			store := new(memoryPushReader)
+			reader := &countingReader{Reader: store}
+			svc := &service{
+				logger:    zap.NewNop(),
+				argus:     store,
+				reader:    reader,
+				now:       time.Now,
+				refresher: new(addRefresher),
+			}
+			var (
+				mu      sync.Mutex
+				updates [][]InternalWebhook
+			)
+			stop, err := svc.StartListener(ListenerConfig{
+				Config: chrysom.ListenerClientConfig{PullInterval: time.Hour},
+				Measures: Measures{
+					WebhookListSizeGaugeName:     prometheus.NewGauge(prometheus.GaugeOpts{Name: "size"}),
+					ChrysomPollsTotalCounterName: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "polls"}, []string{OutcomeLabel}),
+				},
+				RefreshAfterAdd: tc.refreshAfterAdd,
+				RefreshDebounce: time.Millisecond,
+			}, nil, WatchFunc(func(iws []InternalWebhook) {
+				mu.Lock()
+				updates = append(updates, iws)
+				n := len(updates)
+				mu.Unlock()
+				if tc.addFromWatch {
+					assert.NoError(svc.Add(context.Background(), "owner", newWebhook(fmt.Sprintf("https://watch%d.example.com", n))))
+				}
+			}))
+			require.NoError(err)
+			defer stop()
+
+			require.NoError(svc.Add(context.Background(), "owner", newWebhook("https://added.example.com")))
+			if !tc.refreshAfterAdd {
+				time.Sleep(50 * time.Millisecond)
+				assert.Zero(reader.calls.Load())
+				return
+			}
+			assert.Eventually(func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(updates) > 0 && slices.ContainsFunc(updates[0], func(iw InternalWebhook) bool {
+					return iw.Webhook.Config.URL == "https://added.example.com"
+				})
+			}, time.Second, time.Millisecond)
+
+			// an add made by a watch during the refresh doesn't trigger another.
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(int32(1), reader.calls.Load())
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Len(updates, 1)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xmidt-org/ancla/chrysom"
+)
+
+// Snapshot is a Watch that holds on to the latest list of webhooks so it can be
+// served when the store is unreachable. The zero value is ready to use.
+type Snapshot struct {
+	// Now is the snapshot's clock.
+	// (Optional). Defaults to time.Now.
+	Now func() time.Time
+
+	mu        sync.RWMutex
+	webhooks  []InternalWebhook
+	updatedAt time.Time
+	ok        bool
+}
+
+// Update replaces the snapshot with the given webhooks.
+func (s *Snapshot) Update(webhooks []InternalWebhook) {
+	s.set(webhooks, s.now())
+}
+
+// UpdateWithMeta replaces the snapshot with the webhooks of a successful poll,
+// aging them from when they were fetched. Failed polls leave the snapshot as is.
+func (s *Snapshot) UpdateWithMeta(meta chrysom.UpdateMeta, webhooks []InternalWebhook) {
+	if meta.Outcome != chrysom.SuccessOutcome {
+		return
+	}
+	fetchedAt := meta.FetchedAt
+	if fetchedAt.IsZero() {
+		fetchedAt = s.now()
+	}
+	s.set(webhooks, fetchedAt)
+}
+
+func (s *Snapshot) set(webhooks []InternalWebhook, updatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhooks = append([]InternalWebhook{}, webhooks...)
+	s.updatedAt = updatedAt
+	s.ok = true
+}
+
+// Latest returns the webhooks of the latest update and how long ago it happened.
+// It returns false if there hasn't been an update yet.
+func (s *Snapshot) Latest() ([]InternalWebhook, time.Duration, bool) {
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.ok {
+		return nil, 0, false
+	}
+	return append([]InternalWebhook{}, s.webhooks...), now.Sub(s.updatedAt), true
+}
+
+func (s *Snapshot) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
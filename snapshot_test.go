@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/ancla/chrysom"
+)
+
+func TestSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	now := getRefTime()
+	s := &Snapshot{Now: func() time.Time { return now }}
+
+	_, _, ok := s.Latest()
+	assert.False(ok)
+
+	iws := getTestInternalWebhooks()
+	s.Update(iws)
+	now = now.Add(time.Minute)
+	iws[0] = InternalWebhook{}
+
+	latest, age, ok := s.Latest()
+	assert.True(ok)
+	assert.Equal(time.Minute, age)
+	assert.Equal(getTestInternalWebhooks(), latest)
+
+	s.Update(nil)
+	latest, age, ok = s.Latest()
+	assert.True(ok)
+	assert.Zero(age)
+	assert.Empty(latest)
+}
+
+func TestSnapshotUpdateWithMeta(t *testing.T) {
+	assert := assert.New(t)
+	now := getRefTime()
+	s := &Snapshot{Now: func() time.Time { return now }}
+
+	s.UpdateWithMeta(chrysom.UpdateMeta{Outcome: chrysom.FailureOutcome, FetchedAt: now}, nil)
+	_, _, ok := s.Latest()
+	assert.False(ok)
+
+	s.UpdateWithMeta(chrysom.UpdateMeta{Outcome: chrysom.SuccessOutcome, FetchedAt: now.Add(-time.Minute)}, getTestInternalWebhooks())
+	s.UpdateWithMeta(chrysom.UpdateMeta{Outcome: chrysom.FailureOutcome, FetchedAt: now}, nil)
+	latest, age, ok := s.Latest()
+	assert.True(ok)
+	assert.Equal(time.Minute, age)
+	assert.Equal(getTestInternalWebhooks(), latest)
+}
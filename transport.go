@@ -8,12 +8,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/xmidt-org/ancla/auth"
+	"github.com/xmidt-org/ancla/chrysom"
 	"github.com/xmidt-org/httpaux/erraux"
 	"go.uber.org/zap"
 )
@@ -21,47 +28,447 @@ import (
 var (
 	errFailedWebhookUnmarshal    = errors.New("failed to JSON unmarshal webhook")
 	errGettingPartnerIDs         = errors.New("unable to retrieve PartnerIDs")
+	errNoPartnerIDs              = errors.New("no valid PartnerIDs")
+	errWebhookIDMissing          = errors.New("webhook ID is required")
+	errPrincipalMissing          = errors.New("unable to retrieve principal")
+	errValidationBudgetExceeded  = errors.New("webhook validation exceeded its time budget")
+	errStoreBudgetExceeded       = errors.New("webhook store exceeded its time budget")
+	errCreateOnlyNotSupported    = errors.New("create only adds are not supported")
+	errValidatorPanic            = errors.New("webhook validator panicked")
+	errUnsupportedContentType    = errors.New("unsupported content type")
+	errMissingContentType        = errors.New("missing content type")
+	errInvalidIncludeRaw         = errors.New("invalid include_raw")
+	errRawNotAllowed             = errors.New("include_raw is only allowed for admins")
+	errRawNotSupported           = errors.New("include_raw is not supported")
+	errInvalidPage               = errors.New("invalid page")
+	errPagingNotSupported        = errors.New("paging is not supported")
+	errInvalidBreakGlass         = errors.New("invalid break glass header")
+	errBreakGlassNotAllowed      = errors.New("break glass is not allowed")
+	errExtensionRequired         = errors.New("until or a positive duration is required")
 	DefaultBasicPartnerIDsHeader = "X-Xmidt-Partner-Ids"
 )
 
 const (
 	contentTypeHeader string = "Content-Type"
 	jsonContentType   string = "application/json"
+	staleHeader       string = "X-Ancla-Stale"
+	ageHeader         string = "Age"
+	linkHeader        string = "Link"
+	breakGlassHeader  string = "X-Ancla-Break-Glass"
 )
 
+// maxErrorMessageLength caps the bytes of each message in error responses.
+const maxErrorMessageLength = 4096
+
+// internalErrorMessage is the message of 5xx error responses whose error isn't
+// a SanitizedError.
+const internalErrorMessage = "internal error"
+
 type transportConfig struct {
 	now                   func() time.Time
 	v                     Validator
+	defaults              DefaultsPolicy
+	validationTimeout     time.Duration
+	acceptEpochUntil      bool
+	requireContentType    bool
+	lowercasePartnerIDs   bool
+	getLogger             func(context.Context) *zap.Logger
 	basicPartnerIDsHeader string
 	disablePartnerIDs     bool
+	breakGlass            func(*http.Request) bool
+	breakGlassUses        prometheus.Counter
+	rejectedURLs          *rejectionCache
+	addRequestBytes       prometheus.ObserverVec
+	disableCompression    bool
+	// maxBodyBytes caps the body of add requests, 0 for no cap.
+	maxBodyBytes   int64
+	strictDecoding bool
+}
+
+// logger returns the request's logger, falling back to a no op logger.
+func (c transportConfig) logger(ctx context.Context) *zap.Logger {
+	if c.getLogger != nil {
+		if logger := c.getLogger(ctx); logger != nil {
+			return logger
+		}
+	}
+	return zap.NewNop()
+}
+
+// staleWebhooksResponse is a get all response served from a snapshot because
+// the store couldn't be reached.
+type staleWebhooksResponse struct {
+	webhooks []InternalWebhook
+	age      time.Duration
+}
+
+// rawWebhooksResponse is a get all response listing the stored items, each
+// along with the webhook decoded from it.
+type rawWebhooksResponse struct {
+	items chrysom.Items
+}
+
+// rawWebhookEntry is an entry of a rawWebhooksResponse. Webhook is nil and
+// Error is set when the item doesn't decode to a webhook.
+type rawWebhookEntry struct {
+	ID      string                 `json:"id"`
+	Webhook interface{}            `json:"webhook"`
+	Error   string                 `json:"error,omitempty"`
+	Raw     map[string]interface{} `json:"raw"`
+}
+
+// webhooksPageResponse is a get all response listing a page of the webhooks.
+type webhooksPageResponse struct {
+	webhooks []InternalWebhook
+	page     *webhooksPage
+	more     bool
+}
+
+// webhooksPage is the page of webhooks requested with the get all limit and
+// offset query parameters.
+type webhooksPage struct {
+	offset int
+	limit  int
+
+	// url is the request's URL, from which the link to the next page is made.
+	url *url.URL
+}
+
+// next returns the link to the page following the given number of webhooks.
+func (p *webhooksPage) next(n int) string {
+	next := *p.url
+	query := next.Query()
+	query.Set("offset", strconv.Itoa(p.offset+n))
+	query.Set("limit", strconv.Itoa(p.limit))
+	next.RawQuery = query.Encode()
+	return fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI())
+}
+
+// projectedWebhooksResponse is a get all response with only the selected fields
+// of each webhook.
+type projectedWebhooksResponse struct {
+	response interface{}
+	fields   []string
+}
+
+// getAllWebhooksRequest scopes a get all request to an owner, selects the
+// fields of the response, asks for the stored items along with the webhooks
+// or asks for a page of the webhooks.
+type getAllWebhooksRequest struct {
+	owner      string
+	fields     []string
+	includeRaw bool
+	page       *webhooksPage
+}
+
+type getWebhookRequest struct {
+	owner string
+	id    string
+}
+
+// extendWebhookRequest extends the webhook with the ID to the given until, or
+// else to the given duration from now.
+type extendWebhookRequest struct {
+	owner    string
+	id       string
+	until    time.Time
+	duration time.Duration
+}
+
+// extendWebhookBody is the body of an extend request.
+type extendWebhookBody struct {
+	Duration CustomDuration `json:"duration"`
+	Until    time.Time      `json:"until"`
 }
 
 type addWebhookRequest struct {
 	owner          string
 	internalWebook InternalWebhook
+	createOnly     bool
+}
+
+// addWebhookResponse is the webhook stored by an add request, with the
+// defaults filled in.
+type addWebhookResponse struct {
+	webhook Webhook
+	created bool
 }
 
+// encodeGetAllWebhooksResponse encodes the webhooks in the order of the endpoint,
+// which is by item ID unless HandlerConfig.StoreOrder is set, so that the
+// response and its ETag don't change between calls unless the webhooks do. Raw
+// entries are sorted by item ID. A request whose If-None-Match matches the ETag
+// gets a 304 without a body.
 func encodeGetAllWebhooksResponse(ctx context.Context, rw http.ResponseWriter, response interface{}) error {
-	iws := response.([]InternalWebhook)
+	return encodeWebhookList(ctx, rw, response, true)
+}
+
+// encodeGetAllWebhooksResponseInStoreOrder is encodeGetAllWebhooksResponse,
+// leaving raw entries in the order the store returned them.
+func encodeGetAllWebhooksResponseInStoreOrder(ctx context.Context, rw http.ResponseWriter, response interface{}) error {
+	return encodeWebhookList(ctx, rw, response, false)
+}
+
+func encodeWebhookList(ctx context.Context, rw http.ResponseWriter, response interface{}, sortRaw bool) error {
+	var fields []string
+	if p, ok := response.(*projectedWebhooksResponse); ok {
+		response, fields = p.response, p.fields
+	}
+	if r, ok := response.(*rawWebhooksResponse); ok {
+		encoded, err := json.Marshal(rawWebhookEntries(r.items, fields, sortRaw))
+		if err != nil {
+			return err
+		}
+		return writeWebhookList(ctx, rw, encoded)
+	}
+	var iws []InternalWebhook
+	switch r := response.(type) {
+	case []InternalWebhook:
+		iws = r
+	case *staleWebhooksResponse:
+		iws = r.webhooks
+		rw.Header().Set(staleHeader, "true")
+		rw.Header().Set(ageHeader, strconv.FormatInt(int64(r.age/time.Second), 10))
+	case *webhooksPageResponse:
+		iws = r.webhooks
+		if r.more {
+			rw.Header().Set(linkHeader, r.page.next(len(r.webhooks)))
+		}
+	}
 	webhooks := InternalWebhooksToWebhooks(iws)
 	if webhooks == nil {
 		// prefer JSON output to be "[]" instead of "<nil>"
 		webhooks = []Webhook{}
 	}
 	obfuscateSecrets(webhooks)
-	encodedWebhooks, err := json.Marshal(&webhooks)
+	plainUntils(webhooks)
+	var encodedWebhooks []byte
+	var err error
+	if len(fields) > 0 {
+		encodedWebhooks, err = json.Marshal(projectWebhooks(webhooks, fields))
+	} else {
+		encodedWebhooks, err = json.Marshal(&webhooks)
+	}
 	if err != nil {
 		return err
 	}
+	return writeWebhookList(ctx, rw, encodedWebhooks)
+}
 
+// rawWebhookEntries decodes the webhooks of the items, obfuscated like the
+// other get all responses and with only the given fields if any, and pairs
+// them with the redacted data of their items. When sorted is true, the entries
+// are sorted by item ID.
+func rawWebhookEntries(items chrysom.Items, fields []string, sorted bool) []rawWebhookEntry {
+	if sorted {
+		items = items.Sorted()
+	}
+	entries := make([]rawWebhookEntry, 0, len(items))
+	for _, item := range items {
+		entry := rawWebhookEntry{ID: item.ID, Raw: redactItemData(item.Data)}
+		iw, err := ItemToInternalWebhook(item)
+		if err != nil {
+			entry.Error = err.Error()
+			entries = append(entries, entry)
+			continue
+		}
+		webhooks := []Webhook{iw.Webhook}
+		obfuscateSecrets(webhooks)
+		plainUntils(webhooks)
+		entry.Webhook = webhooks[0]
+		if len(fields) > 0 {
+			entry.Webhook = projectWebhooks(webhooks, fields)[0]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// writeWebhookList writes the encoded get all response, handling its ETag and
+// gzip encoding.
+func writeWebhookList(ctx context.Context, rw http.ResponseWriter, encodedWebhooks []byte) error {
+	var err error
+	etag := computeETag(encodedWebhooks)
+	if gzipWanted(ctx) {
+		rw.Header().Add(varyHeader, acceptEncodingHeader)
+		if encodedWebhooks, err = gzipBytes(encodedWebhooks); err != nil {
+			return err
+		}
+		// the gzip representation needs its own strong ETag.
+		etag = strings.TrimSuffix(etag, `"`) + `-gzip"`
+		rw.Header().Set(contentEncodingHeader, gzipEncoding)
+	}
+	rw.Header().Set(etagHeader, etag)
+	if etagMatches(ctx, etag) {
+		rw.Header().Del(contentEncodingHeader)
+		rw.WriteHeader(http.StatusNotModified)
+		return nil
+	}
 	rw.Header().Set(contentTypeHeader, jsonContentType)
 	_, err = rw.Write(encodedWebhooks)
 	return err
 }
 
+// getAllWebhooksRequestDecoder decodes get all requests, scoping them to the
+// request's principal when config.GetAllByOwner is true and selecting the
+// response fields given by the "fields" query parameter. The "include_raw"
+// query parameter is only allowed for the requests config.IsAdmin accepts, and
+// for none if it's nil. The "limit" and "offset" query parameters ask for a page
+// of the webhooks. Unscoped requests for every webhook and field without the
+// stored items decode to nil.
+func getAllWebhooksRequestDecoder(config HandlerConfig) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		query := r.URL.Query()
+		fields, err := parseWebhookFields(query.Get("fields"))
+		if err != nil {
+			return nil, &erraux.Error{Err: err, Code: http.StatusBadRequest}
+		}
+		var includeRaw bool
+		if v := query.Get("include_raw"); v != "" {
+			if includeRaw, err = strconv.ParseBool(v); err != nil {
+				return nil, &erraux.Error{Err: fmt.Errorf("%w: %q", errInvalidIncludeRaw, v), Code: http.StatusBadRequest}
+			}
+		}
+		if includeRaw && (config.IsAdmin == nil || !config.IsAdmin(r)) {
+			return nil, &erraux.Error{Err: errRawNotAllowed, Code: http.StatusForbidden}
+		}
+		page, err := parseWebhooksPage(r.URL, config.defaultPageLimit())
+		if err != nil {
+			return nil, &erraux.Error{Err: err, Code: http.StatusBadRequest}
+		}
+		if page != nil && includeRaw {
+			return nil, &erraux.Error{Err: fmt.Errorf("%w: include_raw can't be paged", errInvalidPage), Code: http.StatusBadRequest}
+		}
+		if !config.GetAllByOwner {
+			if len(fields) == 0 && !includeRaw && page == nil {
+				return nil, nil
+			}
+			return &getAllWebhooksRequest{fields: fields, includeRaw: includeRaw, page: page}, nil
+		}
+		owner, ok := auth.GetPrincipal(r.Context())
+		if !ok || owner == "" {
+			return nil, &erraux.Error{Err: errPrincipalMissing, Code: http.StatusForbidden}
+		}
+		return &getAllWebhooksRequest{owner: owner, fields: fields, includeRaw: includeRaw, page: page}, nil
+	}
+}
+
+// parseWebhooksPage parses the "limit" and "offset" query parameters of the
+// URL, returning nil when neither is given. A missing or 0 limit falls back to
+// defaultLimit.
+func parseWebhooksPage(u *url.URL, defaultLimit int) (*webhooksPage, error) {
+	query := u.Query()
+	if !query.Has("limit") && !query.Has("offset") {
+		return nil, nil
+	}
+	page := webhooksPage{url: u}
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"limit", &page.limit}, {"offset", &page.offset}} {
+		v := query.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %s must be a non negative integer, got %q", errInvalidPage, p.name, v)
+		}
+		*p.value = n
+	}
+	if page.limit == 0 {
+		page.limit = defaultLimit
+	}
+	return &page, nil
+}
+
+// getWebhookRequestDecoder decodes the ID from the request's "id" path value,
+// as set by NewRouter, falling back to the last path segment.
+func getWebhookRequestDecoder(_ context.Context, r *http.Request) (interface{}, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		id = path.Base(r.URL.Path)
+	}
+	if id == "" || id == "/" || id == "." {
+		return nil, &erraux.Error{Err: errWebhookIDMissing, Code: http.StatusBadRequest}
+	}
+	owner, _ := auth.GetPrincipal(r.Context())
+	return &getWebhookRequest{owner: owner, id: id}, nil
+}
+
+// extendWebhookRequestDecoder decodes extend requests, taking the ID like
+// getWebhookRequestDecoder and the until or duration from the JSON body.
+// Requests without a principal are refused with a 403.
+func extendWebhookRequestDecoder(config transportConfig) kithttp.DecodeRequestFunc {
+	return func(c context.Context, r *http.Request) (interface{}, error) {
+		get, err := getWebhookRequestDecoder(c, r)
+		if err != nil {
+			return nil, err
+		}
+		g := get.(*getWebhookRequest)
+		if g.owner == "" {
+			return nil, &erraux.Error{Err: errPrincipalMissing, Code: http.StatusForbidden}
+		}
+		if err = checkContentType(r, config.requireContentType); err != nil {
+			return nil, &erraux.Error{
+				Err:     err,
+				Message: fmt.Sprintf("supported content types are [%s]", jsonContentType),
+				Code:    http.StatusUnsupportedMediaType,
+			}
+		}
+
+		payload, err := readRequestBody(r, config)
+		if err != nil {
+			return nil, err
+		}
+		var body extendWebhookBody
+		if err = json.Unmarshal(payload, &body); err != nil {
+			return nil, &erraux.Error{Err: fmt.Errorf("%w: %w", errFailedWebhookUnmarshal, err), Code: http.StatusBadRequest}
+		}
+		if body.Until.IsZero() && body.Duration <= 0 {
+			return nil, &erraux.Error{Err: errExtensionRequired, Code: http.StatusBadRequest}
+		}
+
+		return &extendWebhookRequest{
+			owner:    g.owner,
+			id:       g.id,
+			until:    body.Until.UTC(),
+			duration: time.Duration(body.Duration),
+		}, nil
+	}
+}
+
+func encodeGetWebhookResponse(_ context.Context, rw http.ResponseWriter, response interface{}) error {
+	webhooks := InternalWebhooksToWebhooks([]InternalWebhook{response.(InternalWebhook)})
+	obfuscateSecrets(webhooks)
+	plainUntils(webhooks)
+	encodedWebhook, err := json.Marshal(&webhooks[0])
+	if err != nil {
+		return err
+	}
+
+	rw.Header().Set(contentTypeHeader, jsonContentType)
+	_, err = rw.Write(encodedWebhook)
+	return err
+}
+
+// readRequestBody reads the request's body with readBody, capped at the
+// configured size, failing with a 413 when it's larger and a 400 when its gzip
+// encoding is corrupt.
+func readRequestBody(r *http.Request, config transportConfig) ([]byte, error) {
+	b, err := readBody(r, !config.disableCompression, config.maxBodyBytes)
+	if errors.Is(err, errRequestBodyTooLarge) {
+		return nil, &erraux.Error{Err: err, Code: http.StatusRequestEntityTooLarge}
+	}
+	if errors.Is(err, errCorruptGzipBody) {
+		return nil, &HTTPError{Code: http.StatusBadRequest, Message: errCorruptGzipBody.Error(), Err: err}
+	}
+	return b, err
+}
+
 func addWebhookRequestDecoder(config transportConfig) kithttp.DecodeRequestFunc {
-	wv := webhookValidator{
-		now: config.now,
+	if config.defaults == nil {
+		config.defaults = DefaultPolicy{Now: config.now}
 	}
 
 	if config.basicPartnerIDsHeader == "" {
@@ -74,36 +481,104 @@ func addWebhookRequestDecoder(config transportConfig) kithttp.DecodeRequestFunc
 	}
 
 	return func(c context.Context, r *http.Request) (request interface{}, err error) {
-		requestPayload, err := io.ReadAll(r.Body)
+		err = checkContentType(r, config.requireContentType)
+		if err != nil {
+			return nil, &erraux.Error{
+				Err:     err,
+				Message: fmt.Sprintf("supported content types are [%s]", jsonContentType),
+				Code:    http.StatusUnsupportedMediaType,
+			}
+		}
+		requestPayload, err := readRequestBody(r, config)
 		if err != nil {
 			return nil, err
 		}
-		var wr WebhookRegistration
-
-		err = json.Unmarshal(requestPayload, &wr)
+		wr, err := unmarshalWebhookRegistration(requestPayload, config.acceptEpochUntil)
+		if err == nil && config.strictDecoding {
+			err = checkUnknownFields(requestPayload)
+		}
+		if config.addRequestBytes != nil {
+			version := V1Version
+			if err != nil {
+				version = InvalidVersion
+			}
+			config.addRequestBytes.With(prometheus.Labels{VersionLabel: version}).Observe(float64(len(requestPayload)))
+		}
 		if err != nil {
+			if errors.Is(err, errInvalidUntilFormat) {
+				return nil, &erraux.Error{Err: fmt.Errorf("%w: %w", errFailedWebhookUnmarshal, err), Code: http.StatusBadRequest}
+			}
 			var e *json.UnmarshalTypeError
 			if errors.As(err, &e) {
 				return nil, &erraux.Error{Err: fmt.Errorf("%w: %v must be of type %v", errFailedWebhookUnmarshal, e.Field, e.Type), Code: http.StatusBadRequest}
 			}
-			return nil, &erraux.Error{Err: fmt.Errorf("%w: %v", errFailedWebhookUnmarshal, err), Code: http.StatusBadRequest}
+			return nil, &erraux.Error{Err: fmt.Errorf("%w: %w", errFailedWebhookUnmarshal, err), Code: http.StatusBadRequest}
 		}
 
 		webhook := wr.ToWebhook()
-		err = config.v.Validate(webhook)
+		breakGlass, err := checkBreakGlass(r, config)
+		if err != nil {
+			config.logger(c).Warn("Refused break glass add webhook request",
+				zap.String("url", redactURL(webhook.Config.URL)), zap.String("remote_addr", r.RemoteAddr), zap.Error(err))
+			return nil, err
+		}
+		rejectedURLs := config.rejectedURLs
+		if breakGlass {
+			owner, _ := auth.GetPrincipal(r.Context())
+			config.logger(c).Warn("Break glass add webhook request skips host lookups",
+				zap.String("owner", owner), zap.String("url", redactURL(webhook.Config.URL)), zap.String("remote_addr", r.RemoteAddr))
+			if config.breakGlassUses != nil {
+				config.breakGlassUses.Inc()
+			}
+			c = withoutHostLookups(c)
+			rejectedURLs = nil
+		}
+		if rejectedURLs != nil {
+			if err := rejectedURLs.get(webhook.Config.URL); err != nil {
+				return nil, &erraux.Error{Err: invalidWebhookError{err}, Message: "failed webhook validation", Code: http.StatusBadRequest}
+			}
+		}
+		err = validateWithin(c, config.v, webhook, config.validationTimeout)
+		if errors.Is(err, errValidationBudgetExceeded) {
+			return nil, &erraux.Error{Err: err, Code: http.StatusGatewayTimeout}
+		}
+		var pe *validatorPanicError
+		if errors.As(err, &pe) {
+			if config.getLogger != nil {
+				if logger := config.getLogger(c); logger != nil {
+					logger.Error("Webhook validator panicked", zap.Error(err), zap.ByteString("stack", pe.stack))
+				}
+			}
+			return nil, &erraux.Error{Err: err, Message: "failed webhook validation", Code: http.StatusInternalServerError}
+		}
 		if err != nil {
-			return nil, &erraux.Error{Err: err, Message: "failed webhook validation", Code: http.StatusBadRequest}
+			if rejectedURLs != nil && errors.Is(err, errInvalidURL) {
+				rejectedURLs.add(webhook.Config.URL, err)
+			}
+			return nil, &erraux.Error{Err: invalidWebhookError{err}, Message: "failed webhook validation", Code: http.StatusBadRequest}
 		}
 
-		wv.setWebhookDefaults(&webhook, r.RemoteAddr)
+		err = config.defaults.Apply(&webhook, r.RemoteAddr)
+		if err != nil {
+			return nil, &erraux.Error{Err: err, Message: "failed applying webhook defaults", Code: http.StatusBadRequest}
+		}
 
 		partners, ok := auth.GetPartnerIDs(r.Context())
+		if !ok {
+			// fall back to the partner IDs header when the auth middleware
+			// didn't set any.
+			partners, ok = headerPartnerIDs(r, config.basicPartnerIDsHeader)
+		}
 		if !ok {
 			if !config.disablePartnerIDs {
 				return nil, &erraux.Error{Err: errGettingPartnerIDs, Message: "failed getting partnerIDs", Code: http.StatusBadRequest}
 			}
 			partners = []string{}
 		}
+		partners = NormalizePartnerIDs(partners, config.lowercasePartnerIDs)
+		if len(partners) == 0 && !config.disablePartnerIDs {
+			return nil, &erraux.Error{Err: errNoPartnerIDs, Message: "failed getting partnerIDs", Code: http.StatusBadRequest}
+		}
 
 		owner, ok := auth.GetPrincipal(r.Context())
 		if !ok {
@@ -116,58 +591,304 @@ func addWebhookRequestDecoder(config transportConfig) kithttp.DecodeRequestFunc
 				Webhook:    webhook,
 				PartnerIDs: partners,
 			},
+			createOnly: isCreateOnly(r),
 		}, nil
 	}
 }
 
+// checkBreakGlass reports whether the request asks to skip host lookups with the
+// break glass header, failing when the header is invalid or the request isn't
+// allowed to.
+func checkBreakGlass(r *http.Request, config transportConfig) (bool, error) {
+	v := r.Header.Get(breakGlassHeader)
+	if v == "" {
+		return false, nil
+	}
+	breakGlass, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, &erraux.Error{Err: fmt.Errorf("%w: %q", errInvalidBreakGlass, v), Code: http.StatusBadRequest}
+	}
+	if !breakGlass {
+		return false, nil
+	}
+	if config.breakGlass == nil || !config.breakGlass(r) {
+		return false, &erraux.Error{Err: errBreakGlassNotAllowed, Code: http.StatusForbidden}
+	}
+	return true, nil
+}
+
+// checkContentType returns an error unless the request's body is JSON. A request
+// without a Content-Type is taken to be JSON unless the header is required.
+func checkContentType(r *http.Request, required bool) error {
+	contentType := r.Header.Get(contentTypeHeader)
+	if contentType == "" {
+		if required {
+			return errMissingContentType
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w '%s': %w", errUnsupportedContentType, contentType, err)
+	}
+	if mediaType != jsonContentType {
+		return fmt.Errorf("%w: %s", errUnsupportedContentType, mediaType)
+	}
+	return nil
+}
+
+// isCreateOnly reports whether the add request asks not to replace an existing
+// webhook, either with an "If-None-Match: *" header or a create_only query parameter.
+func isCreateOnly(r *http.Request) bool {
+	if r.Header.Get("If-None-Match") == "*" {
+		return true
+	}
+	createOnly, _ := strconv.ParseBool(r.URL.Query().Get("create_only"))
+	return createOnly
+}
+
+// validateWithin runs the validator, giving up once the timeout elapses. A
+// ContextValidator is given a context canceled along with the budget; any other
+// validator that runs over is left to finish in the background.
+// A timeout of 0 runs the validator without a budget.
+func validateWithin(ctx context.Context, v Validator, webhook Webhook, timeout time.Duration) error {
+	if timeout <= 0 {
+		return safeValidate(ctx, v, webhook)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errValidationBudgetExceeded)
+	defer cancel()
+	result := make(chan error, 1)
+	go func() {
+		result <- safeValidate(ctx, v, webhook)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// validatorPanicError is a recovered validator panic. The stack is kept out of
+// the message since the message is sent to the client.
+type validatorPanicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *validatorPanicError) Error() string {
+	return fmt.Sprintf("%v: %v", errValidatorPanic, e.value)
+}
+
+func (e *validatorPanicError) Unwrap() error {
+	return errValidatorPanic
+}
+
+// safeValidate runs the validator with the context if it is a ContextValidator,
+// converting a panic into a validatorPanicError.
+func safeValidate(ctx context.Context, v Validator, webhook Webhook) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &validatorPanicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return validateContext(ctx, v, webhook)
+}
+
+// addWebhookResponseEncoder returns the encoder of add responses, which is
+// encodeAddWebhookResponse when legacy is true.
+func addWebhookResponseEncoder(legacy bool) kithttp.EncodeResponseFunc {
+	if legacy {
+		return encodeAddWebhookResponse
+	}
+	return encodeAddedWebhookResponse
+}
+
 func encodeAddWebhookResponse(ctx context.Context, rw http.ResponseWriter, _ interface{}) error {
 	rw.Header().Set(contentTypeHeader, jsonContentType)
 	rw.Write([]byte(`{"message": "Success"}`))
 	return nil
 }
 
-func obfuscateSecrets(webhooks []Webhook) {
-	for i := range webhooks {
-		webhooks[i].Config.Secret = "<obfuscated>"
+// encodeAddedWebhookResponse encodes the added webhook like the get handler,
+// with a 201 when it was created and a 200 otherwise.
+func encodeAddedWebhookResponse(ctx context.Context, rw http.ResponseWriter, response interface{}) error {
+	r, ok := response.(*addWebhookResponse)
+	if !ok {
+		return encodeAddWebhookResponse(ctx, rw, response)
 	}
+	webhooks := []Webhook{r.webhook}
+	obfuscateSecrets(webhooks)
+	plainUntils(webhooks)
+	encoded, err := json.Marshal(&webhooks[0])
+	if err != nil {
+		return err
+	}
+
+	rw.Header().Set(contentTypeHeader, jsonContentType)
+	if r.created {
+		rw.WriteHeader(http.StatusCreated)
+	}
+	_, err = rw.Write(encoded)
+	return err
 }
 
-type webhookValidator struct {
-	now func() time.Time
+// obfuscateSecrets hides the secrets of the webhooks, including the passwords
+// of their URLs. The webhooks are expected to be copies, but the slices they
+// hold may be shared with the stored webhooks, so those are copied first.
+func obfuscateSecrets(webhooks []Webhook) {
+	for i := range webhooks {
+		w := &webhooks[i]
+		w.Config.Secret = "<obfuscated>"
+		w.Config.URL = redactURL(w.Config.URL)
+		w.FailureURL = redactURL(w.FailureURL)
+		if len(w.Config.AlternativeURLs) > 0 {
+			alts := make([]string, len(w.Config.AlternativeURLs))
+			for j, alt := range w.Config.AlternativeURLs {
+				alts[j] = redactURL(alt)
+			}
+			w.Config.AlternativeURLs = alts
+		}
+	}
 }
 
-func (wv webhookValidator) setWebhookDefaults(webhook *Webhook, requestOriginHost string) {
-	if len(webhook.Matcher.DeviceID) == 0 {
-		webhook.Matcher.DeviceID = []string{".*"} // match anything
+// redactURL replaces the password of the URL, if it has one, with "xxxxx".
+// Other URLs are returned as is.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
 	}
-	if webhook.Until.IsZero() {
-		webhook.Until = wv.now().Add(webhook.Duration)
+	if _, ok := u.User.Password(); !ok {
+		return rawURL
 	}
-	if requestOriginHost != "" {
-		webhook.Address = requestOriginHost
+	return u.Redacted()
+}
+
+// redactItemData returns a deep copy of the data of a stored item with its
+// secrets obfuscated and the passwords of its URLs redacted like
+// obfuscateSecrets does. Since stored items may use any of the historical
+// spellings of the keys, every key named secret regardless of case is
+// obfuscated, and every string that is a URL with a password is redacted.
+func redactItemData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
 	}
+	return redactValue("", data).(map[string]interface{})
+}
 
+func redactValue(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			redacted[k] = redactValue(k, e)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, e := range v {
+			redacted[i] = redactValue(key, e)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(v))
+		for i, e := range v {
+			redacted[i] = redactURL(e)
+		}
+		return redacted
+	case string:
+		if strings.EqualFold(key, "secret") {
+			return "<obfuscated>"
+		}
+		return redactURL(v)
+	}
+	return v
 }
 
 func errorEncoder(getLogger func(context.Context) *zap.Logger) kithttp.ErrorEncoder {
+	return formattedErrorEncoder(getLogger, MessageJSON)
+}
+
+// formattedErrorEncoder is errorEncoder, writing bodies in the format.
+func formattedErrorEncoder(getLogger func(context.Context) *zap.Logger, format ErrorFormat) kithttp.ErrorEncoder {
 	return func(ctx context.Context, err error, w http.ResponseWriter) {
-		w.Header().Set(contentTypeHeader, jsonContentType)
 		code := http.StatusInternalServerError
 		var sc kithttp.StatusCoder
 		if errors.As(err, &sc) {
 			code = sc.StatusCode()
 		}
 
-		logger := getLogger(ctx)
-		if logger != nil && code != http.StatusNotFound {
+		var logger *zap.Logger
+		if getLogger != nil {
+			logger = getLogger(ctx)
+		}
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+		if isCommitted(w) {
+			logger.Error("failed after the response was committed", zap.Int("code", code), zap.Error(err))
+			return
+		}
+		if code != http.StatusNotFound {
 			logger.Error("sending non-200, non-404 response", zap.Int("code", code), zap.Error(err))
 		}
 
+		msg := err.Error()
+		var s SanitizedError
+		switch {
+		case errors.As(err, &s):
+			msg = s.SanitizedError()
+		case code >= http.StatusInternalServerError:
+			// server errors, e.g. wrapping Argus failures, may hold internal
+			// URLs and headers, so only their log has the details.
+			msg = internalErrorMessage
+		}
+		var details []string
+		var ve ValidationErrors
+		if code < http.StatusInternalServerError && errors.As(err, &ve) {
+			details = make([]string, len(ve))
+			for i, e := range ve {
+				details[i] = truncateErrorMessage(e.Error())
+			}
+		}
+		body := map[string]interface{}{
+			"message": truncateErrorMessage(msg),
+		}
+		if details != nil {
+			body["details"] = details
+		}
+		contentType := jsonContentType
+		if format == ProblemJSON {
+			body = problemBody(err, code, truncateErrorMessage(msg), details)
+			contentType = problemJSONContentType
+		}
+		encoded, encodeErr := json.Marshal(body)
+		if encodeErr != nil {
+			logger.Error("failed encoding error response", zap.Error(encodeErr))
+			encoded = []byte(`{"message":"` + internalErrorMessage + `"}`)
+		}
+
+		var h kithttp.Headerer
+		if errors.As(err, &h) {
+			for k, values := range h.Headers() {
+				for _, v := range values {
+					w.Header().Add(k, v)
+				}
+			}
+		}
+		w.Header().Set(contentTypeHeader, contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(encoded)))
 		w.WriteHeader(code)
+		w.Write(encoded)
+	}
+}
 
-		json.NewEncoder(w).Encode(
-			map[string]interface{}{
-				"message": err.Error(),
-			})
+// truncateErrorMessage caps the size of the messages in error responses.
+func truncateErrorMessage(msg string) string {
+	if len(msg) <= maxErrorMessageLength {
+		return msg
 	}
+	return strings.ToValidUTF8(msg[:maxErrorMessageLength], "") + "..."
 }
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidUntilFormat  = errors.New(`"until" must be an RFC 3339 time`)
+	errUnknownWebhookField = errors.New("unknown webhook field")
+)
+
+// untilNearMisses are the layouts commonly sent instead of RFC 3339.
+// Fractional seconds are accepted by time.Parse without being in the layouts.
+var untilNearMisses = []string{
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// unmarshalWebhookRegistration unmarshals the payload, normalizing the until
+// field to UTC. An until given as RFC 3339, with or without fractional seconds,
+// is accepted; if acceptEpoch is true, so is one given as epoch seconds. Near
+// misses of the format are reported with a suggestion of the correct value.
+func unmarshalWebhookRegistration(payload []byte, acceptEpoch bool) (WebhookRegistration, error) {
+	wr, err := unmarshalUntil(payload, acceptEpoch)
+	wr.Until = wr.Until.UTC()
+	return wr, err
+}
+
+// checkUnknownFields fails with errUnknownWebhookField, naming the field, when
+// the payload has a field, top level or nested, that WebhookRegistration
+// doesn't. Other decoding errors are left to unmarshalWebhookRegistration.
+func checkUnknownFields(payload []byte) error {
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.DisallowUnknownFields()
+	var wr WebhookRegistration
+	err := d.Decode(&wr)
+	if err == nil {
+		return nil
+	}
+	// encoding/json has no error type for unknown fields.
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return fmt.Errorf("%w %s", errUnknownWebhookField, field)
+	}
+	return nil
+}
+
+func unmarshalUntil(payload []byte, acceptEpoch bool) (WebhookRegistration, error) {
+	var wr WebhookRegistration
+	err := json.Unmarshal(payload, &wr)
+	if err == nil {
+		return wr, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(payload, &fields) != nil {
+		return wr, err
+	}
+	raw, ok := fields["until"]
+	if !ok {
+		return wr, err
+	}
+	var until time.Time
+	if json.Unmarshal(raw, &until) == nil {
+		// the until field isn't the problem.
+		return wr, err
+	}
+
+	if seconds, perr := strconv.ParseInt(string(raw), 10, 64); perr == nil {
+		until = time.Unix(seconds, 0).UTC()
+		if !acceptEpoch {
+			return wr, untilFormatError(string(raw), "epoch seconds", until)
+		}
+		fields["until"], _ = json.Marshal(until)
+		fixed, merr := json.Marshal(fields)
+		if merr != nil {
+			return wr, err
+		}
+		wr = WebhookRegistration{}
+		return wr, json.Unmarshal(fixed, &wr)
+	}
+
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return wr, err
+	}
+	for _, layout := range untilNearMisses {
+		// layouts without a time zone are assumed to be UTC.
+		if until, perr := time.Parse(layout, s); perr == nil {
+			return wr, untilFormatError(strconv.Quote(s), "a near miss of RFC 3339", until)
+		}
+	}
+	return wr, err
+}
+
+func untilFormatError(got, kind string, suggested time.Time) error {
+	return fmt.Errorf("%w: got %s (%s), use %q instead", errInvalidUntilFormat, got, kind, suggested.Format(time.RFC3339Nano))
+}
+
+// plainUntils truncates the until of the webhooks to whole seconds in UTC, so
+// they are encoded as plain RFC 3339 times.
+func plainUntils(webhooks []Webhook) {
+	for i := range webhooks {
+		if !webhooks[i].Until.IsZero() {
+			webhooks[i].Until = webhooks[i].Until.UTC().Truncate(time.Second)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalWebhookRegistration(t *testing.T) {
+	refUntil := time.Date(2021, time.January, 2, 15, 4, 10, 0, time.UTC)
+	tcs := []struct {
+		desc          string
+		until         string
+		acceptEpoch   bool
+		expectedUntil time.Time
+		expectedErr   error
+		suggestion    string
+		expectFailure bool
+	}{
+		{
+			desc:          "RFC 3339",
+			until:         `"2021-01-02T15:04:10Z"`,
+			expectedUntil: refUntil,
+		},
+		{
+			desc:          "RFC 3339 with fractional seconds",
+			until:         `"2021-01-02T15:04:10.25Z"`,
+			expectedUntil: refUntil.Add(250 * time.Millisecond),
+		},
+		{
+			desc:          "RFC 3339 with offset",
+			until:         `"2021-01-02T10:04:10-05:00"`,
+			expectedUntil: refUntil,
+		},
+		{
+			desc:          "RFC 3339 with fractional seconds and offset",
+			until:         `"2021-01-02T16:04:10.5+01:00"`,
+			expectedUntil: refUntil.Add(500 * time.Millisecond),
+		},
+		{
+			desc:        "Space instead of T",
+			until:       `"2021-01-02 15:04:10Z"`,
+			expectedErr: errInvalidUntilFormat,
+			suggestion:  "2021-01-02T15:04:10Z",
+		},
+		{
+			desc:        "Space instead of T with offset",
+			until:       `"2021-01-02 10:04:10-05:00"`,
+			expectedErr: errInvalidUntilFormat,
+			suggestion:  "2021-01-02T10:04:10-05:00",
+		},
+		{
+			desc:        "Missing time zone",
+			until:       `"2021-01-02T15:04:10"`,
+			expectedErr: errInvalidUntilFormat,
+			suggestion:  "2021-01-02T15:04:10Z",
+		},
+		{
+			desc:        "Space instead of T and missing time zone",
+			until:       `"2021-01-02 15:04:10.5"`,
+			expectedErr: errInvalidUntilFormat,
+			suggestion:  "2021-01-02T15:04:10.5Z",
+		},
+		{
+			desc:        "Epoch seconds rejected",
+			until:       fmt.Sprint(refUntil.Unix()),
+			expectedErr: errInvalidUntilFormat,
+			suggestion:  "2021-01-02T15:04:10Z",
+		},
+		{
+			desc:          "Epoch seconds accepted",
+			until:         fmt.Sprint(refUntil.Unix()),
+			acceptEpoch:   true,
+			expectedUntil: refUntil,
+		},
+		{
+			desc:          "Unrecognized format",
+			until:         `"tomorrow"`,
+			expectFailure: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			payload := fmt.Sprintf(`{"config": {"url": "https://example.com"}, "events": ["online"], "until": %s}`, tc.until)
+			wr, err := unmarshalWebhookRegistration([]byte(payload), tc.acceptEpoch)
+			switch {
+			case tc.expectFailure:
+				assert.Error(err)
+				assert.NotErrorIs(err, errInvalidUntilFormat)
+			case tc.expectedErr != nil:
+				assert.ErrorIs(err, tc.expectedErr)
+				assert.Contains(err.Error(), tc.suggestion)
+			default:
+				assert.NoError(err)
+				assert.Equal(tc.expectedUntil, wr.Until)
+				assert.Equal("https://example.com", wr.Config.URL)
+			}
+		})
+	}
+
+	t.Run("Other field failure", func(t *testing.T) {
+		_, err := unmarshalWebhookRegistration([]byte(`{"events": "online", "until": "2021-01-02T15:04:10Z"}`), false)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errInvalidUntilFormat)
+	})
+}
+
+func TestCheckUnknownFields(t *testing.T) {
+	tcs := []struct {
+		desc          string
+		payload       string
+		expectedField string
+	}{
+		{
+			desc:    "Known fields",
+			payload: `{"config": {"url": "https://example.com/hook"}, "events": ["online"], "duration": "1m"}`,
+		},
+		{
+			desc:          "Top level typo",
+			payload:       `{"config": {"url": "https://example.com/hook"}, "evnts": ["online"], "duration": "1m"}`,
+			expectedField: `"evnts"`,
+		},
+		{
+			desc:          "Nested typo",
+			payload:       `{"config": {"ur1": "https://example.com/hook"}, "events": ["online"]}`,
+			expectedField: `"ur1"`,
+		},
+		{
+			desc:    "Other decoding errors are ignored",
+			payload: `{"config": {"url": "https://example.com/hook"}, "until": 1609599850}`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			err := checkUnknownFields([]byte(tc.payload))
+			if tc.expectedField == "" {
+				assert.NoError(err)
+				return
+			}
+			assert.ErrorIs(err, errUnknownWebhookField)
+			assert.Contains(err.Error(), tc.expectedField)
+		})
+	}
+}
+
+func TestPlainUntils(t *testing.T) {
+	refUntil := time.Date(2021, time.January, 2, 15, 4, 10, 0, time.UTC)
+	webhooks := []Webhook{
+		{Until: refUntil.Add(750 * time.Millisecond).In(time.FixedZone("EST", -5*60*60))},
+		{Until: refUntil},
+		{},
+	}
+	plainUntils(webhooks)
+	assert.Equal(t, []Webhook{{Until: refUntil}, {Until: refUntil}, {}}, webhooks)
+}
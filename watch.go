@@ -4,15 +4,30 @@
 package ancla
 
 import (
+	"reflect"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xmidt-org/ancla/chrysom"
 )
 
 // Watch is the interface for listening for webhook subcription updates.
-// Updates represent the latest known list of subscriptions.
+// Updates represent the latest known list of subscriptions, sorted by the IDs
+// of the items they are stored as.
 type Watch interface {
 	Update([]InternalWebhook)
 }
 
+// MetaWatch is a Watch that is also told about the poll behind each update.
+// Watches given to StartListener that implement it get UpdateWithMeta instead
+// of Update, including for failed polls when chrysom.ListenerClientConfig's
+// DeliverFailedPolls is set, which come with no webhooks, and for polls whose
+// webhooks are unchanged, which plain Watches aren't updated with unless its
+// AlwaysNotify is set.
+type MetaWatch interface {
+	Watch
+	UpdateWithMeta(meta chrysom.UpdateMeta, webhooks []InternalWebhook)
+}
+
 // WatchFunc allows bare functions to pass as Watches.
 type WatchFunc func([]InternalWebhook)
 
@@ -25,3 +40,36 @@ func webhookListSizeWatch(s prometheus.Gauge) Watch {
 		s.Set(float64(len(webhooks)))
 	})
 }
+
+// registrationChurnWatch counts the registrations added, removed and updated
+// between consecutive updates, identifying registrations by their URL. Unless
+// countInitial is true, the first update only sets the baseline.
+func registrationChurnWatch(added, removed, updated prometheus.Counter, countInitial bool) Watch {
+	var previous map[string]InternalWebhook
+	return WatchFunc(func(webhooks []InternalWebhook) {
+		current := make(map[string]InternalWebhook, len(webhooks))
+		for _, iw := range webhooks {
+			current[iw.Webhook.Config.URL] = iw
+		}
+		if previous == nil && !countInitial {
+			previous = current
+			return
+		}
+
+		for url, iw := range current {
+			old, ok := previous[url]
+			switch {
+			case !ok:
+				added.Inc()
+			case !reflect.DeepEqual(old, iw):
+				updated.Inc()
+			}
+		}
+		for url := range previous {
+			if _, ok := current[url]; !ok {
+				removed.Inc()
+			}
+		}
+		previous = current
+	})
+}
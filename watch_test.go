@@ -5,7 +5,11 @@ package ancla
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,3 +24,66 @@ func TestWebhookListSizeWatch(t *testing.T) {
 	// nolint:typecheck
 	gauge.AssertExpectations(t)
 }
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	require.NoError(t, c.Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestRegistrationChurnWatch(t *testing.T) {
+	var (
+		a        = InternalWebhook{Webhook: Webhook{Config: DeliveryConfig{URL: "https://a.example.net"}}}
+		b        = InternalWebhook{Webhook: Webhook{Config: DeliveryConfig{URL: "https://b.example.net"}}}
+		c        = InternalWebhook{Webhook: Webhook{Config: DeliveryConfig{URL: "https://c.example.net"}}}
+		aRenewed = a
+	)
+	aRenewed.Webhook.Until = time.Date(2021, time.January, 2, 15, 4, 10, 0, time.UTC)
+
+	tcs := []struct {
+		desc            string
+		countInitial    bool
+		updates         [][]InternalWebhook
+		expectedAdded   float64
+		expectedRemoved float64
+		expectedUpdated float64
+	}{
+		{
+			desc:    "First update is the baseline",
+			updates: [][]InternalWebhook{{a, b}},
+		},
+		{
+			desc:          "First update counted",
+			countInitial:  true,
+			updates:       [][]InternalWebhook{{a, b}},
+			expectedAdded: 2,
+		},
+		{
+			desc:            "Several cycles",
+			updates:         [][]InternalWebhook{{a, b}, {aRenewed, b, c}, {aRenewed, c}, {}, {a}},
+			expectedAdded:   2,
+			expectedRemoved: 3,
+			expectedUpdated: 1,
+		},
+		{
+			desc:          "Empty baseline",
+			updates:       [][]InternalWebhook{{}, {a}, {a}},
+			expectedAdded: 1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			added := prometheus.NewCounter(prometheus.CounterOpts{Name: "added"})
+			removed := prometheus.NewCounter(prometheus.CounterOpts{Name: "removed"})
+			updated := prometheus.NewCounter(prometheus.CounterOpts{Name: "updated"})
+			watch := registrationChurnWatch(added, removed, updated, tc.countInitial)
+			for _, u := range tc.updates {
+				watch.Update(u)
+			}
+			assert.Equal(tc.expectedAdded, counterValue(t, added))
+			assert.Equal(tc.expectedRemoved, counterValue(t, removed))
+			assert.Equal(tc.expectedUpdated, counterValue(t, updated))
+		})
+	}
+}
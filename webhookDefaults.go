@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errDeviceIDRequired = errors.New("matcher device_id is required")
+	errDurationRequired = errors.New("until or duration is required")
+)
+
+// DefaultsPolicy fills in the optional fields of a webhook registration that the
+// requester left out. The add handler applies it after the webhook is validated.
+type DefaultsPolicy interface {
+	// Apply sets the defaults on the given webhook. requestOriginHost is the
+	// address the registration request came from, or "" if it is unknown.
+	// A non-nil error rejects the registration.
+	Apply(webhook *Webhook, requestOriginHost string) error
+}
+
+// DefaultsPolicyFunc allows bare functions to pass as DefaultsPolicies.
+type DefaultsPolicyFunc func(*Webhook, string) error
+
+// Apply runs the function and returns the result.
+func (f DefaultsPolicyFunc) Apply(webhook *Webhook, requestOriginHost string) error {
+	return f(webhook, requestOriginHost)
+}
+
+// DefaultPolicy is the DefaultsPolicy used when none is configured. It matches
+// any device when no Matcher.DeviceID is given, computes Until from Duration when
+// Until is absent and records the request origin as the webhook's Address.
+type DefaultPolicy struct {
+	// Now is used to compute Until.
+	// (Optional). Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Apply sets the defaults on the given webhook. It never returns an error.
+func (p DefaultPolicy) Apply(webhook *Webhook, requestOriginHost string) error {
+	if len(webhook.Matcher.DeviceID) == 0 {
+		webhook.Matcher.DeviceID = []string{".*"} // match anything
+	}
+	setUntilAndAddress(webhook, requestOriginHost, p.Now)
+	return nil
+}
+
+// StrictPolicy is a DefaultsPolicy that refuses to guess on the requester's behalf:
+// a webhook must provide its own Matcher.DeviceID and either Until or Duration.
+// Only Until (from Duration) and Address are filled in.
+type StrictPolicy struct {
+	// Now is used to compute Until.
+	// (Optional). Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Apply rejects webhooks that would need a permissive default and otherwise sets
+// the remaining defaults on the given webhook.
+func (p StrictPolicy) Apply(webhook *Webhook, requestOriginHost string) error {
+	if len(webhook.Matcher.DeviceID) == 0 {
+		return errDeviceIDRequired
+	}
+	if webhook.Until.IsZero() && webhook.Duration <= 0 {
+		return errDurationRequired
+	}
+	setUntilAndAddress(webhook, requestOriginHost, p.Now)
+	return nil
+}
+
+func setUntilAndAddress(webhook *Webhook, requestOriginHost string, now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	if webhook.Until.IsZero() {
+		webhook.Until = now().Add(webhook.Duration)
+	}
+	if requestOriginHost != "" {
+		webhook.Address = requestOriginHost
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	assert := assert.New(t)
+	webhook := Webhook{
+		Config: DeliveryConfig{
+			URL: "example.com:443",
+		},
+		Events: []string{"online"},
+	}
+	assert.NoError(DefaultPolicy{Now: mockNow}.Apply(&webhook, ""))
+	assert.Equal([]string{".*"}, webhook.Matcher.DeviceID)
+	assert.Equal(mockNow(), webhook.Until)
+	assert.Empty(webhook.Address)
+}
+
+func TestStrictPolicy(t *testing.T) {
+	tcs := []struct {
+		desc            string
+		webhook         Webhook
+		remoteAddr      string
+		expectedWebhook Webhook
+		expectedErr     error
+	}{
+		{
+			desc: "No DeviceID Failure",
+			webhook: Webhook{
+				Events:   []string{"online"},
+				Duration: 5 * time.Minute,
+			},
+			expectedErr: errDeviceIDRequired,
+		},
+		{
+			desc: "No Until or Duration Failure",
+			webhook: Webhook{
+				Events: []string{"online"},
+				Matcher: MetadataMatcherConfig{
+					DeviceID: []string{"mac:112233445566"},
+				},
+			},
+			expectedErr: errDurationRequired,
+		},
+		{
+			desc: "Duration Success",
+			webhook: Webhook{
+				Events: []string{"online"},
+				Matcher: MetadataMatcherConfig{
+					DeviceID: []string{"mac:112233445566"},
+				},
+				Duration: 5 * time.Minute,
+			},
+			remoteAddr: "example.com:443",
+			expectedWebhook: Webhook{
+				Address: "example.com:443",
+				Events:  []string{"online"},
+				Matcher: MetadataMatcherConfig{
+					DeviceID: []string{"mac:112233445566"},
+				},
+				Duration: 5 * time.Minute,
+				Until:    mockNow().Add(5 * time.Minute),
+			},
+		},
+		{
+			desc: "Until Success",
+			webhook: Webhook{
+				Events: []string{"online"},
+				Matcher: MetadataMatcherConfig{
+					DeviceID: []string{"mac:112233445566"},
+				},
+				Until: mockNow().Add(time.Minute),
+			},
+			expectedWebhook: Webhook{
+				Events: []string{"online"},
+				Matcher: MetadataMatcherConfig{
+					DeviceID: []string{"mac:112233445566"},
+				},
+				Until: mockNow().Add(time.Minute),
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			webhook := tc.webhook
+			err := StrictPolicy{Now: mockNow}.Apply(&webhook, tc.remoteAddr)
+			if tc.expectedErr != nil {
+				assert.True(errors.Is(err, tc.expectedErr))
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expectedWebhook, webhook)
+		})
+	}
+}
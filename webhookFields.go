@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2026 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package ancla
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errUnknownField = errors.New("unknown webhook field")
+
+// webhookFields are the fields that can be selected with the get all "fields"
+// query parameter, keyed by their name in the response. The secret is never
+// selectable.
+//
+//	registered_from_address  Address
+//	url                      Config.URL
+//	content_type             Config.ContentType
+//	alt_urls                 Config.AlternativeURLs
+//	failure_url              FailureURL
+//	events                   Events
+//	matcher                  Matcher
+//	duration                 Duration
+//	until                    Until
+var webhookFields = map[string]func(Webhook) interface{}{
+	"registered_from_address": func(w Webhook) interface{} { return w.Address },
+	"url":                     func(w Webhook) interface{} { return w.Config.URL },
+	"content_type":            func(w Webhook) interface{} { return w.Config.ContentType },
+	"alt_urls":                func(w Webhook) interface{} { return w.Config.AlternativeURLs },
+	"failure_url":             func(w Webhook) interface{} { return w.FailureURL },
+	"events":                  func(w Webhook) interface{} { return w.Events },
+	"matcher":                 func(w Webhook) interface{} { return w.Matcher },
+	"duration":                func(w Webhook) interface{} { return w.Duration },
+	"until":                   func(w Webhook) interface{} { return w.Until },
+}
+
+// parseWebhookFields parses a comma separated list of webhook fields, returning
+// an error for any field not in webhookFields.
+func parseWebhookFields(s string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if _, ok := webhookFields[f]; !ok {
+			return nil, fmt.Errorf("%w: %q", errUnknownField, f)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// projectWebhooks returns the webhooks with only the given fields.
+func projectWebhooks(webhooks []Webhook, fields []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(webhooks))
+	for i, w := range webhooks {
+		p := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			p[f] = webhookFields[f](w)
+		}
+		projected[i] = p
+	}
+	return projected
+}
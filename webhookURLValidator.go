@@ -4,6 +4,7 @@
 package ancla
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -24,10 +25,39 @@ var (
 	errNoSuchHost            = errors.New("host does not exist")
 	errBadURLProtocol        = errors.New("bad URL protocol")
 	errEmptyURL              = errors.New("url cannot be an empty string")
+	errSelfURL               = errors.New("URL points at this service")
 )
 
+// resolver is used by the validators that look up hosts.
+var resolver = net.DefaultResolver
+
+type skipHostLookupsKey struct{}
+
+// withoutHostLookups returns a context telling the validators that look up
+// hosts to skip the lookups, checking only hosts that are IPs.
+func withoutHostLookups(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipHostLookupsKey{}, true)
+}
+
+// HostLookupsSkipped reports whether the validators that look up hosts must
+// skip the lookups for the given context, as they do for break glass add
+// requests. Custom ContextValidators that look up hosts should honor it.
+func HostLookupsSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipHostLookupsKey{}).(bool)
+	return skip
+}
+
+// lookupIPs resolves the host, returning no IPs when host lookups are skipped
+// for the context and the host isn't an IP.
+func lookupIPs(ctx context.Context, host string) ([]net.IP, error) {
+	if HostLookupsSkipped(ctx) && net.ParseIP(host) == nil {
+		return nil, nil
+	}
+	return resolver.LookupIP(ctx, "ip", host)
+}
+
 // filterNil takes out all entries of Nil value from the slice.
-func filterNil(vs []ValidURLFunc) (filtered []ValidURLFunc) {
+func filterNil(vs []ValidURLFuncCtx) (filtered []ValidURLFuncCtx) {
 	for _, v := range vs {
 		if v != nil {
 			filtered = append(filtered, v)
@@ -36,24 +66,41 @@ func filterNil(vs []ValidURLFunc) (filtered []ValidURLFunc) {
 	return
 }
 
+// adaptAll adapts the ValidURLFuncs into ValidURLFuncCtxs.
+func adaptAll(vs []ValidURLFunc) []ValidURLFuncCtx {
+	adapted := make([]ValidURLFuncCtx, 0, len(vs))
+	for _, v := range vs {
+		if v != nil {
+			adapted = append(adapted, AdaptValidURLFunc(v))
+		}
+	}
+	return adapted
+}
+
 // GoodConfigURL parses the given webhook's Config.URL
 // and returns as soon as the URL is considered invalid. It returns nil if the URL is
 // valid.
 func GoodConfigURL(vs []ValidURLFunc) ValidatorFunc {
+	return GoodConfigURLCtx(adaptAll(vs)).Validate
+}
+
+// GoodConfigURLCtx is GoodConfigURL for ValidURLFuncCtxs, which are given the
+// validation's context.
+func GoodConfigURLCtx(vs []ValidURLFuncCtx) ContextValidatorFunc {
 	vs = filterNil(vs)
-	return func(w Webhook) error {
+	return func(ctx context.Context, w Webhook) error {
 		if w.Config.URL == "" {
-			return fmt.Errorf("%w: %v",
+			return fmt.Errorf("%w: %w",
 				errInvalidURL, errEmptyURL)
 		}
 		parsedURL, err := url.ParseRequestURI(w.Config.URL)
 		if err != nil {
-			return fmt.Errorf("%w: %v", errInvalidURL, err)
+			return fmt.Errorf("%w: %w", errInvalidURL, err)
 		}
 		for _, f := range vs {
-			err = f(parsedURL)
+			err = f(ctx, parsedURL)
 			if err != nil {
-				return fmt.Errorf("%w: %v", errInvalidURL, err)
+				return fmt.Errorf("%w: %w", errInvalidURL, err)
 			}
 		}
 		return nil
@@ -64,18 +111,24 @@ func GoodConfigURL(vs []ValidURLFunc) ValidatorFunc {
 // and returns as soon as the URL is considered invalid. It returns nil if the URL is
 // valid.
 func GoodFailureURL(vs []ValidURLFunc) ValidatorFunc {
+	return GoodFailureURLCtx(adaptAll(vs)).Validate
+}
+
+// GoodFailureURLCtx is GoodFailureURL for ValidURLFuncCtxs, which are given the
+// validation's context.
+func GoodFailureURLCtx(vs []ValidURLFuncCtx) ContextValidatorFunc {
 	vs = filterNil(vs)
-	return func(w Webhook) error {
+	return func(ctx context.Context, w Webhook) error {
 		if w.FailureURL == "" {
 			return nil
 		}
 		parsedFailureURL, err := url.ParseRequestURI(w.FailureURL)
 		if err != nil {
-			return fmt.Errorf("%w: %v", errInvalidFailureURL, err)
+			return fmt.Errorf("%w: %w", errInvalidFailureURL, err)
 		}
 		for _, f := range vs {
-			if err = f(parsedFailureURL); err != nil {
-				return fmt.Errorf("%w: %v", errInvalidFailureURL, err)
+			if err = f(ctx, parsedFailureURL); err != nil {
+				return fmt.Errorf("%w: %w", errInvalidFailureURL, err)
 			}
 		}
 		return nil
@@ -86,22 +139,28 @@ func GoodFailureURL(vs []ValidURLFunc) ValidatorFunc {
 // and returns as soon as the URL is considered invalid. It returns nil if the URL is
 // valid.
 func GoodAlternativeURLs(vs []ValidURLFunc) ValidatorFunc {
+	return GoodAlternativeURLsCtx(adaptAll(vs)).Validate
+}
+
+// GoodAlternativeURLsCtx is GoodAlternativeURLs for ValidURLFuncCtxs, which are
+// given the validation's context.
+func GoodAlternativeURLsCtx(vs []ValidURLFuncCtx) ContextValidatorFunc {
 	vs = filterNil(vs)
-	return func(w Webhook) error {
+	return func(ctx context.Context, w Webhook) error {
 		for _, u := range w.Config.AlternativeURLs {
 			if u == "" {
-				return fmt.Errorf("%w: %v",
+				return fmt.Errorf("%w: %w",
 					errInvalidAlternativeURL, errEmptyURL)
 			}
 			parsedAlternativeURL, err := url.ParseRequestURI(u)
 			if err != nil {
-				return fmt.Errorf("%w '%s': %v",
+				return fmt.Errorf("%w '%s': %w",
 					errInvalidAlternativeURL, u, err)
 			}
 			for _, f := range vs {
-				err = f(parsedAlternativeURL)
+				err = f(ctx, parsedAlternativeURL)
 				if err != nil {
-					return fmt.Errorf("%w '%s': %v",
+					return fmt.Errorf("%w '%s': %w",
 						errInvalidAlternativeURL, u, err)
 				}
 			}
@@ -161,15 +220,24 @@ func RejectAllIPs() ValidURLFunc {
 // RejectLoopback creates a ValidURLFunc that returns an error if the given URL is
 // a loopback address.
 func RejectLoopback() ValidURLFunc {
+	f := RejectLoopbackCtx()
 	return func(u *url.URL) error {
+		return f(context.Background(), u)
+	}
+}
+
+// RejectLoopbackCtx is RejectLoopback with the host lookup canceled along with
+// the given context.
+func RejectLoopbackCtx() ValidURLFuncCtx {
+	return func(ctx context.Context, u *url.URL) error {
 		host := u.Hostname()
 		ip := net.ParseIP(host)
 		if ip != nil && ip.IsLoopback() {
 			return fmt.Errorf("%w: %v", errLoopbackGivenAsHost, ip)
 		}
-		ips, err := net.LookupIP(host)
+		ips, err := lookupIPs(ctx, host)
 		if err != nil {
-			return fmt.Errorf("%w: %v", errNoSuchHost, err)
+			return fmt.Errorf("%w: %w", errNoSuchHost, err)
 		}
 		for _, i := range ips {
 			if i.IsLoopback() {
@@ -184,18 +252,30 @@ func RejectLoopback() ValidURLFunc {
 // InvalidSubnets checks if the given URL is in any subnets we are blocking and returns
 // an error if it is. SpecialIPs will return nil if the URL is not in the subnet.
 func InvalidSubnets(i []string) (ValidURLFunc, error) {
+	f, err := InvalidSubnetsCtx(i)
+	if err != nil {
+		return nil, err
+	}
+	return func(u *url.URL) error {
+		return f(context.Background(), u)
+	}, nil
+}
+
+// InvalidSubnetsCtx is InvalidSubnets with the host lookup canceled along with
+// the given context.
+func InvalidSubnetsCtx(i []string) (ValidURLFuncCtx, error) {
 	invalidSubnets := []*net.IPNet{}
 	for _, sp := range i {
 		_, n, err := net.ParseCIDR(sp)
 		if err != nil {
-			return nil, fmt.Errorf("%w %s: %v", errInvalidSubnet, sp, err)
+			return nil, fmt.Errorf("%w %s: %w", errInvalidSubnet, sp, err)
 		}
 		invalidSubnets = append(invalidSubnets, n)
 	}
-	return func(u *url.URL) error {
-		ips, err := net.LookupIP(u.Hostname())
+	return func(ctx context.Context, u *url.URL) error {
+		ips, err := lookupIPs(ctx, u.Hostname())
 		if err != nil {
-			return fmt.Errorf("%w: %v", errInvalidURL, err)
+			return fmt.Errorf("%w: %w", errInvalidURL, err)
 		}
 		for _, d := range ips {
 			for _, s := range invalidSubnets {
@@ -208,3 +288,102 @@ func InvalidSubnets(i []string) (ValidURLFunc, error) {
 		return nil
 	}, nil
 }
+
+// RejectSelfURLs creates a ValidURLFunc that returns an error if the URL points at
+// the registering service itself, i.e. its host is one of selfHosts or resolves
+// to an IP within selfCIDRs. selfCIDRs may also hold single IPs, see SelfCIDRs.
+func RejectSelfURLs(selfHosts []string, selfCIDRs []string) (ValidURLFunc, error) {
+	f, err := RejectSelfURLsCtx(selfHosts, selfCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	return func(u *url.URL) error {
+		return f(context.Background(), u)
+	}, nil
+}
+
+// RejectSelfURLsCtx is RejectSelfURLs with the host lookup canceled along with
+// the given context.
+func RejectSelfURLsCtx(selfHosts []string, selfCIDRs []string) (ValidURLFuncCtx, error) {
+	hosts := make(map[string]bool, len(selfHosts))
+	for _, h := range selfHosts {
+		if h != "" {
+			hosts[strings.ToLower(h)] = true
+		}
+	}
+	subnets := make([]*net.IPNet, 0, len(selfCIDRs))
+	for _, c := range selfCIDRs {
+		n, err := parseCIDROrIP(c)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", errInvalidSubnet, c, err)
+		}
+		subnets = append(subnets, n)
+	}
+	return func(ctx context.Context, u *url.URL) error {
+		host := u.Hostname()
+		if hosts[strings.ToLower(host)] {
+			return fmt.Errorf("%w: %v", errSelfURL, host)
+		}
+		if len(subnets) == 0 {
+			return nil
+		}
+		ips := []net.IP{net.ParseIP(host)}
+		if ips[0] == nil {
+			var err error
+			if ips, err = lookupIPs(ctx, host); err != nil {
+				return fmt.Errorf("%w: %w", errNoSuchHost, err)
+			}
+		}
+		for _, ip := range ips {
+			for _, n := range subnets {
+				if n.Contains(ip) {
+					return fmt.Errorf("%w: %v resolves to %v", errSelfURL, host, ip)
+				}
+			}
+		}
+		return nil
+	}, nil
+}
+
+// parseCIDROrIP parses a CIDR, or a single IP as the network of just that IP.
+func parseCIDROrIP(s string) (*net.IPNet, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, n, err := net.ParseCIDR(s)
+	return n, err
+}
+
+// interfaceAddrs lists the addresses of the host's network interfaces.
+var interfaceAddrs = net.InterfaceAddrs
+
+// SelfCIDRs returns the IPs of the host's network interfaces, loopback included,
+// as single IP CIDRs for RejectSelfURLs.
+func SelfCIDRs() ([]string, error) {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	cidrs := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		var ip net.IP
+		switch a := a.(type) {
+		case *net.IPNet:
+			ip = a.IP
+		case *net.IPAddr:
+			ip = a.IP
+		default:
+			continue
+		}
+		n, err := parseCIDROrIP(ip.String())
+		if err != nil {
+			continue
+		}
+		cidrs = append(cidrs, n.String())
+	}
+	return cidrs, nil
+}
@@ -4,10 +4,13 @@
 package ancla
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,6 +66,64 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateAll(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	var valid ValidatorFunc = func(Webhook) error { return nil }
+	var first ValidatorFunc = func(Webhook) error { return fmt.Errorf("%w: wrapped", errFirst) }
+	var second ValidatorFunc = func(Webhook) error { return errSecond }
+
+	tcs := []struct {
+		desc         string
+		validators   Validators
+		expectedErrs []error
+	}{
+		{
+			desc: "Nil Validators Success",
+		},
+		{
+			desc:       "Valid Validators Success",
+			validators: Validators{valid, valid},
+		},
+		{
+			desc:         "Single Failure",
+			validators:   Validators{valid, second},
+			expectedErrs: []error{errSecond},
+		},
+		{
+			desc:         "Every Failure",
+			validators:   Validators{first, valid, second},
+			expectedErrs: []error{errFirst, errSecond},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			for _, err := range []error{
+				tc.validators.ValidateAll(Webhook{}),
+				AllValidators(tc.validators).Validate(Webhook{}),
+				AllValidators(tc.validators).ValidateContext(context.Background(), Webhook{}),
+			} {
+				if len(tc.expectedErrs) == 0 {
+					assert.NoError(err)
+					continue
+				}
+				var ve ValidationErrors
+				assert.ErrorAs(err, &ve)
+				assert.Len(ve, len(tc.expectedErrs))
+				for _, expected := range tc.expectedErrs {
+					assert.ErrorIs(err, expected)
+				}
+			}
+		})
+	}
+
+	// Validate still stops at the first failure.
+	err := Validators{first, second}.Validate(Webhook{})
+	assert.ErrorIs(t, err, errFirst)
+	assert.NotErrorIs(t, err, errSecond)
+}
+
 func TestGoodConfigURL(t *testing.T) {
 	tcs := []struct {
 		desc          string
@@ -517,3 +578,217 @@ func TestInvalidSubnets(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupCancellation(t *testing.T) {
+	// the resolver blocks until the lookup's context is done.
+	blocking := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	original := resolver
+	resolver = blocking
+	defer func() { resolver = original }()
+
+	invalidSubnets, err := InvalidSubnetsCtx([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	tcs := []struct {
+		desc string
+		f    ValidURLFuncCtx
+	}{
+		{
+			desc: "RejectLoopback",
+			f:    RejectLoopbackCtx(),
+		},
+		{
+			desc: "InvalidSubnets",
+			f:    invalidSubnets,
+		},
+		{
+			desc: "GoodConfigURL",
+			f: func(ctx context.Context, u *url.URL) error {
+				return GoodConfigURLCtx([]ValidURLFuncCtx{RejectLoopbackCtx()}).ValidateContext(ctx,
+					Webhook{Config: DeliveryConfig{URL: u.String()}})
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			u, err := url.Parse("https://webhook.example.invalid/")
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			err = tc.f(ctx, u)
+			assert.Error(err)
+			assert.Less(time.Since(start), 2*time.Second)
+
+			ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+			start = time.Now()
+			err = tc.f(ctx, u)
+			assert.Error(err)
+			assert.Less(time.Since(start), 2*time.Second)
+		})
+	}
+}
+
+func TestHostLookupsSkipped(t *testing.T) {
+	// the resolver fails every lookup, like during a DNS outage.
+	failing := &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("dns is down")
+		},
+	}
+	original := resolver
+	resolver = failing
+	defer func() { resolver = original }()
+
+	invalidSubnets, err := InvalidSubnetsCtx([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	rejectSelf, err := RejectSelfURLsCtx(nil, []string{"192.0.2.1"})
+	require.NoError(t, err)
+	fs := map[string]ValidURLFuncCtx{
+		"RejectLoopback": RejectLoopbackCtx(),
+		"InvalidSubnets": invalidSubnets,
+		"RejectSelfURLs": rejectSelf,
+	}
+	ips := map[string]string{
+		"RejectLoopback": "https://127.0.0.1/",
+		"InvalidSubnets": "https://10.1.2.3/",
+		"RejectSelfURLs": "https://192.0.2.1/",
+	}
+	for name, f := range fs {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			host, err := url.Parse("https://webhook.example.com/")
+			require.NoError(t, err)
+			ip, err := url.Parse(ips[name])
+			require.NoError(t, err)
+
+			assert.False(HostLookupsSkipped(context.Background()))
+			assert.Error(f(context.Background(), host))
+
+			ctx := withoutHostLookups(context.Background())
+			assert.True(HostLookupsSkipped(ctx))
+			assert.NoError(f(ctx, host))
+			assert.Error(f(ctx, ip))
+		})
+	}
+}
+
+func TestValidatorsValidateContext(t *testing.T) {
+	assert := assert.New(t)
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	var got interface{}
+	vs := Validators{
+		ValidatorFunc(func(Webhook) error { return nil }),
+		ContextValidatorFunc(func(ctx context.Context, _ Webhook) error {
+			got = ctx.Value(ctxKey{})
+			return nil
+		}),
+	}
+	assert.NoError(vs.ValidateContext(ctx, Webhook{}))
+	assert.Equal("value", got)
+
+	got = nil
+	assert.NoError(vs.Validate(Webhook{}))
+	assert.Nil(got)
+}
+
+func TestRejectSelfURLs(t *testing.T) {
+	original := interfaceAddrs
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+			&net.IPNet{IP: net.ParseIP("10.1.2.3"), Mask: net.CIDRMask(16, 32)},
+			&net.IPNet{IP: net.ParseIP("2001:db8::5"), Mask: net.CIDRMask(64, 128)},
+		}, nil
+	}
+	defer func() { interfaceAddrs = original }()
+	detected, err := SelfCIDRs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1/32", "10.1.2.3/32", "2001:db8::5/128"}, detected)
+
+	tcs := []struct {
+		desc               string
+		url                string
+		selfHosts          []string
+		selfCIDRs          []string
+		expectedInitialErr error
+		expectedLatterErr  error
+	}{
+		{
+			desc:              "Self host Failure",
+			url:               "https://Ingest.Xmidt.Example.net:8080/events",
+			selfHosts:         []string{"ingest.xmidt.example.net"},
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:              "Loopback IP Failure",
+			url:               "https://127.0.0.1:8080",
+			selfCIDRs:         detected,
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:              "Loopback host lookup Failure",
+			url:               "https://localhost:8080",
+			selfCIDRs:         detected,
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:              "Interface IP Failure",
+			url:               "https://10.1.2.3",
+			selfCIDRs:         detected,
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:              "Interface IPv6 Failure",
+			url:               "https://[2001:db8::5]",
+			selfCIDRs:         detected,
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:              "Self subnet Failure",
+			url:               "https://10.1.9.9",
+			selfCIDRs:         []string{"10.1.0.0/16"},
+			expectedLatterErr: errSelfURL,
+		},
+		{
+			desc:      "Interface subnet neighbor Success",
+			url:       "https://10.1.2.4",
+			selfHosts: []string{"ingest.xmidt.example.net"},
+			selfCIDRs: detected,
+		},
+		{
+			desc:      "Unrelated host Success",
+			url:       "https://receiver.example.net",
+			selfHosts: []string{"ingest.xmidt.example.net"},
+		},
+		{
+			desc:               "Invalid CIDR Failure",
+			selfCIDRs:          []string{"10.1.0.0//16"},
+			expectedInitialErr: errInvalidSubnet,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			f, err := RejectSelfURLs(tc.selfHosts, tc.selfCIDRs)
+			if tc.expectedInitialErr != nil {
+				assert.ErrorIs(err, tc.expectedInitialErr)
+				return
+			}
+			require.NoError(t, err)
+			u, err := url.Parse(tc.url)
+			require.NoError(t, err)
+			assert.ErrorIs(f(u), tc.expectedLatterErr)
+		})
+	}
+}
@@ -30,11 +30,36 @@ var (
 	}
 	errFailedToBuildValidators    = errors.New("failed to build validators")
 	errFailedToBuildValidURLFuncs = errors.New("failed to build ValidURLFuncs")
+
+	// ErrInvalidTTLConfig is returned by TTLVConfigFromConfig when the TTLConfig
+	// can't be used.
+	ErrInvalidTTLConfig = errors.New("invalid TTL config")
 )
 
+const defaultMaxAlternativeURLs = 32
+
 type ValidatorConfig struct {
-	URL URLVConfig
-	TTL TTLVConfig
+	URL    URLVConfig
+	TTL    TTLVConfig
+	Events EventsVConfig
+
+	// CollectAll, if true, makes the validator built by BuildValidator report
+	// every validation failure instead of only the first, listed in the
+	// "details" of the 400 response.
+	// (Optional). Defaults to false, which fails fast.
+	CollectAll bool
+
+	// FailureURL is the policy for the FailureURL, e.g. to allow http failure URLs
+	// to internal collectors while only allowing https receiver URLs. Its
+	// MaxAlternativeURLs is unused.
+	// (Optional). Defaults to URL.
+	FailureURL *URLVConfig
+
+	// RequireFailureURL, if true, rejects webhooks without a FailureURL, which
+	// leave no way to notify their owner when they're cut off. The FailureURL
+	// is validated as usual.
+	// (Optional). Defaults to false.
+	RequireFailureURL bool
 }
 
 type URLVConfig struct {
@@ -45,6 +70,41 @@ type URLVConfig struct {
 	AllowSpecialUseIPs   bool
 	InvalidHosts         []string
 	InvalidSubnets       []string
+
+	// SelfHosts and SelfCIDRs are the hosts and addresses of the registering
+	// service, which URLs must not point at to avoid event loops.
+	// (Optional). Defaults to allowing URLs to any host.
+	SelfHosts []string
+	SelfCIDRs []string
+
+	// DetectSelfCIDRs adds the IPs of the host's network interfaces to SelfCIDRs.
+	// (Optional). Defaults to false.
+	DetectSelfCIDRs bool
+
+	// MaxAlternativeURLs is the maximum number of Config.AlternativeURLs. A
+	// negative value means no limit.
+	// (Optional). Defaults to 32.
+	MaxAlternativeURLs int
+}
+
+type EventsVConfig struct {
+	// Dialect restricts the regular expressions accepted in Events.
+	// (Optional). Defaults to RegexDialectRE2.
+	Dialect RegexDialect
+
+	// MaxEvents is the maximum number of Events.
+	// (Optional). Defaults to no limit.
+	MaxEvents int
+
+	// MatchBudget caps the time compiling the Events and matching them against
+	// SampleEvents may take, e.g. 5ms, rejecting costlier webhooks. The cost
+	// is measured with wall-clock time, so the budget should be generous.
+	// (Optional). Defaults to 0, which doesn't check the cost.
+	MatchBudget time.Duration
+
+	// SampleEvents are the event types matched against to measure MatchBudget.
+	// (Optional). Defaults to DefaultSampleEvents.
+	SampleEvents []string
 }
 
 type TTLVConfig struct {
@@ -53,30 +113,113 @@ type TTLVConfig struct {
 	Now    func() time.Time
 }
 
+// TTLConfig is the serializable form of TTLVConfig, e.g. decoded from YAML,
+// with durations such as "24h".
+type TTLConfig struct {
+	// MaxTTL is the longest a webhook may be registered for.
+	// (Optional). Defaults to 0, which rejects webhooks with a duration.
+	MaxTTL string
+
+	// Jitter is how far past MaxTTL from now an until may be, and must be
+	// less than MaxTTL.
+	// (Optional). Defaults to 0.
+	Jitter string
+}
+
+// TTLVConfigFromConfig builds a TTLVConfig from the config, with the clock of
+// the until check, or time.Now when now is nil. It fails with
+// ErrInvalidTTLConfig when a duration can't be parsed or is negative, or when
+// Jitter isn't less than MaxTTL.
+func TTLVConfigFromConfig(c TTLConfig, now func() time.Time) (TTLVConfig, error) {
+	var errs []error
+	parse := func(name, value string) time.Duration {
+		if value == "" {
+			return 0
+		}
+		d, err := time.ParseDuration(value)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%w: %s %q isn't a duration", ErrInvalidTTLConfig, name, value))
+		case d < 0:
+			errs = append(errs, fmt.Errorf("%w: %s %q can't be negative", ErrInvalidTTLConfig, name, value))
+		}
+		return d
+	}
+	maxTTL := parse("MaxTTL", c.MaxTTL)
+	jitter := parse("Jitter", c.Jitter)
+	if len(errs) == 0 && jitter > 0 && jitter >= maxTTL {
+		errs = append(errs, fmt.Errorf("%w: Jitter %v must be less than MaxTTL %v", ErrInvalidTTLConfig, jitter, maxTTL))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return TTLVConfig{}, err
+	}
+
+	if now == nil {
+		now = time.Now
+	}
+	return TTLVConfig{
+		Max:    maxTTL,
+		Jitter: jitter,
+		Now:    now,
+	}, nil
+}
+
 // BuildValidURLFuncs translates the configuration into a list of ValidURLFuncs
 // to be run on the webhook.
-func buildValidURLFuncs(config ValidatorConfig) ([]ValidURLFunc, error) {
-	var v []ValidURLFunc
-	v = append(v, GoodURLScheme(config.URL.HTTPSOnly))
-	if !config.URL.AllowLoopback {
-		v = append(v, RejectLoopback())
+func buildValidURLFuncs(config ValidatorConfig) ([]ValidURLFuncCtx, error) {
+	return buildURLFuncs(config.URL)
+}
+
+// buildFailureURLFuncs translates the FailureURL configuration into a list of
+// ValidURLFuncs, falling back to the URL configuration.
+func buildFailureURLFuncs(config ValidatorConfig) ([]ValidURLFuncCtx, error) {
+	if config.FailureURL == nil {
+		return buildURLFuncs(config.URL)
 	}
-	if !config.URL.AllowIP {
-		v = append(v, RejectAllIPs())
+	return buildURLFuncs(*config.FailureURL)
+}
+
+func buildURLFuncs(config URLVConfig) ([]ValidURLFuncCtx, error) {
+	var v []ValidURLFuncCtx
+	// The checks that don't resolve hosts come first, so obviously bad URLs are
+	// rejected without lookups.
+	v = append(v, AdaptValidURLFunc(GoodURLScheme(config.HTTPSOnly)))
+	if !config.AllowIP {
+		v = append(v, AdaptValidURLFunc(RejectAllIPs()))
 	}
-	if !config.URL.AllowSpecialUseHosts {
-		config.URL.InvalidHosts = append(config.URL.InvalidHosts, SpecialUseHosts...)
+	invalidHosts := config.InvalidHosts
+	if !config.AllowSpecialUseHosts {
+		invalidHosts = append(append([]string{}, invalidHosts...), SpecialUseHosts...)
 	}
-	if len(config.URL.InvalidHosts) > 0 {
-		v = append(v, RejectHosts(config.URL.InvalidHosts))
+	if len(invalidHosts) > 0 {
+		v = append(v, AdaptValidURLFunc(RejectHosts(invalidHosts)))
 	}
-	if !config.URL.AllowSpecialUseIPs {
-		config.URL.InvalidSubnets = append(config.URL.InvalidSubnets, SpecialUseIPs...)
+	if !config.AllowLoopback {
+		v = append(v, RejectLoopbackCtx())
 	}
-	if len(config.URL.InvalidSubnets) > 0 {
-		fInvalidSubnets, err := InvalidSubnets(config.URL.InvalidSubnets)
+	selfCIDRs := config.SelfCIDRs
+	if config.DetectSelfCIDRs {
+		detected, err := SelfCIDRs()
 		if err != nil {
-			return nil, fmt.Errorf("%w: %v", errFailedToBuildValidURLFuncs, err)
+			return nil, fmt.Errorf("%w: %w", errFailedToBuildValidURLFuncs, err)
+		}
+		selfCIDRs = append(append([]string{}, selfCIDRs...), detected...)
+	}
+	if len(config.SelfHosts) > 0 || len(selfCIDRs) > 0 {
+		fRejectSelf, err := RejectSelfURLsCtx(config.SelfHosts, selfCIDRs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errFailedToBuildValidURLFuncs, err)
+		}
+		v = append(v, fRejectSelf)
+	}
+	invalidSubnets := config.InvalidSubnets
+	if !config.AllowSpecialUseIPs {
+		invalidSubnets = append(append([]string{}, invalidSubnets...), SpecialUseIPs...)
+	}
+	if len(invalidSubnets) > 0 {
+		fInvalidSubnets, err := InvalidSubnetsCtx(invalidSubnets)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errFailedToBuildValidURLFuncs, err)
 		}
 		v = append(v, fInvalidSubnets)
 	}
@@ -84,32 +227,73 @@ func buildValidURLFuncs(config ValidatorConfig) ([]ValidURLFunc, error) {
 }
 
 // BuildValidators translates the configuration into a list of validators to be run on the
-// webhook.
+// webhook. The structural validators come before the URL validators, which may
+// resolve hosts, so that a webhook failing a structural check is rejected
+// without lookups.
 func BuildValidators(config ValidatorConfig) (Validators, error) {
 	v, err := buildValidURLFuncs(config)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errFailedToBuildValidators, err)
+		return nil, fmt.Errorf("%w: %w", errFailedToBuildValidators, err)
+	}
+	fv, err := buildFailureURLFuncs(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errFailedToBuildValidators, err)
 	}
 
 	vs := Validators{
-		GoodConfigURL(v),
-		GoodFailureURL(v),
-		GoodAlternativeURLs(v),
-		CheckEvents(),
 		CheckDeviceID(),
 		CheckUntilOrDurationExist(),
 	}
+	if config.RequireFailureURL {
+		vs = append(vs, CheckFailureURLExists())
+	}
+	maxAlternativeURLs := config.URL.MaxAlternativeURLs
+	if maxAlternativeURLs == 0 {
+		maxAlternativeURLs = defaultMaxAlternativeURLs
+	}
+	vs = append(vs, CheckMaxAlternativeURLs(maxAlternativeURLs))
+	if config.Events.MaxEvents > 0 {
+		vs = append(vs, CheckMaxEvents(config.Events.MaxEvents))
+	}
+
+	fCheckEvents, err := CheckEventsDialect(config.Events.Dialect)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errFailedToBuildValidators, err)
+	}
+	vs = append(vs, fCheckEvents)
+	if config.Events.MatchBudget > 0 {
+		vs = append(vs, CheckEventsCost(config.Events.MatchBudget, config.Events.SampleEvents))
+	}
+
 	fCheckDuration, err := CheckDuration(config.TTL.Max)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errFailedToBuildValidators, err)
+		return nil, fmt.Errorf("%w: %w", errFailedToBuildValidators, err)
 	}
 	vs = append(vs, fCheckDuration)
 
 	fCheckUntil, err := CheckUntil(config.TTL.Jitter, config.TTL.Max, config.TTL.Now)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", errFailedToBuildValidators, err)
+		return nil, fmt.Errorf("%w: %w", errFailedToBuildValidators, err)
 	}
 	vs = append(vs, fCheckUntil)
 
+	vs = append(vs,
+		GoodConfigURLCtx(v),
+		GoodFailureURLCtx(fv),
+		GoodAlternativeURLsCtx(v),
+	)
+	return vs, nil
+}
+
+// BuildValidator is BuildValidators returning a Validator for HandlerConfig.V,
+// which is an AllValidators when config.CollectAll is set.
+func BuildValidator(config ValidatorConfig) (Validator, error) {
+	vs, err := BuildValidators(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.CollectAll {
+		return AllValidators(vs), nil
+	}
 	return vs, nil
 }
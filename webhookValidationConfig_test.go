@@ -4,8 +4,13 @@
 package ancla
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -139,6 +144,33 @@ func TestBuildValidURLFuncs(t *testing.T) {
 			},
 			expectedErr: errFailedToBuildValidURLFuncs,
 		},
+		{
+			desc: "Self URLs only",
+			config: ValidatorConfig{
+				URL: URLVConfig{
+					AllowLoopback:        true,
+					AllowIP:              true,
+					AllowSpecialUseHosts: true,
+					AllowSpecialUseIPs:   true,
+					SelfHosts:            []string{"ingest.example.net"},
+					DetectSelfCIDRs:      true,
+				},
+			},
+			expectedFuncCount: 2,
+		},
+		{
+			desc: "Self CIDR Failure",
+			config: ValidatorConfig{
+				URL: URLVConfig{
+					AllowLoopback:        true,
+					AllowIP:              true,
+					AllowSpecialUseHosts: true,
+					AllowSpecialUseIPs:   true,
+					SelfCIDRs:            []string{"10.0.0.0//8"},
+				},
+			},
+			expectedErr: errFailedToBuildValidURLFuncs,
+		},
 		{
 			desc: "Build None",
 			config: ValidatorConfig{
@@ -210,9 +242,27 @@ func TestBuildValidators(t *testing.T) {
 			},
 			expectedErr: errFailedToBuildValidators,
 		},
+		{
+			desc: "CheckEventsDialect Failure",
+			config: ValidatorConfig{
+				Events: EventsVConfig{
+					Dialect: "pcre",
+				},
+			},
+			expectedErr: errFailedToBuildValidators,
+		},
 		{
 			desc:              "All Validators Added",
-			expectedFuncCount: 8,
+			expectedFuncCount: 9,
+		},
+		{
+			desc: "Max Events Validator Added",
+			config: ValidatorConfig{
+				Events: EventsVConfig{
+					MaxEvents: 10,
+				},
+			},
+			expectedFuncCount: 10,
 		},
 	}
 	for _, tc := range tcs {
@@ -232,3 +282,341 @@ func TestBuildValidators(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildValidatorsFailureURL(t *testing.T) {
+	httpsOnly := URLVConfig{
+		HTTPSOnly:            true,
+		AllowLoopback:        true,
+		AllowIP:              true,
+		AllowSpecialUseHosts: true,
+		AllowSpecialUseIPs:   true,
+	}
+	allowHTTP := httpsOnly
+	allowHTTP.HTTPSOnly = false
+	tcs := []struct {
+		desc        string
+		failureURL  *URLVConfig
+		webhook     Webhook
+		expectedErr error
+	}{
+		{
+			desc:       "http failure URL allowed",
+			failureURL: &allowHTTP,
+			webhook: Webhook{
+				Config:     DeliveryConfig{URL: "https://receiver.example.com/"},
+				FailureURL: "http://collector.internal/",
+			},
+		},
+		{
+			desc:       "http receiver URL still rejected",
+			failureURL: &allowHTTP,
+			webhook: Webhook{
+				Config:     DeliveryConfig{URL: "http://receiver.example.com/"},
+				FailureURL: "http://collector.internal/",
+			},
+			expectedErr: errInvalidURL,
+		},
+		{
+			desc: "Defaults to the URL config",
+			webhook: Webhook{
+				Config:     DeliveryConfig{URL: "https://receiver.example.com/"},
+				FailureURL: "http://collector.internal/",
+			},
+			expectedErr: errInvalidFailureURL,
+		},
+		{
+			desc:        "Invalid failure subnets Failure",
+			failureURL:  &URLVConfig{InvalidSubnets: []string{"https://localhost:9000"}},
+			expectedErr: errFailedToBuildValidators,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			vs, err := BuildValidators(ValidatorConfig{URL: httpsOnly, FailureURL: tc.failureURL})
+			if errors.Is(tc.expectedErr, errFailedToBuildValidators) {
+				assert.ErrorIs(err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			// only the URL validators, which come last, are run; the webhook isn't
+			// otherwise complete.
+			urlValidators := vs[len(vs)-3:]
+			err = urlValidators.Validate(tc.webhook)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestBuildValidatorsRequireFailureURL(t *testing.T) {
+	urlConfig := URLVConfig{
+		AllowLoopback:        true,
+		AllowIP:              true,
+		AllowSpecialUseHosts: true,
+		AllowSpecialUseIPs:   true,
+	}
+	tcs := []struct {
+		desc              string
+		requireFailureURL bool
+		failureURL        string
+		expectedErr       error
+	}{
+		{
+			desc: "Not required",
+		},
+		{
+			desc:              "Required and missing",
+			requireFailureURL: true,
+			expectedErr:       errFailureURLAbsent,
+		},
+		{
+			desc:              "Required and invalid",
+			requireFailureURL: true,
+			failureURL:        "ftp://collector.example.com/",
+			expectedErr:       errInvalidFailureURL,
+		},
+		{
+			desc:              "Required and valid",
+			requireFailureURL: true,
+			failureURL:        "https://collector.example.com/",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			vs, err := BuildValidators(ValidatorConfig{
+				URL:               urlConfig,
+				TTL:               TTLVConfig{Max: time.Hour},
+				RequireFailureURL: tc.requireFailureURL,
+			})
+			require.NoError(t, err)
+			err = vs.Validate(Webhook{
+				Config:     DeliveryConfig{URL: "https://receiver.example.com/"},
+				FailureURL: tc.failureURL,
+				Events:     []string{"online"},
+				Duration:   time.Minute,
+			})
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestBuildValidatorsMatchBudget(t *testing.T) {
+	urlConfig := URLVConfig{
+		AllowLoopback:        true,
+		AllowIP:              true,
+		AllowSpecialUseHosts: true,
+		AllowSpecialUseIPs:   true,
+	}
+	webhook := Webhook{
+		Config:   DeliveryConfig{URL: "https://receiver.example.com/"},
+		Events:   []string{strings.Repeat("(a|b)?", 100) + "c"},
+		Duration: time.Minute,
+	}
+	tcs := []struct {
+		desc        string
+		events      EventsVConfig
+		expectedErr error
+	}{
+		{
+			desc: "Not checked by default",
+		},
+		{
+			desc: "Over the budget",
+			events: EventsVConfig{
+				MatchBudget:  5 * time.Millisecond,
+				SampleEvents: []string{strings.Repeat("a", 100000)},
+			},
+			expectedErr: errEventsTooCostly,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			vs, err := BuildValidators(ValidatorConfig{
+				URL:    urlConfig,
+				TTL:    TTLVConfig{Max: time.Hour},
+				Events: tc.events,
+			})
+			require.NoError(t, err)
+			assert.ErrorIs(t, vs.Validate(webhook), tc.expectedErr)
+		})
+	}
+}
+
+func TestBuildValidatorsOrder(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	var lookups atomic.Int32
+	original := resolver
+	resolver = &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			lookups.Add(1)
+			return nil, errors.New("no lookups expected")
+		},
+	}
+	defer func() { resolver = original }()
+	// an invalid URL along with structural failures.
+	w := Webhook{Config: DeliveryConfig{URL: "ftp://receiver.example.net/"}}
+
+	vs, err := BuildValidators(ValidatorConfig{})
+	require.NoError(err)
+	err = vs.Validate(w)
+	assert.ErrorIs(err, errUntilDurationAbsent)
+	assert.Zero(lookups.Load(), "the structural validators should reject the webhook before any lookup")
+
+	v, err := BuildValidator(ValidatorConfig{})
+	require.NoError(err)
+	assert.Equal(vs.Validate(w).Error(), v.Validate(w).Error())
+
+	v, err = BuildValidator(ValidatorConfig{CollectAll: true})
+	require.NoError(err)
+	err = v.Validate(w)
+	var ve ValidationErrors
+	require.ErrorAs(err, &ve)
+	assert.ErrorIs(ve[0], errUntilDurationAbsent)
+	assert.ErrorIs(err, errZeroEvents)
+	assert.ErrorIs(ve[len(ve)-1], errInvalidURL)
+
+	_, err = BuildValidator(ValidatorConfig{URL: URLVConfig{InvalidSubnets: []string{"invalid"}}})
+	assert.ErrorIs(err, errFailedToBuildValidators)
+}
+
+func TestBuildValidatorsLimits(t *testing.T) {
+	altURLs := make([]string, defaultMaxAlternativeURLs+1)
+	for i := range altURLs {
+		altURLs[i] = fmt.Sprintf("https://%v.example.net", i)
+	}
+	tcs := []struct {
+		desc        string
+		config      ValidatorConfig
+		webhook     Webhook
+		expectedErr error
+		rejectedErr error
+	}{
+		{
+			desc:        "Default alternative URL limit",
+			webhook:     Webhook{Config: DeliveryConfig{AlternativeURLs: altURLs}},
+			expectedErr: errTooManyAltURLs,
+		},
+		{
+			desc:        "No alternative URL limit",
+			config:      ValidatorConfig{URL: URLVConfig{MaxAlternativeURLs: -1}},
+			webhook:     Webhook{Config: DeliveryConfig{AlternativeURLs: altURLs}},
+			rejectedErr: errTooManyAltURLs,
+		},
+		{
+			desc:        "Events at the limit",
+			config:      ValidatorConfig{Events: EventsVConfig{MaxEvents: 2}},
+			webhook:     Webhook{Events: []string{"online", "offline"}},
+			rejectedErr: errTooManyEvents,
+		},
+		{
+			desc:        "Events over the limit",
+			config:      ValidatorConfig{Events: EventsVConfig{MaxEvents: 2}},
+			webhook:     Webhook{Events: []string{"online", "offline", "reboot"}},
+			expectedErr: errTooManyEvents,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			vs, err := BuildValidators(tc.config)
+			require.NoError(t, err)
+			// the webhooks are otherwise incomplete, so only look for the limit errors.
+			err = vs.ValidateAll(tc.webhook)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(err, tc.expectedErr)
+			}
+			if tc.rejectedErr != nil {
+				assert.NotErrorIs(err, tc.rejectedErr)
+			}
+		})
+	}
+}
+
+func TestTTLVConfigFromConfig(t *testing.T) {
+	tcs := []struct {
+		desc           string
+		config         string
+		expectedMax    time.Duration
+		expectedJitter time.Duration
+		expectedErr    string
+	}{
+		{
+			desc:           "Durations",
+			config:         `{"MaxTTL": "24h", "Jitter": "30s"}`,
+			expectedMax:    24 * time.Hour,
+			expectedJitter: 30 * time.Second,
+		},
+		{
+			desc:        "Only MaxTTL",
+			config:      `{"MaxTTL": "5m"}`,
+			expectedMax: 5 * time.Minute,
+		},
+		{
+			desc:   "Empty",
+			config: `{}`,
+		},
+		{
+			desc:        "Unparseable MaxTTL",
+			config:      `{"MaxTTL": "a day"}`,
+			expectedErr: `MaxTTL "a day" isn't a duration`,
+		},
+		{
+			desc:        "Negative Jitter",
+			config:      `{"MaxTTL": "1h", "Jitter": "-1s"}`,
+			expectedErr: `Jitter "-1s" can't be negative`,
+		},
+		{
+			desc:        "Jitter as long as MaxTTL",
+			config:      `{"MaxTTL": "1m", "Jitter": "60s"}`,
+			expectedErr: "Jitter 1m0s must be less than MaxTTL 1m0s",
+		},
+		{
+			desc:        "Jitter without MaxTTL",
+			config:      `{"Jitter": "1s"}`,
+			expectedErr: "Jitter 1s must be less than MaxTTL 0s",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			var config TTLConfig
+			require.NoError(json.Unmarshal([]byte(tc.config), &config))
+
+			ttl, err := TTLVConfigFromConfig(config, nil)
+			if tc.expectedErr != "" {
+				assert.ErrorIs(err, ErrInvalidTTLConfig)
+				assert.ErrorContains(err, tc.expectedErr)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.expectedMax, ttl.Max)
+			assert.Equal(tc.expectedJitter, ttl.Jitter)
+			assert.NotNil(ttl.Now)
+		})
+	}
+}
+
+func TestTTLVConfigFromConfigClock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	now := func() time.Time { return getRefTime() }
+	ttl, err := TTLVConfigFromConfig(TTLConfig{MaxTTL: "1h"}, now)
+	require.NoError(err)
+
+	checkUntil, err := CheckUntil(ttl.Jitter, ttl.Max, ttl.Now)
+	require.NoError(err)
+	assert.ErrorIs(checkUntil(Webhook{Until: getRefTime().Add(2 * time.Hour)}), errInvalidUntil)
+	assert.NoError(checkUntil(Webhook{Until: getRefTime().Add(30 * time.Minute)}))
+}
@@ -4,6 +4,7 @@
 package ancla
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -20,6 +21,10 @@ var (
 	errUntilDurationAbsent = errors.New("until and duration are both absent")
 	errInvalidTTL          = errors.New("TTL must be non-negative")
 	errInvalidJitter       = errors.New("jitter must be non-negative")
+	errTooManyAltURLs      = errors.New("too many alternative URLs")
+	errTooManyEvents       = errors.New("too many events")
+	errFailureURLAbsent    = errors.New("failure URL is required to notify the owner when the webhook is cut off")
+	errEventsTooCostly     = errors.New("events are too costly to evaluate")
 )
 
 // Validator is a WebhookValidator that allows access to the Validate function.
@@ -28,16 +33,48 @@ type Validator interface {
 }
 
 // Validators is a WebhookValidator that ensures the webhook is valid with
-// each validator in the list.
+// each validator in the list. The validators run in list order, and Validate
+// stops at the first failure.
 type Validators []Validator
 
+// AllValidators is a Validators that runs every validator in the list and
+// reports all of their failures rather than only the first, i.e. Validate is
+// Validators.ValidateAll.
+type AllValidators Validators
+
+// ValidationErrors is every failure found by Validators.ValidateAll. Each error
+// is also matched by errors.Is and errors.As.
+type ValidationErrors []error
+
 // ValidatorFunc is a WebhookValidator that takes Webhooks and validates them
 // against functions.
 type ValidatorFunc func(Webhook) error
 
+// ContextValidator is a Validator that can also validate with a context, e.g.
+// to cancel the host lookups of a validation along with its request.
+type ContextValidator interface {
+	Validator
+	ValidateContext(ctx context.Context, w Webhook) error
+}
+
+// ContextValidatorFunc is a ContextValidator that validates webhooks with a function.
+type ContextValidatorFunc func(context.Context, Webhook) error
+
 // ValidURLFunc takes URLs and ensures they are valid.
 type ValidURLFunc func(*url.URL) error
 
+// ValidURLFuncCtx takes URLs and ensures they are valid, giving up once the
+// context is done.
+type ValidURLFuncCtx func(context.Context, *url.URL) error
+
+// AdaptValidURLFunc adapts a ValidURLFunc into a ValidURLFuncCtx that ignores
+// the context.
+func AdaptValidURLFunc(f ValidURLFunc) ValidURLFuncCtx {
+	return func(_ context.Context, u *url.URL) error {
+		return f(u)
+	}
+}
+
 // Validate runs the given webhook through each validator in the validators list.
 // It returns as soon as the webhook is considered invalid and returns nil if the
 // webhook is valid.
@@ -51,12 +88,85 @@ func (vs Validators) Validate(w Webhook) error {
 	return nil
 }
 
+// ValidateContext is Validate with the context given to the ContextValidators
+// in the list.
+func (vs Validators) ValidateContext(ctx context.Context, w Webhook) error {
+	for _, v := range vs {
+		err := validateContext(ctx, v, w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateAll runs the given webhook through every validator in the validators
+// list and returns a ValidationErrors with all of the failures, or nil if the
+// webhook is valid.
+func (vs Validators) ValidateAll(w Webhook) error {
+	return vs.ValidateAllContext(context.Background(), w)
+}
+
+// ValidateAllContext is ValidateAll with the context given to the
+// ContextValidators in the list.
+func (vs Validators) ValidateAllContext(ctx context.Context, w Webhook) error {
+	var errs ValidationErrors
+	for _, v := range vs {
+		if err := validateContext(ctx, v, w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate runs the given webhook through every validator in the list.
+func (vs AllValidators) Validate(w Webhook) error {
+	return Validators(vs).ValidateAll(w)
+}
+
+// ValidateContext is Validate with the context given to the ContextValidators
+// in the list.
+func (vs AllValidators) ValidateContext(ctx context.Context, w Webhook) error {
+	return Validators(vs).ValidateAllContext(ctx, w)
+}
+
+// Error joins the messages of the failures with newlines, as errors.Join does.
+func (ve ValidationErrors) Error() string {
+	return errors.Join(ve...).Error()
+}
+
+// Unwrap returns the failures.
+func (ve ValidationErrors) Unwrap() []error {
+	return ve
+}
+
 // Validate runs the function and returns the result. This allows any ValidatorFunc to implement
 // the Validator interface.
 func (vf ValidatorFunc) Validate(w Webhook) error {
 	return vf(w)
 }
 
+// Validate runs the function with a background context.
+func (vf ContextValidatorFunc) Validate(w Webhook) error {
+	return vf(context.Background(), w)
+}
+
+// ValidateContext runs the function and returns the result.
+func (vf ContextValidatorFunc) ValidateContext(ctx context.Context, w Webhook) error {
+	return vf(ctx, w)
+}
+
+// validateContext gives the context to the validator if it is a ContextValidator.
+func validateContext(ctx context.Context, v Validator, w Webhook) error {
+	if cv, ok := v.(ContextValidator); ok {
+		return cv.ValidateContext(ctx, w)
+	}
+	return v.Validate(w)
+}
+
 // AlwaysValid doesn't check anything in the webhook and never returns an error.
 func AlwaysValid() ValidatorFunc {
 	return func(w Webhook) error {
@@ -81,6 +191,84 @@ func CheckEvents() ValidatorFunc {
 	}
 }
 
+// CheckEventsDialect is CheckEvents with the values restricted to the given
+// regex dialect. CheckEventsDialect returns an error if the dialect is unknown.
+func CheckEventsDialect(dialect RegexDialect) (ValidatorFunc, error) {
+	switch dialect {
+	case "", RegexDialectRE2:
+		return CheckEvents(), nil
+	case RegexDialectPortable:
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownRegexDialect, dialect)
+	}
+	return func(w Webhook) error {
+		if len(w.Events) == 0 {
+			return errZeroEvents
+		}
+		for _, e := range w.Events {
+			if err := checkPortable(e); err != nil {
+				return fmt.Errorf("%w: %q: %w", errEventsUnparseable, e, err)
+			}
+			if _, err := regexp.Compile(e); err != nil {
+				return errEventsUnparseable
+			}
+		}
+		return nil
+	}, nil
+}
+
+// matchCostTrials is the number of times CheckEventsCost evaluates the events
+// before rejecting them, so that a pause of the process doesn't reject them.
+const matchCostTrials = 3
+
+// DefaultSampleEvents are representative event types CheckEventsCost matches
+// the events against when no samples are given.
+var DefaultSampleEvents = []string{
+	"device-status/mac:112233445566/online",
+	"device-status/mac:112233445566/offline",
+	"device-status/mac:112233445566/fully-manageable",
+	"device-status/mac:112233445566/operational",
+	"node-change",
+	"event:device-status/mac:112233445566/reboot-pending/some-timestamp",
+}
+
+// CheckEventsCost ensures compiling the Events and matching them against the
+// samples takes no more than the budget. Since wall-clock time varies, the
+// events are evaluated up to 3 times and only rejected when every trial
+// exceeds the budget. Events that don't compile are left to CheckEvents. A
+// budget of zero or less means no limit, and no samples means DefaultSampleEvents.
+func CheckEventsCost(budget time.Duration, samples []string) ValidatorFunc {
+	if budget <= 0 {
+		return AlwaysValid()
+	}
+	if len(samples) == 0 {
+		samples = DefaultSampleEvents
+	}
+	return func(w Webhook) error {
+		var fastest time.Duration
+		for trial := 0; trial < matchCostTrials; trial++ {
+			start := time.Now()
+			for _, e := range w.Events {
+				re, err := regexp.Compile(e)
+				if err != nil {
+					continue
+				}
+				for _, s := range samples {
+					re.MatchString(s)
+				}
+			}
+			elapsed := time.Since(start)
+			if elapsed <= budget {
+				return nil
+			}
+			if trial == 0 || elapsed < fastest {
+				fastest = elapsed
+			}
+		}
+		return fmt.Errorf("%w: %v exceeds the budget of %v", errEventsTooCostly, fastest, budget)
+	}
+}
+
 // CheckDeviceID ensures that the DeviceIDs are able to parse into regex.
 func CheckDeviceID() ValidatorFunc {
 	return func(w Webhook) error {
@@ -94,6 +282,45 @@ func CheckDeviceID() ValidatorFunc {
 	}
 }
 
+// CheckFailureURLExists ensures the FailureURL is set, so the owner can be told
+// when the webhook is cut off. Whether it's valid is up to GoodFailureURL.
+func CheckFailureURLExists() ValidatorFunc {
+	return func(w Webhook) error {
+		if w.FailureURL == "" {
+			return errFailureURLAbsent
+		}
+		return nil
+	}
+}
+
+// CheckMaxAlternativeURLs ensures there are no more than max
+// Config.AlternativeURLs. A max of zero or less means no limit.
+func CheckMaxAlternativeURLs(max int) ValidatorFunc {
+	if max <= 0 {
+		return AlwaysValid()
+	}
+	return func(w Webhook) error {
+		if n := len(w.Config.AlternativeURLs); n > max {
+			return fmt.Errorf("%w: %v exceeds the limit of %v", errTooManyAltURLs, n, max)
+		}
+		return nil
+	}
+}
+
+// CheckMaxEvents ensures there are no more than max Events. A max of zero or
+// less means no limit.
+func CheckMaxEvents(max int) ValidatorFunc {
+	if max <= 0 {
+		return AlwaysValid()
+	}
+	return func(w Webhook) error {
+		if n := len(w.Events); n > max {
+			return fmt.Errorf("%w: %v exceeds the limit of %v", errTooManyEvents, n, max)
+		}
+		return nil
+	}
+}
+
 // CheckDuration ensures that 0 <= Duration <= ttl. Duration returns an error
 // if a negative value is given.
 func CheckDuration(maxTTL time.Duration) (ValidatorFunc, error) {
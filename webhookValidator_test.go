@@ -6,6 +6,7 @@ package ancla
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,6 +65,233 @@ func TestCheckEvents(t *testing.T) {
 	}
 }
 
+func TestCheckEventsDialect(t *testing.T) {
+	tcs := []struct {
+		desc        string
+		dialect     RegexDialect
+		events      []string
+		expectedErr error
+	}{
+		{
+			desc:   "RE2 accepts dollar Success",
+			events: []string{"device-status$"},
+		},
+		{
+			desc:        "RE2 rejects lookahead Failure",
+			dialect:     RegexDialectRE2,
+			events:      []string{"online(?!-test)"},
+			expectedErr: errEventsUnparseable,
+		},
+		{
+			desc:    "Portable Success",
+			dialect: RegexDialectPortable,
+			events:  []string{"^device-status/.*\\z", "online|offline"},
+		},
+		{
+			desc:        "Portable empty Failure",
+			dialect:     RegexDialectPortable,
+			expectedErr: errZeroEvents,
+		},
+		{
+			desc:        "Portable lookahead Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"online(?=-test)"},
+			expectedErr: errRegexLookaround,
+		},
+		{
+			desc:        "Portable negative lookahead Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"online(?!-test)"},
+			expectedErr: errRegexLookaround,
+		},
+		{
+			desc:        "Portable lookbehind Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"(?<=device-)status"},
+			expectedErr: errRegexLookaround,
+		},
+		{
+			desc:        "Portable negative lookbehind Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"(?<!device-)status"},
+			expectedErr: errRegexLookaround,
+		},
+		{
+			desc:        "Portable numbered backreference Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"(on|off)line-\\1"},
+			expectedErr: errRegexBackreference,
+		},
+		{
+			desc:        "Portable named backreference Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"(?P<s>on|off)line-\\k<s>"},
+			expectedErr: errRegexBackreference,
+		},
+		{
+			desc:        "Portable dollar Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{"online", "device-status$"},
+			expectedErr: errRegexDollar,
+		},
+		{
+			desc:        "Portable unparseable Failure",
+			dialect:     RegexDialectPortable,
+			events:      []string{`\M`},
+			expectedErr: errEventsUnparseable,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			check, err := CheckEventsDialect(tc.dialect)
+			require.NoError(err)
+			err = check(Webhook{Events: tc.events})
+			assert.True(errors.Is(err, tc.expectedErr),
+				fmt.Errorf("error [%v] doesn't contain error [%v] in its err chain",
+					err, tc.expectedErr),
+			)
+		})
+	}
+
+	t.Run("Unknown dialect Failure", func(t *testing.T) {
+		_, err := CheckEventsDialect("pcre")
+		assert.ErrorIs(t, err, errUnknownRegexDialect)
+	})
+}
+
+func TestCheckMaxAlternativeURLs(t *testing.T) {
+	urls := []string{"https://a.example.net", "https://b.example.net", "https://c.example.net"}
+	tcs := []struct {
+		desc        string
+		max         int
+		urls        []string
+		expectedErr error
+	}{
+		{
+			desc: "No alternative URLs Success",
+			max:  2,
+		},
+		{
+			desc: "At the limit Success",
+			max:  3,
+			urls: urls,
+		},
+		{
+			desc:        "Over the limit Failure",
+			max:         2,
+			urls:        urls,
+			expectedErr: errTooManyAltURLs,
+		},
+		{
+			desc: "Zero limit Success",
+			urls: urls,
+		},
+		{
+			desc: "Negative limit Success",
+			max:  -1,
+			urls: urls,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := CheckMaxAlternativeURLs(tc.max)(Webhook{Config: DeliveryConfig{AlternativeURLs: tc.urls}})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestCheckEventsCost(t *testing.T) {
+	samples := []string{strings.Repeat("a", 100000)}
+	expensive := strings.Repeat("(a|b)?", 100) + "c"
+	tcs := []struct {
+		desc        string
+		budget      time.Duration
+		samples     []string
+		events      []string
+		expectedErr error
+	}{
+		{
+			desc:    "Cheap events Success",
+			budget:  5 * time.Millisecond,
+			samples: samples,
+			events:  []string{"online", "device-status/.*"},
+		},
+		{
+			desc:        "Expensive events Failure",
+			budget:      5 * time.Millisecond,
+			samples:     samples,
+			events:      []string{"online", expensive},
+			expectedErr: errEventsTooCostly,
+		},
+		{
+			desc:   "Default samples Success",
+			budget: 5 * time.Millisecond,
+			events: []string{"device-status/.*/online"},
+		},
+		{
+			desc:    "Unparseable events are skipped",
+			budget:  5 * time.Millisecond,
+			samples: samples,
+			events:  []string{"[["},
+		},
+		{
+			desc:    "Zero budget Success",
+			samples: samples,
+			events:  []string{expensive},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := CheckEventsCost(tc.budget, tc.samples)(Webhook{Events: tc.events})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestCheckMaxEvents(t *testing.T) {
+	events := []string{"online", "offline", "reboot"}
+	tcs := []struct {
+		desc        string
+		max         int
+		events      []string
+		expectedErr error
+	}{
+		{
+			desc: "No events Success",
+			max:  1,
+		},
+		{
+			desc:   "At the limit Success",
+			max:    3,
+			events: events,
+		},
+		{
+			desc:        "Over the limit Failure",
+			max:         2,
+			events:      events,
+			expectedErr: errTooManyEvents,
+		},
+		{
+			desc:   "Zero limit Success",
+			events: events,
+		},
+		{
+			desc:   "Negative limit Success",
+			max:    -1,
+			events: events,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := CheckMaxEvents(tc.max)(Webhook{Events: tc.events})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
 func TestCheckDeviceID(t *testing.T) {
 	tcs := []struct {
 		desc        string
@@ -239,6 +467,12 @@ func TestCheckUntil(t *testing.T) {
 	}
 }
 
+func TestCheckFailureURLExists(t *testing.T) {
+	assert := assert.New(t)
+	assert.ErrorIs(CheckFailureURLExists()(Webhook{}), errFailureURLAbsent)
+	assert.NoError(CheckFailureURLExists()(Webhook{FailureURL: "https://collector.example.com/"}))
+}
+
 func TestCheckUntilOrDurationExist(t *testing.T) {
 	tcs := []struct {
 		desc        string